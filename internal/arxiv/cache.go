@@ -0,0 +1,380 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package arxiv
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mtreilly/arc-arxiv/internal/fsutil"
+)
+
+// Kinds of requests the response cache keeps, each with its own time to
+// live. Other requests, such as PDF downloads and harvesting, are never
+// cached.
+const (
+	KindSearch  = "search"
+	KindArticle = "article"
+	KindListing = "listing"
+)
+
+// CacheKinds lists the cached request kinds.
+var CacheKinds = []string{KindSearch, KindArticle, KindListing}
+
+// DefaultTTLs returns how long a cached response of each kind is used
+// without asking arXiv again. Listings change once a day and paper
+// metadata rarely, but new search results appear all the time.
+func DefaultTTLs() map[string]time.Duration {
+	return map[string]time.Duration{
+		KindSearch:  time.Hour,
+		KindArticle: 24 * time.Hour,
+		KindListing: time.Hour,
+	}
+}
+
+// Cache stores arXiv API responses on disk, one file per request. A fresh
+// response is served without a request; an expired one is revalidated with
+// its ETag or Last-Modified date when the server gave one. If arXiv cannot
+// be reached, an expired response is served anyway and reported as stale
+// (see WithCacheReport).
+type Cache struct {
+	dir  string
+	ttls map[string]time.Duration
+	now  func() time.Time
+}
+
+// NewCache returns a cache kept in dir. Kinds missing from ttls take the
+// default; a zero or negative TTL makes every response of that kind
+// revalidate.
+func NewCache(dir string, ttls map[string]time.Duration) *Cache {
+	c := &Cache{dir: dir, ttls: DefaultTTLs(), now: time.Now}
+	for kind, ttl := range ttls {
+		c.ttls[kind] = ttl
+	}
+	return c
+}
+
+// sharedCache is used by every Client made by NewClient; nil disables
+// caching.
+var (
+	sharedCacheMu sync.Mutex
+	sharedCache   *Cache
+)
+
+// SetCache sets the response cache used by clients made from now on. Nil
+// disables caching.
+func SetCache(c *Cache) {
+	sharedCacheMu.Lock()
+	defer sharedCacheMu.Unlock()
+	sharedCache = c
+}
+
+func currentCache() *Cache {
+	sharedCacheMu.Lock()
+	defer sharedCacheMu.Unlock()
+	return sharedCache
+}
+
+// cacheEntry is one stored response.
+type cacheEntry struct {
+	Kind         string    `json:"kind"`
+	URL          string    `json:"url"`
+	StoredAt     time.Time `json:"stored_at"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	ContentType  string    `json:"content_type,omitempty"`
+	Body         []byte    `json:"body"`
+}
+
+// cacheKey normalizes a request URL: the host is lowercased and the query
+// parameters are sorted, so equal queries share an entry.
+func cacheKey(u *url.URL) string {
+	n := *u
+	n.Host = strings.ToLower(n.Host)
+	n.RawQuery = n.Query().Encode()
+	n.Fragment = ""
+	return n.String()
+}
+
+func (c *Cache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:16])+".json")
+}
+
+func (c *Cache) load(key string) *cacheEntry {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil
+	}
+	var e cacheEntry
+	if json.Unmarshal(data, &e) != nil || e.URL != key {
+		return nil
+	}
+	return &e
+}
+
+func (c *Cache) store(key string, e *cacheEntry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return err
+	}
+	return fsutil.WriteFile(c.path(key), data, 0o644)
+}
+
+func (c *Cache) fresh(e *cacheEntry) bool {
+	return c.now().Sub(e.StoredAt) < c.ttls[e.Kind]
+}
+
+// CacheStats summarizes the responses of one kind in the cache.
+type CacheStats struct {
+	Kind    string
+	Entries int
+	Fresh   int
+	Bytes   int64
+	Oldest  time.Time
+	Newest  time.Time
+}
+
+// Stats summarizes the cache per kind, in the order of CacheKinds.
+func (c *Cache) Stats() ([]CacheStats, error) {
+	byKind := make(map[string]*CacheStats)
+	stats := make([]CacheStats, len(CacheKinds))
+	for i, kind := range CacheKinds {
+		stats[i].Kind = kind
+		byKind[kind] = &stats[i]
+	}
+
+	err := c.walk(func(path string, info os.FileInfo) error {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		var e cacheEntry
+		if json.Unmarshal(data, &e) != nil {
+			return nil
+		}
+		s := byKind[e.Kind]
+		if s == nil {
+			return nil
+		}
+		s.Entries++
+		s.Bytes += info.Size()
+		if c.fresh(&e) {
+			s.Fresh++
+		}
+		if s.Oldest.IsZero() || e.StoredAt.Before(s.Oldest) {
+			s.Oldest = e.StoredAt
+		}
+		if e.StoredAt.After(s.Newest) {
+			s.Newest = e.StoredAt
+		}
+		return nil
+	})
+	return stats, err
+}
+
+// Clear removes cached responses and returns how many it removed. With
+// expiredOnly, fresh responses are kept.
+func (c *Cache) Clear(expiredOnly bool) (int, error) {
+	removed := 0
+	err := c.walk(func(path string, _ os.FileInfo) error {
+		if expiredOnly {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil
+			}
+			var e cacheEntry
+			if json.Unmarshal(data, &e) == nil && c.fresh(&e) {
+				return nil
+			}
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		removed++
+		return nil
+	})
+	return removed, err
+}
+
+// walk calls fn for every entry file in the cache directory.
+func (c *Cache) walk(fn func(path string, info os.FileInfo) error) error {
+	entries, err := os.ReadDir(c.dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read cache: %w", err)
+	}
+	for _, de := range entries {
+		if de.IsDir() || filepath.Ext(de.Name()) != ".json" {
+			continue
+		}
+		info, err := de.Info()
+		if err != nil {
+			continue
+		}
+		if err := fn(filepath.Join(c.dir, de.Name()), info); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type cacheContextKey int
+
+const (
+	kindKey cacheContextKey = iota
+	skipKey
+	reportKey
+)
+
+// withKind marks the requests made with ctx as cacheable of the given kind.
+func withKind(ctx context.Context, kind string) context.Context {
+	return context.WithValue(ctx, kindKey, kind)
+}
+
+// SkipCache makes the requests made with ctx go to arXiv even when a fresh
+// response is cached, for callers that need the current state, such as
+// update. The answer still refreshes the cache.
+func SkipCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipKey, true)
+}
+
+// CacheReport records stale responses served to requests made with a
+// context from WithCacheReport.
+type CacheReport struct {
+	mu    sync.Mutex
+	stale time.Time
+}
+
+// WithCacheReport returns a context whose requests report to the returned
+// CacheReport.
+func WithCacheReport(ctx context.Context) (context.Context, *CacheReport) {
+	r := &CacheReport{}
+	return context.WithValue(ctx, reportKey, r), r
+}
+
+// Stale reports whether a stale response was served because arXiv could
+// not be reached, and when the oldest such response was stored.
+func (r *CacheReport) Stale() (time.Time, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.stale, !r.stale.IsZero()
+}
+
+func (r *CacheReport) markStale(storedAt time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.stale.IsZero() || storedAt.Before(r.stale) {
+		r.stale = storedAt
+	}
+}
+
+// cachingTransport answers cacheable requests from the cache and stores
+// the responses next fetches.
+type cachingTransport struct {
+	cache *Cache
+	next  http.RoundTripper
+}
+
+func (t *cachingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	kind, _ := ctx.Value(kindKey).(string)
+	if kind == "" || req.Method != http.MethodGet {
+		return t.next.RoundTrip(req)
+	}
+
+	key := cacheKey(req.URL)
+	cached := t.cache.load(key)
+	skip, _ := ctx.Value(skipKey).(bool)
+	if cached != nil && !skip && t.cache.fresh(cached) {
+		return cached.response(req), nil
+	}
+
+	r := req
+	if cached != nil && !skip && (cached.ETag != "" || cached.LastModified != "") {
+		r = req.Clone(ctx)
+		if cached.ETag != "" {
+			r.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			r.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	resp, err := t.next.RoundTrip(r)
+	if err != nil {
+		if cached != nil && !skip && ctx.Err() == nil {
+			if report, ok := ctx.Value(reportKey).(*CacheReport); ok {
+				report.markStale(cached.StoredAt)
+			}
+			return cached.response(req), nil
+		}
+		return nil, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached != nil && r != req:
+		_ = resp.Body.Close()
+		cached.StoredAt = t.cache.now()
+		_ = t.cache.store(key, cached)
+		return cached.response(req), nil
+	case resp.StatusCode != http.StatusOK:
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	// A failure to store only costs a request next time.
+	_ = t.cache.store(key, &cacheEntry{
+		Kind:         kind,
+		URL:          key,
+		StoredAt:     t.cache.now(),
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		ContentType:  resp.Header.Get("Content-Type"),
+		Body:         body,
+	})
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
+	return resp, nil
+}
+
+// response rebuilds an HTTP response from the entry.
+func (e *cacheEntry) response(req *http.Request) *http.Response {
+	header := make(http.Header)
+	if e.ContentType != "" {
+		header.Set("Content-Type", e.ContentType)
+	}
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package arxiv
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// cachedListingClient returns a client reading listings from a server that
+// counts its requests and answers If-None-Match "v1" with 304.
+func cachedListingClient(t *testing.T) (*Client, *Cache, *httptest.Server, *[]string) {
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Header.Get("If-None-Match"))
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		fmt.Fprint(w, listingFeed)
+	}))
+	t.Cleanup(srv.Close)
+
+	cache := NewCache(t.TempDir(), map[string]time.Duration{KindListing: time.Hour})
+	c := testClient(t, WithCache(cache), WithListingBaseURL(srv.URL))
+	return c, cache, srv, &requests
+}
+
+func TestCacheFreshAndRevalidate(t *testing.T) {
+	c, cache, _, requests := cachedListingClient(t)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		listing, err := c.Listing(ctx, "cs.LG")
+		if err != nil || len(listing.Entries) != 2 {
+			t.Fatalf("Listing #%d: %v", i+1, err)
+		}
+	}
+	if len(*requests) != 1 {
+		t.Fatalf("requests = %q, want the second listing from the cache", *requests)
+	}
+
+	// Expired: revalidated with the ETag and served from the cache on 304.
+	start := time.Now()
+	cache.now = func() time.Time { return start.Add(2 * time.Hour) }
+	listing, err := c.Listing(ctx, "cs.LG")
+	if err != nil || len(listing.Entries) != 2 {
+		t.Fatalf("revalidated Listing: %v", err)
+	}
+	if len(*requests) != 2 || (*requests)[1] != `"v1"` {
+		t.Errorf("requests = %q, want a conditional request", *requests)
+	}
+	if _, err := c.Listing(ctx, "cs.LG"); err != nil || len(*requests) != 2 {
+		t.Errorf("after 304 the entry should be fresh again: %v, %q", err, *requests)
+	}
+
+	// SkipCache asks the server unconditionally.
+	if _, err := c.Listing(SkipCache(ctx), "cs.LG"); err != nil || len(*requests) != 3 || (*requests)[2] != "" {
+		t.Errorf("SkipCache: %v, %q", err, *requests)
+	}
+}
+
+func TestCacheServesStaleWhenOffline(t *testing.T) {
+	c, cache, srv, _ := cachedListingClient(t)
+	if _, err := c.Listing(context.Background(), "cs.LG"); err != nil {
+		t.Fatal(err)
+	}
+	srv.Close()
+
+	ctx, report := WithCacheReport(context.Background())
+	if _, err := c.Listing(ctx, "cs.LG"); err != nil {
+		t.Fatal(err)
+	}
+	if _, stale := report.Stale(); stale {
+		t.Error("fresh response reported as stale")
+	}
+
+	start := time.Now()
+	cache.now = func() time.Time { return start.Add(2 * time.Hour) }
+	listing, err := c.Listing(ctx, "cs.LG")
+	if err != nil || len(listing.Entries) != 2 {
+		t.Fatalf("offline Listing: %v", err)
+	}
+	if storedAt, stale := report.Stale(); !stale || storedAt.After(start) {
+		t.Errorf("Stale() = %v, %v", storedAt, stale)
+	}
+
+	if _, err := c.Listing(context.Background(), "math"); err == nil {
+		t.Error("expected an error for an uncached listing while offline")
+	}
+}
+
+func TestCacheOnlyCachesKinds(t *testing.T) {
+	n := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n++
+		fmt.Fprint(w, "pdf")
+	}))
+	defer srv.Close()
+
+	c := testClient(t, WithCache(NewCache(t.TempDir(), nil)))
+	for i := 0; i < 2; i++ {
+		resp, err := c.get(context.Background(), srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = resp.Body.Close()
+	}
+	if n != 2 {
+		t.Errorf("got %d requests, want 2", n)
+	}
+}
+
+func TestCacheKey(t *testing.T) {
+	a, _ := url.Parse("http://Export.arXiv.org/api/query?start=0&search_query=all:x#frag")
+	b, _ := url.Parse("http://export.arxiv.org/api/query?search_query=all%3Ax&start=0")
+	if cacheKey(a) != cacheKey(b) {
+		t.Errorf("keys differ: %s vs %s", cacheKey(a), cacheKey(b))
+	}
+}
+
+func TestCacheStatsAndClear(t *testing.T) {
+	cache := NewCache(t.TempDir(), map[string]time.Duration{KindSearch: time.Minute})
+	now := time.Now()
+	entries := map[string]*cacheEntry{
+		"http://a/1": {Kind: KindSearch, StoredAt: now, Body: []byte("one")},
+		"http://a/2": {Kind: KindSearch, StoredAt: now.Add(-time.Hour), Body: []byte("two")},
+		"http://a/3": {Kind: KindArticle, StoredAt: now.Add(-time.Hour), Body: []byte("three")},
+	}
+	for key, e := range entries {
+		e.URL = key
+		if err := cache.store(key, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stats, err := cache.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := stats[0]; s.Kind != KindSearch || s.Entries != 2 || s.Fresh != 1 || s.Bytes == 0 {
+		t.Errorf("search stats = %+v", s)
+	}
+	if s := stats[1]; s.Kind != KindArticle || s.Entries != 1 || s.Fresh != 1 {
+		t.Errorf("article stats = %+v", s)
+	}
+	if s := stats[2]; s.Kind != KindListing || s.Entries != 0 {
+		t.Errorf("listing stats = %+v", s)
+	}
+
+	if n, err := cache.Clear(true); err != nil || n != 1 {
+		t.Errorf("Clear(expired) = %d, %v", n, err)
+	}
+	if cache.load("http://a/2") != nil || cache.load("http://a/1") == nil {
+		t.Error("Clear(expired) removed the wrong entries")
+	}
+	if n, err := cache.Clear(false); err != nil || n != 2 {
+		t.Errorf("Clear = %d, %v", n, err)
+	}
+}
@@ -5,14 +5,16 @@ package arxiv
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
-	"os"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/mtreilly/arc-arxiv/internal/fsutil"
 	"github.com/mtreilly/goarxiv"
 )
 
@@ -22,7 +24,12 @@ type Author struct {
 	Affiliation string `yaml:"affiliation,omitempty"`
 }
 
-// ArxivMeta represents paper metadata stored locally.
+// ArxivMeta represents paper metadata stored locally. PinnedVersion is set
+// when a specific version was requested (for example "fetch 2304.00067v2").
+// Tags, Collections and the reading fields (Status, Priority, Rating) are
+// the user's own and never come from arXiv. An empty Status means unread.
+// License, the ACM and MSC classes, the report number and the submitter are
+// only provided by OAI-PMH harvesting (see Harvest).
 type ArxivMeta struct {
 	ID              string   `yaml:"id"`
 	ArxivID         string   `yaml:"arxiv_id"`
@@ -39,44 +46,104 @@ type ArxivMeta struct {
 	Comment         string   `yaml:"comment,omitempty"`
 	JournalRef      string   `yaml:"journal_ref,omitempty"`
 	DOI             string   `yaml:"doi,omitempty"`
+	ReportNo        string   `yaml:"report_no,omitempty"`
+	License         string   `yaml:"license,omitempty"`
+	ACMClass        string   `yaml:"acm_class,omitempty"`
+	MSCClass        string   `yaml:"msc_class,omitempty"`
+	Submitter       string   `yaml:"submitter,omitempty"`
 	Version         int      `yaml:"version"`
+	PinnedVersion   int      `yaml:"pinned_version,omitempty"`
 	FetchedAt       string   `yaml:"fetched_at"`
+	Tags            []string `yaml:"tags,omitempty"`
+	Collections     []string `yaml:"collections,omitempty"`
+	Status          string   `yaml:"status,omitempty"`
+	Priority        string   `yaml:"priority,omitempty"`
+	Rating          int      `yaml:"rating,omitempty"`
 }
 
-// Client wraps goarxiv.Client with additional functionality.
+// Client wraps goarxiv.Client with additional functionality. Make one
+// with NewClient.
 type Client struct {
-	client *goarxiv.Client
+	client         *goarxiv.Client
+	http           *http.Client
+	downloadBases  []string
+	oaiBaseURL     string
+	listingBaseURL string
 }
 
-// NewClient creates a new arxiv client with sensible defaults.
-func NewClient() (*Client, error) {
+// NewClient creates a new arxiv client. Without options it talks to arXiv
+// itself. All its requests, to the API as well as for PDFs, go through the
+// scheduler shared by every client (see SetLimits) unless WithScheduler
+// gives another; queries and listings are answered from the response cache
+// set with SetCache unless WithCache gives another.
+func NewClient(opts ...Option) (*Client, error) {
+	o := &options{
+		apiBaseURL:      DefaultAPIBaseURL,
+		downloadBases:   []string{DefaultDownloadBaseURL},
+		oaiBaseURL:      DefaultOAIBaseURL,
+		listingBaseURL:  DefaultListingBaseURL,
+		connectTimeout:  DefaultConnectTimeout,
+		responseTimeout: DefaultResponseTimeout,
+		scheduler:       shared,
+	}
+	for _, opt := range opts {
+		if err := opt(o); err != nil {
+			return nil, err
+		}
+	}
+	if !o.cacheSet {
+		o.cache = currentCache()
+	}
+
+	hc := o.client()
 	c, err := goarxiv.New(
 		goarxiv.WithUserAgent("arc-arxiv/1.0"),
+		goarxiv.WithBaseURL(o.apiBaseURL),
+		goarxiv.WithHTTPClient(hc),
+		goarxiv.WithRateLimiter(unpaced{}),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("create arxiv client: %w", err)
 	}
-	return &Client{client: c}, nil
+	return &Client{
+		client:         c,
+		http:           hc,
+		downloadBases:  o.downloadBases,
+		oaiBaseURL:     o.oaiBaseURL,
+		listingBaseURL: o.listingBaseURL,
+	}, nil
 }
 
 // FetchArticle retrieves a single article by arXiv ID.
 func (c *Client) FetchArticle(ctx context.Context, id string) (*ArxivMeta, error) {
-	article, err := c.client.GetByID(ctx, id)
+	article, err := c.client.GetByID(withKind(ctx, KindArticle), id)
 	if err != nil {
 		return nil, fmt.Errorf("fetch article %s: %w", id, err)
 	}
 	return articleToMeta(article), nil
 }
 
-// FetchArticles retrieves multiple articles by their IDs.
+// articlesPerRequest is how many IDs FetchArticles asks for at once: the
+// API answers an id_list query with at most max_results entries, which
+// defaults to 10 and goarxiv leaves unset.
+const articlesPerRequest = 10
+
+// FetchArticles retrieves multiple articles by their IDs, in as few
+// requests as the API allows. IDs arXiv does not know are missing from the
+// result; it is not in the order of ids.
 func (c *Client) FetchArticles(ctx context.Context, ids []string) ([]*ArxivMeta, error) {
-	articles, err := c.client.GetByIDs(ctx, ids)
-	if err != nil {
-		return nil, fmt.Errorf("fetch articles: %w", err)
-	}
-	metas := make([]*ArxivMeta, 0, len(articles))
-	for _, article := range articles {
-		metas = append(metas, articleToMeta(article))
+	metas := make([]*ArxivMeta, 0, len(ids))
+	for start := 0; start < len(ids); start += articlesPerRequest {
+		chunk := ids[start:min(start+articlesPerRequest, len(ids))]
+		articles, err := c.client.GetByIDs(withKind(ctx, KindArticle), chunk)
+		if err != nil {
+			return nil, fmt.Errorf("fetch articles: %w", err)
+		}
+		for _, article := range articles {
+			if meta := articleToMeta(article); meta != nil {
+				metas = append(metas, meta)
+			}
+		}
 	}
 	return metas, nil
 }
@@ -152,7 +219,7 @@ func (c *Client) Search(ctx context.Context, query string, opts *SearchOptions)
 		}
 	}
 
-	results, err := c.client.Search(ctx, builder.Build(), searchOpts)
+	results, err := c.client.Search(withKind(ctx, KindSearch), builder.Build(), searchOpts)
 	if err != nil {
 		return nil, 0, fmt.Errorf("search: %w", err)
 	}
@@ -165,6 +232,9 @@ func (c *Client) Search(ctx context.Context, query string, opts *SearchOptions)
 	return metas, results.TotalResults, nil
 }
 
+// pdfMagic is the header every PDF file starts with.
+const pdfMagic = "%PDF-"
+
 // DownloadProgress is called during PDF download with progress info.
 type DownloadProgress func(downloaded, total int64)
 
@@ -175,41 +245,149 @@ func (c *Client) DownloadPDF(ctx context.Context, id string, destPath string, pr
 		return fmt.Errorf("invalid arxiv id: %w", err)
 	}
 
-	pdfURL := fmt.Sprintf("https://arxiv.org/pdf/%s.pdf", normalizedID)
+	return c.download(ctx, "/pdf/"+normalizedID+".pdf", func(resp *http.Response) error {
+		return savePDF(resp.Body, destPath, resp.ContentLength, progress)
+	})
+}
 
-	req, err := http.NewRequestWithContext(ctx, "GET", pdfURL, nil)
+// DownloadPDFVersion downloads a specific version of an article's PDF,
+// regardless of any version suffix already present in id.
+func (c *Client) DownloadPDFVersion(ctx context.Context, id string, version int, destPath string, progress DownloadProgress) error {
+	normalizedID, err := NormalizeArxivID(id)
 	if err != nil {
-		return err
+		return fmt.Errorf("invalid arxiv id: %w", err)
+	}
+	if version <= 0 {
+		return fmt.Errorf("invalid version %d for %s", version, id)
+	}
+	base, _ := SplitVersion(normalizedID)
+	return c.DownloadPDF(ctx, fmt.Sprintf("%sv%d", base, version), destPath, progress)
+}
+
+// DownloadSource downloads the e-print (the source files the authors
+// submitted) for an article to destPath, as served by arXiv: usually a
+// gzipped tar archive, sometimes a single gzipped file or a PDF. A version
+// suffix in id selects that version.
+func (c *Client) DownloadSource(ctx context.Context, id string, destPath string, progress DownloadProgress) error {
+	normalizedID, err := NormalizeArxivID(id)
+	if err != nil {
+		return fmt.Errorf("invalid arxiv id: %w", err)
+	}
+
+	return c.download(ctx, "/e-print/"+normalizedID, func(resp *http.Response) error {
+		if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/html") {
+			return fmt.Errorf("no source available for %s", normalizedID)
+		}
+		return saveFile(resp.Body, destPath, resp.ContentLength, progress, func(head []byte) error {
+			if len(head) == 0 {
+				return fmt.Errorf("downloaded source is empty")
+			}
+			return nil
+		})
+	})
+}
+
+// download requests path from the download site, then from each mirror in
+// turn while the request fails with a network or server error, and hands
+// the first response to save.
+func (c *Client) download(ctx context.Context, path string, save func(*http.Response) error) error {
+	var err error
+	for _, base := range c.downloadBases {
+		var resp *http.Response
+		resp, err = c.get(ctx, base+path)
+		if err == nil {
+			defer func() { _ = resp.Body.Close() }()
+			return save(resp)
+		}
+		if ctx.Err() != nil || !failover(err) {
+			return err
+		}
+	}
+	return err
+}
+
+// failover reports whether a failed download is worth trying on a mirror:
+// anything but the site saying the file does not exist or may not be had.
+func failover(err error) bool {
+	var se *StatusError
+	if errors.As(err, &se) {
+		return se.Code >= 500 || se.Code == http.StatusTooManyRequests
+	}
+	return true
+}
+
+// StatusError reports an HTTP response other than 200 OK.
+type StatusError struct {
+	Code   int
+	Status string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("HTTP %d: %s", e.Code, e.Status)
+}
+
+// get issues a GET request and returns the response if it succeeded. The
+// caller must close the body.
+func (c *Client) get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
 	}
 	req.Header.Set("User-Agent", "arc-arxiv/1.0")
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, &StatusError{Code: resp.StatusCode, Status: resp.Status}
+	}
+	return resp, nil
+}
+
+// savePDF streams a PDF to destPath atomically. Nothing appears at destPath
+// unless the body is complete, matches the expected size (when known), and
+// starts with a PDF header; an existing file is left untouched on failure.
+func savePDF(body io.Reader, destPath string, size int64, progress DownloadProgress) error {
+	return saveFile(body, destPath, size, progress, func(head []byte) error {
+		if string(head) != pdfMagic {
+			return fmt.Errorf("downloaded file is not a PDF")
+		}
+		return nil
+	})
+}
+
+// saveFile streams body to destPath atomically, reporting progress when the
+// size is known. check is given the first bytes of the complete file (up to
+// len(pdfMagic)) and can reject it before it is committed.
+func saveFile(body io.Reader, destPath string, size int64, progress DownloadProgress, check func(head []byte) error) error {
+	f, err := fsutil.Create(destPath, 0o644)
 	if err != nil {
 		return err
 	}
-	defer func() { _ = resp.Body.Close() }()
+	defer f.Abort()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, resp.Status)
+	var w io.Writer = f
+	if progress != nil && size > 0 {
+		w = io.MultiWriter(f, &progressWriter{total: size, cb: progress})
 	}
 
-	f, err := os.Create(destPath)
+	n, err := io.Copy(w, body)
 	if err != nil {
 		return err
 	}
-	defer func() { _ = f.Close() }()
+	if size > 0 && n != size {
+		return fmt.Errorf("incomplete download: got %d of %d bytes", n, size)
+	}
 
-	if progress != nil && resp.ContentLength > 0 {
-		pw := &progressWriter{
-			total: resp.ContentLength,
-			cb:    progress,
-		}
-		_, err = io.Copy(io.MultiWriter(f, pw), resp.Body)
-	} else {
-		_, err = io.Copy(f, resp.Body)
+	head := make([]byte, len(pdfMagic))
+	k, _ := f.ReadAt(head, 0)
+	if err := check(head[:k]); err != nil {
+		return err
 	}
 
-	return err
+	return f.Commit()
 }
 
 type progressWriter struct {
@@ -321,9 +499,11 @@ func MetaToArticle(meta *ArxivMeta) *goarxiv.Article {
 }
 
 var (
-	oldIDPattern = regexp.MustCompile(`^[a-z-]+/\d{7}(v\d+)?$`)
-	newIDPattern = regexp.MustCompile(`^\d{4}\.\d{4,5}(v\d+)?$`)
-	urlPattern   = regexp.MustCompile(`arxiv\.org/(?:abs|pdf)/([a-z-]+/\d{7}|\d{4}\.\d{4,5})(v\d+)?(?:\.pdf)?`)
+	versionSuffix = regexp.MustCompile(`v(\d+)$`)
+	oldIDPattern  = regexp.MustCompile(`^[a-z-]+/\d{7}(v\d+)?$`)
+	newIDPattern  = regexp.MustCompile(`^\d{4}\.\d{4,5}(v\d+)?$`)
+	oldIDDirName  = regexp.MustCompile(`^([a-z-]+)-(\d{7}(?:v\d+)?)$`)
+	urlPattern    = regexp.MustCompile(`arxiv\.org/(?:abs|pdf)/([a-z-]+/\d{7}|\d{4}\.\d{4,5})(v\d+)?(?:\.pdf)?`)
 )
 
 // NormalizeArxivID extracts and validates an arXiv ID from various input formats.
@@ -347,6 +527,42 @@ func NormalizeArxivID(input string) (string, error) {
 	return "", fmt.Errorf("invalid arXiv ID or URL: %s", input)
 }
 
+// SplitVersion separates a normalized arXiv ID into its base ID and version
+// suffix. The version is 0 when the ID carries no suffix.
+func SplitVersion(id string) (base string, version int) {
+	m := versionSuffix.FindStringSubmatchIndex(id)
+	if m == nil {
+		return id, 0
+	}
+	v, err := strconv.Atoi(id[m[2]:m[3]])
+	if err != nil {
+		return id, 0
+	}
+	return id[:m[0]], v
+}
+
+// DirName maps a normalized arXiv ID to a name that is safe to use as a
+// single path element. Old-style IDs contain a slash ("hep-th/9901001"),
+// which becomes a hyphen ("hep-th-9901001"); other IDs are returned as-is.
+func DirName(id string) string {
+	if oldIDPattern.MatchString(id) {
+		return strings.Replace(id, "/", "-", 1)
+	}
+	return id
+}
+
+// IDFromDirName reverses DirName, recovering the arXiv ID a library
+// directory was named after. ok is false if name is not such a directory.
+func IDFromDirName(name string) (id string, ok bool) {
+	if m := oldIDDirName.FindStringSubmatch(name); m != nil {
+		return m[1] + "/" + m[2], true
+	}
+	if newIDPattern.MatchString(name) {
+		return name, true
+	}
+	return "", false
+}
+
 // IsValidArxivID checks if the input is a valid arXiv ID.
 func IsValidArxivID(input string) bool {
 	_, err := NormalizeArxivID(input)
@@ -4,6 +4,8 @@
 package arxiv
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -173,6 +175,60 @@ func TestIsValidArxivID(t *testing.T) {
 	}
 }
 
+func TestSplitVersion(t *testing.T) {
+	tests := []struct {
+		id          string
+		wantBase    string
+		wantVersion int
+	}{
+		{"2304.00067", "2304.00067", 0},
+		{"2304.00067v2", "2304.00067", 2},
+		{"2304.00067v12", "2304.00067", 12},
+		{"hep-th/9901001", "hep-th/9901001", 0},
+		{"hep-th/9901001v3", "hep-th/9901001", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			base, version := SplitVersion(tt.id)
+			if base != tt.wantBase || version != tt.wantVersion {
+				t.Errorf("SplitVersion(%q) = (%q, %d), want (%q, %d)", tt.id, base, version, tt.wantBase, tt.wantVersion)
+			}
+		})
+	}
+}
+
+func TestDirName(t *testing.T) {
+	tests := []struct {
+		id  string
+		dir string
+	}{
+		{"2304.00067", "2304.00067"},
+		{"2304.00067v2", "2304.00067v2"},
+		{"hep-th/9901001", "hep-th-9901001"},
+		{"cond-mat/0102536v1", "cond-mat-0102536v1"},
+		{"math/0309136", "math-0309136"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			if got := DirName(tt.id); got != tt.dir {
+				t.Errorf("DirName(%q) = %q, want %q", tt.id, got, tt.dir)
+			}
+			id, ok := IDFromDirName(tt.dir)
+			if !ok || id != tt.id {
+				t.Errorf("IDFromDirName(%q) = %q, %v; want %q, true", tt.dir, id, ok, tt.id)
+			}
+		})
+	}
+
+	for _, name := range []string{"hep-th", "notes", "hep-th-990100", "2304.00067.bak"} {
+		if id, ok := IDFromDirName(name); ok {
+			t.Errorf("IDFromDirName(%q) = %q, want not ok", name, id)
+		}
+	}
+}
+
 func TestArticleToMeta(t *testing.T) {
 	published := time.Date(2023, 4, 1, 0, 0, 0, 0, time.UTC)
 	updated := time.Date(2023, 4, 15, 0, 0, 0, 0, time.UTC)
@@ -764,6 +820,61 @@ func TestProgressWriter_ZeroTotal(t *testing.T) {
 	}
 }
 
+func TestSavePDF(t *testing.T) {
+	const pdf = "%PDF-1.4 fake pdf content"
+
+	t.Run("writes complete PDF", func(t *testing.T) {
+		dir := t.TempDir()
+		dest := filepath.Join(dir, "paper.pdf")
+
+		var last int64
+		err := savePDF(strings.NewReader(pdf), dest, int64(len(pdf)), func(downloaded, total int64) {
+			last = downloaded
+		})
+		if err != nil {
+			t.Fatalf("savePDF: %v", err)
+		}
+		data, _ := os.ReadFile(dest)
+		if string(data) != pdf {
+			t.Errorf("content = %q", data)
+		}
+		if last != int64(len(pdf)) {
+			t.Errorf("progress reported %d bytes, want %d", last, len(pdf))
+		}
+		entries, _ := os.ReadDir(dir)
+		if len(entries) != 1 {
+			t.Errorf("temporary files left behind: %d entries", len(entries))
+		}
+	})
+
+	t.Run("rejects short body and keeps existing file", func(t *testing.T) {
+		dest := filepath.Join(t.TempDir(), "paper.pdf")
+		if err := os.WriteFile(dest, []byte("previous"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		err := savePDF(strings.NewReader(pdf), dest, int64(len(pdf)+100), nil)
+		if err == nil || !strings.Contains(err.Error(), "incomplete download") {
+			t.Fatalf("err = %v, want incomplete download", err)
+		}
+		data, _ := os.ReadFile(dest)
+		if string(data) != "previous" {
+			t.Errorf("existing file clobbered: %q", data)
+		}
+	})
+
+	t.Run("rejects non-PDF content", func(t *testing.T) {
+		dest := filepath.Join(t.TempDir(), "paper.pdf")
+		err := savePDF(strings.NewReader("<html>Too many requests</html>"), dest, -1, nil)
+		if err == nil {
+			t.Fatal("expected error for HTML body")
+		}
+		if _, err := os.Stat(dest); !os.IsNotExist(err) {
+			t.Error("destination should not exist after rejected download")
+		}
+	})
+}
+
 func TestAuthor_EmptyName(t *testing.T) {
 	article := &goarxiv.Article{
 		ID:      "2304.00067",
@@ -0,0 +1,145 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package arxiv
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DefaultListingBaseURL serves arXiv's daily announcements as RSS, one feed
+// per category or archive.
+const DefaultListingBaseURL = "https://rss.arxiv.org/rss"
+
+// Announcement types of listing entries, as arXiv reports them.
+const (
+	AnnounceNew          = "new"
+	AnnounceCross        = "cross"
+	AnnounceReplace      = "replace"
+	AnnounceReplaceCross = "replace-cross"
+)
+
+// ListingEntry is one paper in a daily announcement.
+type ListingEntry struct {
+	Meta *ArxivMeta
+	// Type is one of the Announce constants.
+	Type string
+}
+
+// Listing is the latest announcement of one or more categories, in the
+// order arXiv lists it.
+type Listing struct {
+	Categories []string
+	// Date is the announcement date, YYYY-MM-DD, or empty if unknown.
+	Date    string
+	Entries []ListingEntry
+}
+
+var categoryPattern = regexp.MustCompile(`^[A-Za-z-]+(\.[A-Za-z-]+)?$`)
+
+// Listing fetches the latest daily announcement of the given categories
+// ("cs.LG") or archives ("hep-th", "math"). There is no announcement on
+// weekends and holidays; the listing then has no entries.
+func (c *Client) Listing(ctx context.Context, categories ...string) (*Listing, error) {
+	if len(categories) == 0 {
+		return nil, fmt.Errorf("no category given")
+	}
+	for _, cat := range categories {
+		if !categoryPattern.MatchString(cat) {
+			return nil, fmt.Errorf("invalid category %q (e.g., cs.LG, hep-th, math)", cat)
+		}
+	}
+
+	resp, err := c.get(withKind(ctx, KindListing), c.listingBaseURL+"/"+url.PathEscape(strings.Join(categories, "+")))
+	if err != nil {
+		return nil, fmt.Errorf("fetch listing: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var feed rssListing
+	if err := xml.NewDecoder(resp.Body).Decode(&feed); err != nil {
+		return nil, fmt.Errorf("parse listing: %w", err)
+	}
+
+	listing := &Listing{Categories: categories}
+	if t, err := time.Parse("Mon, 2 Jan 2006 15:04:05 -0700", strings.TrimSpace(feed.Channel.PubDate)); err == nil {
+		listing.Date = t.Format("2006-01-02")
+	}
+	for _, item := range feed.Channel.Items {
+		if e, ok := item.entry(); ok {
+			listing.Entries = append(listing.Entries, e)
+		}
+	}
+	return listing, nil
+}
+
+type rssListing struct {
+	Channel struct {
+		PubDate string    `xml:"pubDate"`
+		Items   []rssItem `xml:"item"`
+	} `xml:"channel"`
+}
+
+type rssItem struct {
+	Title        string   `xml:"title"`
+	Link         string   `xml:"link"`
+	Description  string   `xml:"description"`
+	GUID         string   `xml:"guid"`
+	Categories   []string `xml:"category"`
+	AnnounceType string   `xml:"announce_type"`
+	Rights       string   `xml:"rights"`
+	Creator      string   `xml:"creator"`
+}
+
+// listingIDPattern finds the versioned ID at the start of an item
+// description ("arXiv:2403.12345v1 Announce Type: new").
+var listingIDPattern = regexp.MustCompile(`arXiv:(\S+?v\d+)`)
+
+func (item *rssItem) entry() (ListingEntry, bool) {
+	versioned := ""
+	if m := listingIDPattern.FindStringSubmatch(item.Description); m != nil {
+		versioned = m[1]
+	} else {
+		versioned = strings.TrimPrefix(strings.TrimSpace(item.GUID), "oai:arXiv.org:")
+	}
+	id, err := NormalizeArxivID(versioned)
+	if err != nil {
+		return ListingEntry{}, false
+	}
+	base, version := SplitVersion(id)
+
+	abstract := item.Description
+	if _, after, ok := strings.Cut(abstract, "Abstract:"); ok {
+		abstract = after
+	}
+
+	meta := &ArxivMeta{
+		ID:         "paper-" + base,
+		ArxivID:    base,
+		Title:      clean(item.Title),
+		SourceType: "arxiv",
+		URL:        "https://arxiv.org/abs/" + id,
+		PDFURL:     "https://arxiv.org/pdf/" + id,
+		Authors:    splitRawAuthors(item.Creator),
+		Abstract:   clean(abstract),
+		Categories: item.Categories,
+		Version:    version,
+		License:    clean(item.Rights),
+		FetchedAt:  time.Now().Format(time.RFC3339),
+	}
+	if len(item.Categories) > 0 {
+		meta.PrimaryCategory = item.Categories[0]
+	}
+
+	kind := strings.TrimSpace(item.AnnounceType)
+	if kind == "" {
+		kind = AnnounceNew
+	}
+	return ListingEntry{Meta: meta, Type: kind}, true
+}
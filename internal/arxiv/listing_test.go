@@ -0,0 +1,97 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package arxiv
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+const listingFeed = `<?xml version='1.0' encoding='UTF-8'?>
+<rss xmlns:arxiv="http://arxiv.org/schemas/atom" xmlns:dc="http://purl.org/dc/elements/1.1/" version="2.0">
+<channel>
+<title>cs.LG updates on arXiv.org</title>
+<pubDate>Fri, 7 Mar 2025 00:00:00 -0500</pubDate>
+<item>
+<title>Learning Things</title>
+<link>https://arxiv.org/abs/2503.04001</link>
+<description>arXiv:2503.04001v1 Announce Type: new
+Abstract: We learn
+  things.</description>
+<guid isPermaLink="false">oai:arXiv.org:2503.04001v1</guid>
+<category>cs.LG</category>
+<category>stat.ML</category>
+<pubDate>Fri, 07 Mar 2025 00:00:00 -0500</pubDate>
+<arxiv:announce_type>new</arxiv:announce_type>
+<dc:rights>http://creativecommons.org/licenses/by/4.0/</dc:rights>
+<dc:creator>Ada Lovelace, Alan Turing (Manchester)</dc:creator>
+</item>
+<item>
+<title>Old Physics</title>
+<link>https://arxiv.org/abs/hep-th/9901001</link>
+<description>arXiv:hep-th/9901001v3 Announce Type: replace-cross
+Abstract: Revised.</description>
+<guid isPermaLink="false">oai:arXiv.org:hep-th/9901001v3</guid>
+<category>hep-th</category>
+<category>cs.LG</category>
+<arxiv:announce_type>replace-cross</arxiv:announce_type>
+<dc:creator>A. Author</dc:creator>
+</item>
+<item>
+<title>Broken</title>
+<description>No identifier here</description>
+</item>
+</channel>
+</rss>`
+
+func TestListing(t *testing.T) {
+	var path string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		fmt.Fprint(w, listingFeed)
+	}))
+	defer srv.Close()
+
+	c := testClient(t, WithListingBaseURL(srv.URL+"/"))
+	listing, err := c.Listing(context.Background(), "cs.LG", "stat.ML")
+	if err != nil {
+		t.Fatalf("Listing: %v", err)
+	}
+	if path != "/cs.LG+stat.ML" {
+		t.Errorf("path = %s", path)
+	}
+	if listing.Date != "2025-03-07" || len(listing.Entries) != 2 {
+		t.Fatalf("listing = %+v", listing)
+	}
+
+	e := listing.Entries[0]
+	m := e.Meta
+	if e.Type != AnnounceNew || m.ArxivID != "2503.04001" || m.Version != 1 || m.Title != "Learning Things" {
+		t.Errorf("entry = %s %+v", e.Type, m)
+	}
+	if m.Abstract != "We learn things." || m.PrimaryCategory != "cs.LG" || m.PDFURL != "https://arxiv.org/pdf/2503.04001v1" {
+		t.Errorf("meta = %+v", m)
+	}
+	wantAuthors := []Author{{Name: "Ada Lovelace"}, {Name: "Alan Turing", Affiliation: "Manchester"}}
+	if !reflect.DeepEqual(m.Authors, wantAuthors) {
+		t.Errorf("authors = %+v", m.Authors)
+	}
+
+	if e := listing.Entries[1]; e.Type != AnnounceReplaceCross || e.Meta.ArxivID != "hep-th/9901001" || e.Meta.Version != 3 {
+		t.Errorf("replacement = %s %+v", e.Type, e.Meta)
+	}
+}
+
+func TestListingInvalidCategory(t *testing.T) {
+	c := testClient(t)
+	for _, cats := range [][]string{nil, {"cs.LG/../x"}, {"cs LG"}} {
+		if _, err := c.Listing(context.Background(), cats...); err == nil {
+			t.Errorf("Listing(%q): expected error", cats)
+		}
+	}
+}
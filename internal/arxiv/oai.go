@@ -0,0 +1,359 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package arxiv
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultOAIBaseURL is arXiv's OAI-PMH endpoint.
+const DefaultOAIBaseURL = "https://oaipmh.arxiv.org/oai"
+
+// OAI-PMH metadata formats understood by Harvest. arXivRaw carries the
+// submitter and the full version history; arXiv carries structured author
+// names with affiliations.
+const (
+	FormatArxivRaw = "arXivRaw"
+	FormatArxiv    = "arXiv"
+)
+
+// HarvestOptions selects the records Harvest lists.
+type HarvestOptions struct {
+	// From and Until bound the record datestamps, as YYYY-MM-DD. Either may
+	// be empty.
+	From  string
+	Until string
+	// Set restricts the harvest to an archive, such as "cs", "math" or
+	// "physics:hep-th".
+	Set string
+	// Format is the metadata format, FormatArxivRaw (the default) or
+	// FormatArxiv.
+	Format string
+	// ResumptionToken continues an earlier, interrupted harvest. The other
+	// options are then ignored, as the token encodes them.
+	ResumptionToken string
+}
+
+// HarvestPage reports progress after each page of records. Token is the
+// resumption token for the next page, empty after the last one; Total is the
+// size of the complete list when the server reports it, else -1.
+type HarvestPage struct {
+	Records int
+	Deleted int
+	Total   int
+	Token   string
+}
+
+// HarvestError is returned when a harvest stops part way. Token resumes it
+// from the page that failed; it is empty if the first page failed.
+type HarvestError struct {
+	Token string
+	Err   error
+}
+
+func (e *HarvestError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *HarvestError) Unwrap() error {
+	return e.Err
+}
+
+// Harvest lists records through OAI-PMH ListRecords, following resumption
+// tokens until the list is complete. Each record is passed to fn, and page
+// (which may be nil) is called after every page. An error from either stops
+// the harvest with a HarvestError whose token repeats the current page.
+// Deleted records are skipped. An empty result is not an error.
+func (c *Client) Harvest(ctx context.Context, opts HarvestOptions, fn func(*ArxivMeta) error, page func(HarvestPage) error) error {
+	format := opts.Format
+	if format == "" {
+		format = FormatArxivRaw
+	}
+	if format != FormatArxivRaw && format != FormatArxiv {
+		return fmt.Errorf("unknown metadata format %q (use %s or %s)", format, FormatArxivRaw, FormatArxiv)
+	}
+
+	params := url.Values{"verb": {"ListRecords"}}
+	if opts.ResumptionToken != "" {
+		params.Set("resumptionToken", opts.ResumptionToken)
+	} else {
+		params.Set("metadataPrefix", format)
+		for key, value := range map[string]string{"from": opts.From, "until": opts.Until, "set": opts.Set} {
+			if value != "" {
+				params.Set(key, value)
+			}
+		}
+	}
+
+	token := opts.ResumptionToken
+	progress := HarvestPage{Total: -1}
+	for {
+		resp, err := c.oaiRequest(ctx, params)
+		if err != nil {
+			return &HarvestError{Token: token, Err: err}
+		}
+		if resp.Error != nil {
+			if resp.Error.Code == "noRecordsMatch" {
+				return nil
+			}
+			return &HarvestError{Token: token, Err: fmt.Errorf("OAI-PMH error %s: %s", resp.Error.Code, strings.TrimSpace(resp.Error.Message))}
+		}
+
+		for _, rec := range resp.ListRecords.Records {
+			if rec.Header.Status == "deleted" {
+				progress.Deleted++
+				continue
+			}
+			meta := rec.meta()
+			if meta == nil {
+				continue
+			}
+			if err := fn(meta); err != nil {
+				return &HarvestError{Token: token, Err: err}
+			}
+			progress.Records++
+		}
+
+		rt := resp.ListRecords.Token
+		next := strings.TrimSpace(rt.Value)
+		if rt.CompleteListSize != "" {
+			if n, err := strconv.Atoi(rt.CompleteListSize); err == nil {
+				progress.Total = n
+			}
+		}
+		progress.Token = next
+		if page != nil {
+			if err := page(progress); err != nil {
+				return &HarvestError{Token: token, Err: err}
+			}
+		}
+		if next == "" {
+			return nil
+		}
+		token = next
+		params = url.Values{"verb": {"ListRecords"}, "resumptionToken": {token}}
+	}
+}
+
+// oaiRequest performs one OAI-PMH request. The scheduler waits and retries
+// when the server answers 503 with a Retry-After delay, as arXiv does
+// between pages.
+func (c *Client) oaiRequest(ctx context.Context, params url.Values) (*oaiResponse, error) {
+	resp, err := c.get(ctx, c.oaiBaseURL+"?"+params.Encode())
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var out oaiResponse
+	if err := xml.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("parse OAI-PMH response: %w", err)
+	}
+	return &out, nil
+}
+
+type oaiResponse struct {
+	Error *struct {
+		Code    string `xml:"code,attr"`
+		Message string `xml:",chardata"`
+	} `xml:"error"`
+	ListRecords struct {
+		Records []oaiRecord `xml:"record"`
+		Token   struct {
+			Value            string `xml:",chardata"`
+			CompleteListSize string `xml:"completeListSize,attr"`
+		} `xml:"resumptionToken"`
+	} `xml:"ListRecords"`
+}
+
+type oaiRecord struct {
+	Header struct {
+		Identifier string   `xml:"identifier"`
+		Datestamp  string   `xml:"datestamp"`
+		Sets       []string `xml:"setSpec"`
+		Status     string   `xml:"status,attr"`
+	} `xml:"header"`
+	Metadata struct {
+		Raw   *oaiArxivRaw `xml:"arXivRaw"`
+		Arxiv *oaiArxiv    `xml:"arXiv"`
+	} `xml:"metadata"`
+}
+
+// oaiCommon holds the fields both metadata formats share.
+type oaiCommon struct {
+	ID         string `xml:"id"`
+	Title      string `xml:"title"`
+	Categories string `xml:"categories"`
+	Comments   string `xml:"comments"`
+	ReportNo   string `xml:"report-no"`
+	ACMClass   string `xml:"acm-class"`
+	MSCClass   string `xml:"msc-class"`
+	JournalRef string `xml:"journal-ref"`
+	DOI        string `xml:"doi"`
+	License    string `xml:"license"`
+	Abstract   string `xml:"abstract"`
+}
+
+type oaiArxivRaw struct {
+	oaiCommon
+	Submitter string `xml:"submitter"`
+	Versions  []struct {
+		Version string `xml:"version,attr"`
+		Date    string `xml:"date"`
+	} `xml:"version"`
+	Authors string `xml:"authors"`
+}
+
+type oaiArxiv struct {
+	oaiCommon
+	Created string `xml:"created"`
+	Updated string `xml:"updated"`
+	Authors []struct {
+		Keyname     string   `xml:"keyname"`
+		Forenames   string   `xml:"forenames"`
+		Suffix      string   `xml:"suffix"`
+		Affiliation []string `xml:"affiliation"`
+	} `xml:"authors>author"`
+}
+
+// meta maps a record to ArxivMeta. FetchedAt is the time of the harvest.
+func (r *oaiRecord) meta() *ArxivMeta {
+	var common *oaiCommon
+	meta := &ArxivMeta{SourceType: "arxiv", FetchedAt: time.Now().Format(time.RFC3339)}
+
+	switch {
+	case r.Metadata.Raw != nil:
+		raw := r.Metadata.Raw
+		common = &raw.oaiCommon
+		meta.Submitter = clean(raw.Submitter)
+		meta.Authors = splitRawAuthors(raw.Authors)
+		meta.Version = len(raw.Versions)
+		if len(raw.Versions) > 0 {
+			meta.Published = oaiTime(raw.Versions[0].Date)
+			meta.Updated = oaiTime(raw.Versions[len(raw.Versions)-1].Date)
+			if v := strings.TrimPrefix(raw.Versions[len(raw.Versions)-1].Version, "v"); v != "" {
+				if n, err := strconv.Atoi(v); err == nil {
+					meta.Version = n
+				}
+			}
+		}
+	case r.Metadata.Arxiv != nil:
+		a := r.Metadata.Arxiv
+		common = &a.oaiCommon
+		meta.Published = oaiTime(a.Created)
+		meta.Updated = oaiTime(a.Updated)
+		if meta.Updated == "" {
+			meta.Updated = meta.Published
+		}
+		for _, au := range a.Authors {
+			name := clean(strings.Join([]string{au.Forenames, au.Keyname, au.Suffix}, " "))
+			author := Author{Name: name}
+			if len(au.Affiliation) > 0 {
+				author.Affiliation = clean(strings.Join(au.Affiliation, "; "))
+			}
+			meta.Authors = append(meta.Authors, author)
+		}
+	default:
+		return nil
+	}
+
+	meta.ArxivID = strings.TrimSpace(common.ID)
+	if meta.ArxivID == "" {
+		meta.ArxivID = strings.TrimPrefix(r.Header.Identifier, "oai:arXiv.org:")
+	}
+	if meta.ArxivID == "" {
+		return nil
+	}
+	meta.ID = "paper-" + meta.ArxivID
+	meta.Title = clean(common.Title)
+	meta.Abstract = clean(common.Abstract)
+	meta.Categories = strings.Fields(common.Categories)
+	if len(meta.Categories) > 0 {
+		meta.PrimaryCategory = meta.Categories[0]
+	}
+	meta.Comment = clean(common.Comments)
+	meta.ReportNo = clean(common.ReportNo)
+	meta.ACMClass = clean(common.ACMClass)
+	meta.MSCClass = clean(common.MSCClass)
+	meta.JournalRef = clean(common.JournalRef)
+	meta.DOI = clean(common.DOI)
+	meta.License = clean(common.License)
+
+	versioned := meta.ArxivID
+	if meta.Version > 0 {
+		versioned = fmt.Sprintf("%sv%d", meta.ArxivID, meta.Version)
+	}
+	meta.URL = "https://arxiv.org/abs/" + versioned
+	meta.PDFURL = "https://arxiv.org/pdf/" + versioned
+	return meta
+}
+
+// clean collapses the line breaks and indentation of OAI-PMH text fields.
+func clean(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// oaiTime converts an arXivRaw version date ("Mon, 2 Apr 2007 19:18:42
+// GMT") or an arXiv date ("2007-04-02") to RFC 3339.
+func oaiTime(s string) string {
+	s = strings.TrimSpace(s)
+	for _, layout := range []string{"Mon, 2 Jan 2006 15:04:05 MST", time.RFC1123Z, "2006-01-02"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC().Format(time.RFC3339)
+		}
+	}
+	return ""
+}
+
+// splitRawAuthors splits an arXivRaw author list ("A. One, B. Two (MIT) and
+// C. Three") into authors. A parenthesized part after a name becomes its
+// affiliation; commas inside parentheses do not separate authors.
+func splitRawAuthors(s string) []Author {
+	s = clean(s)
+	var parts []string
+	depth, start := 0, 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			if depth > 0 {
+				depth--
+			}
+		case ',':
+			if depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		case ' ':
+			if depth == 0 && strings.HasPrefix(s[i:], " and ") {
+				parts = append(parts, s[start:i])
+				start = i + len(" and ")
+				i += len(" and ") - 1
+			}
+		}
+	}
+	parts = append(parts, s[start:])
+
+	var authors []Author
+	for _, p := range parts {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		author := Author{Name: p}
+		if open := strings.Index(p, "("); open > 0 && strings.HasSuffix(p, ")") {
+			author.Name = strings.TrimSpace(p[:open])
+			author.Affiliation = strings.TrimSpace(p[open+1 : len(p)-1])
+		}
+		authors = append(authors, author)
+	}
+	return authors
+}
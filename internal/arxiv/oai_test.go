@@ -0,0 +1,218 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package arxiv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+const oaiPage1 = `<?xml version="1.0" encoding="UTF-8"?>
+<OAI-PMH xmlns="http://www.openarchives.org/OAI/2.0/">
+<responseDate>2025-03-01T09:00:00Z</responseDate>
+<request verb="ListRecords" metadataPrefix="arXivRaw" set="cs">http://export.arxiv.org/oai2</request>
+<ListRecords>
+<record>
+<header><identifier>oai:arXiv.org:1706.03762</identifier><datestamp>2023-08-03</datestamp><setSpec>cs</setSpec></header>
+<metadata>
+<arXivRaw xmlns="http://arxiv.org/OAI/arXivRaw/">
+<id>1706.03762</id>
+<submitter>Ashish Vaswani</submitter>
+<version version="v1"><date>Mon, 12 Jun 2017 17:57:34 GMT</date><size>1021kb</size><source_type>D</source_type></version>
+<version version="v7"><date>Wed, 2 Aug 2023 00:41:18 GMT</date><size>1893kb</size><source_type>D</source_type></version>
+<title>Attention Is All
+  You Need</title>
+<authors>Ashish Vaswani, Noam Shazeer (Google Brain, Mountain View), Niki Parmar and Jakob Uszkoreit</authors>
+<categories>cs.CL cs.LG</categories>
+<comments>15 pages, 5 figures</comments>
+<acm-class>I.2.7</acm-class>
+<msc-class>68T50</msc-class>
+<license>http://arxiv.org/licenses/nonexclusive-distrib/1.0/</license>
+<abstract>  The dominant sequence transduction models
+are based on complex recurrent networks.
+</abstract>
+</arXivRaw>
+</metadata>
+</record>
+<record>
+<header status="deleted"><identifier>oai:arXiv.org:1706.00001</identifier><datestamp>2023-08-03</datestamp></header>
+</record>
+<resumptionToken cursor="0" completeListSize="3">token|1001</resumptionToken>
+</ListRecords>
+</OAI-PMH>`
+
+const oaiPage2 = `<?xml version="1.0" encoding="UTF-8"?>
+<OAI-PMH xmlns="http://www.openarchives.org/OAI/2.0/">
+<ListRecords>
+<record>
+<header><identifier>oai:arXiv.org:hep-th/9901001</identifier><datestamp>2008-02-03</datestamp></header>
+<metadata>
+<arXivRaw xmlns="http://arxiv.org/OAI/arXivRaw/">
+<id>hep-th/9901001</id>
+<submitter>Someone</submitter>
+<version version="v1"><date>Fri, 1 Jan 1999 10:00:00 GMT</date></version>
+<title>Old Style</title>
+<authors>A. Author</authors>
+<categories>hep-th</categories>
+<doi>10.1000/xyz</doi>
+<journal-ref>Phys. Rev. D 1 (1999)</journal-ref>
+<abstract>Abstract.</abstract>
+</arXivRaw>
+</metadata>
+</record>
+<resumptionToken cursor="1001" completeListSize="3"></resumptionToken>
+</ListRecords>
+</OAI-PMH>`
+
+// oaiServer serves the two pages above, answering the first request for
+// page two with 503 and Retry-After as arXiv does. fail makes page two fail.
+func oaiServer(t *testing.T, fail bool) (*httptest.Server, *[]string) {
+	var requests []string
+	throttled := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.RawQuery)
+		q := r.URL.Query()
+		switch {
+		case q.Get("resumptionToken") == "token|1001" && fail:
+			http.Error(w, "boom", http.StatusInternalServerError)
+		case q.Get("resumptionToken") == "token|1001" && !throttled:
+			throttled = true
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case q.Get("resumptionToken") == "token|1001":
+			fmt.Fprint(w, oaiPage2)
+		case q.Get("set") == "empty":
+			fmt.Fprint(w, `<OAI-PMH><error code="noRecordsMatch">No records</error></OAI-PMH>`)
+		case q.Get("metadataPrefix") == "arXivRaw":
+			fmt.Fprint(w, oaiPage1)
+		default:
+			fmt.Fprint(w, `<OAI-PMH><error code="badArgument">bad</error></OAI-PMH>`)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+func TestHarvest(t *testing.T) {
+	srv, requests := oaiServer(t, false)
+	c := testClient(t, WithOAIBaseURL(srv.URL))
+
+	var metas []*ArxivMeta
+	var pages []HarvestPage
+	err := c.Harvest(context.Background(), HarvestOptions{Set: "cs", From: "2023-08-01", Until: "2023-08-31"},
+		func(m *ArxivMeta) error { metas = append(metas, m); return nil },
+		func(p HarvestPage) error { pages = append(pages, p); return nil })
+	if err != nil {
+		t.Fatalf("Harvest: %v", err)
+	}
+
+	if (*requests)[0] != "from=2023-08-01&metadataPrefix=arXivRaw&set=cs&until=2023-08-31&verb=ListRecords" {
+		t.Errorf("first request = %s", (*requests)[0])
+	}
+	if len(*requests) != 3 {
+		t.Errorf("requests = %v, want the 503 retried", *requests)
+	}
+	want := []HarvestPage{{Records: 1, Deleted: 1, Total: 3, Token: "token|1001"}, {Records: 2, Deleted: 1, Total: 3}}
+	if !reflect.DeepEqual(pages, want) {
+		t.Errorf("pages = %+v", pages)
+	}
+	if len(metas) != 2 {
+		t.Fatalf("got %d records", len(metas))
+	}
+
+	m := metas[0]
+	if m.ArxivID != "1706.03762" || m.Title != "Attention Is All You Need" || m.Submitter != "Ashish Vaswani" {
+		t.Errorf("meta = %+v", m)
+	}
+	if m.Version != 7 || m.Published != "2017-06-12T17:57:34Z" || m.Updated != "2023-08-02T00:41:18Z" {
+		t.Errorf("versions: v%d %s %s", m.Version, m.Published, m.Updated)
+	}
+	if m.License != "http://arxiv.org/licenses/nonexclusive-distrib/1.0/" || m.ACMClass != "I.2.7" || m.MSCClass != "68T50" {
+		t.Errorf("classes: %q %q %q", m.License, m.ACMClass, m.MSCClass)
+	}
+	if m.PrimaryCategory != "cs.CL" || len(m.Categories) != 2 || m.Comment != "15 pages, 5 figures" {
+		t.Errorf("categories %v, comment %q", m.Categories, m.Comment)
+	}
+	if m.Abstract != "The dominant sequence transduction models are based on complex recurrent networks." {
+		t.Errorf("abstract = %q", m.Abstract)
+	}
+	if m.URL != "https://arxiv.org/abs/1706.03762v7" || m.PDFURL != "https://arxiv.org/pdf/1706.03762v7" {
+		t.Errorf("links = %s %s", m.URL, m.PDFURL)
+	}
+	wantAuthors := []Author{
+		{Name: "Ashish Vaswani"},
+		{Name: "Noam Shazeer", Affiliation: "Google Brain, Mountain View"},
+		{Name: "Niki Parmar"},
+		{Name: "Jakob Uszkoreit"},
+	}
+	if !reflect.DeepEqual(m.Authors, wantAuthors) {
+		t.Errorf("authors = %+v", m.Authors)
+	}
+
+	if old := metas[1]; old.ArxivID != "hep-th/9901001" || old.DOI != "10.1000/xyz" || old.JournalRef != "Phys. Rev. D 1 (1999)" || old.Version != 1 {
+		t.Errorf("old-style meta = %+v", old)
+	}
+}
+
+func TestHarvestResumeAfterFailure(t *testing.T) {
+	srv, _ := oaiServer(t, true)
+	c := testClient(t, WithOAIBaseURL(srv.URL))
+
+	n := 0
+	err := c.Harvest(context.Background(), HarvestOptions{Set: "cs"}, func(*ArxivMeta) error { n++; return nil }, nil)
+	var herr *HarvestError
+	if !errors.As(err, &herr) || herr.Token != "token|1001" || n != 1 {
+		t.Fatalf("err = %v, records = %d", err, n)
+	}
+
+	// A failing callback repeats the current page on resume.
+	stop := errors.New("disk full")
+	err = c.Harvest(context.Background(), HarvestOptions{Set: "cs"}, func(*ArxivMeta) error { return stop }, nil)
+	if !errors.As(err, &herr) || herr.Token != "" || !errors.Is(err, stop) {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestHarvestErrors(t *testing.T) {
+	srv, _ := oaiServer(t, false)
+	c := testClient(t, WithOAIBaseURL(srv.URL))
+	noop := func(*ArxivMeta) error { return nil }
+
+	if err := c.Harvest(context.Background(), HarvestOptions{Set: "empty"}, noop, nil); err != nil {
+		t.Errorf("noRecordsMatch: %v", err)
+	}
+	if err := c.Harvest(context.Background(), HarvestOptions{Set: "cs", Format: FormatArxiv}, noop, nil); err == nil {
+		t.Error("expected OAI-PMH error")
+	}
+	if err := c.Harvest(context.Background(), HarvestOptions{Format: "oai_dc"}, noop, nil); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
+
+func TestOAIRecordArxivFormat(t *testing.T) {
+	rec := oaiRecord{}
+	rec.Metadata.Arxiv = &oaiArxiv{
+		oaiCommon: oaiCommon{ID: "2304.00067", Title: "T", Categories: "math.AG", License: "http://creativecommons.org/licenses/by/4.0/"},
+		Created:   "2023-03-31",
+	}
+	rec.Metadata.Arxiv.Authors = append(rec.Metadata.Arxiv.Authors, struct {
+		Keyname     string   `xml:"keyname"`
+		Forenames   string   `xml:"forenames"`
+		Suffix      string   `xml:"suffix"`
+		Affiliation []string `xml:"affiliation"`
+	}{Keyname: "Smith", Forenames: "Jane", Suffix: "Jr", Affiliation: []string{"MIT"}})
+
+	m := rec.meta()
+	if m.Authors[0].Name != "Jane Smith Jr" || m.Authors[0].Affiliation != "MIT" {
+		t.Errorf("authors = %+v", m.Authors)
+	}
+	if m.Published != "2023-03-31T00:00:00Z" || m.Updated != m.Published || m.URL != "https://arxiv.org/abs/2304.00067" {
+		t.Errorf("meta = %+v", m)
+	}
+}
@@ -0,0 +1,196 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package arxiv
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultAPIBaseURL is arXiv's query API.
+const DefaultAPIBaseURL = "https://export.arxiv.org/api/query"
+
+// DefaultDownloadBaseURL serves PDFs (/pdf/<id>) and sources
+// (/e-print/<id>).
+const DefaultDownloadBaseURL = "https://arxiv.org"
+
+// Default timeouts of a single attempt, used unless an option sets them.
+const (
+	DefaultConnectTimeout  = 30 * time.Second
+	DefaultResponseTimeout = 60 * time.Second
+)
+
+// Option configures a Client made by NewClient.
+type Option func(*options) error
+
+type options struct {
+	apiBaseURL      string
+	downloadBases   []string
+	oaiBaseURL      string
+	listingBaseURL  string
+	httpClient      *http.Client
+	connectTimeout  time.Duration
+	responseTimeout time.Duration
+	scheduler       *Scheduler
+	cache           *Cache
+	cacheSet        bool
+}
+
+// baseURL checks that s is an absolute http(s) URL and strips trailing
+// slashes.
+func baseURL(what, s string) (string, error) {
+	s = strings.TrimRight(strings.TrimSpace(s), "/")
+	u, err := url.Parse(s)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("invalid %s URL %q", what, s)
+	}
+	return s, nil
+}
+
+// WithAPIBaseURL sets the query API endpoint, for example
+// http://export.arxiv.org/api/query or a local stub server.
+func WithAPIBaseURL(u string) Option {
+	return func(o *options) (err error) {
+		o.apiBaseURL, err = baseURL("API", u)
+		return err
+	}
+}
+
+// WithDownloadBaseURL sets the site PDFs and sources are downloaded from,
+// replacing https://arxiv.org. It must serve /pdf/<id> and /e-print/<id>.
+func WithDownloadBaseURL(u string) Option {
+	return func(o *options) error {
+		base, err := baseURL("download", u)
+		if err != nil {
+			return err
+		}
+		o.downloadBases[0] = base
+		return nil
+	}
+}
+
+// WithMirrors adds download sites to try, in order, when a download from
+// the previous one fails with a network error or a server error. A paper
+// the site reports as missing is not looked for on the mirrors.
+func WithMirrors(urls ...string) Option {
+	return func(o *options) error {
+		for _, u := range urls {
+			base, err := baseURL("mirror", u)
+			if err != nil {
+				return err
+			}
+			o.downloadBases = append(o.downloadBases, base)
+		}
+		return nil
+	}
+}
+
+// WithOAIBaseURL sets the OAI-PMH endpoint used by Harvest.
+func WithOAIBaseURL(u string) Option {
+	return func(o *options) (err error) {
+		o.oaiBaseURL, err = baseURL("OAI-PMH", strings.TrimRight(u, "?"))
+		return err
+	}
+}
+
+// WithListingBaseURL sets where daily listings are read from.
+func WithListingBaseURL(u string) Option {
+	return func(o *options) (err error) {
+		o.listingBaseURL, err = baseURL("listing", u)
+		return err
+	}
+}
+
+// WithHTTPClient makes the client send its requests through hc, for
+// example one with a proxy or a custom CA. The requests are still
+// scheduled and cached; hc's own Timeout, if any, includes the time a
+// request waits for its turn.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(o *options) error {
+		if hc == nil {
+			return fmt.Errorf("http client cannot be nil")
+		}
+		o.httpClient = hc
+		return nil
+	}
+}
+
+// WithTimeouts bounds each attempt of a request: connect limits setting up
+// the connection and response limits the wait for the response to start.
+// Zero keeps a default. They apply when the transport is an
+// *http.Transport, as it is unless WithHTTPClient supplies another.
+func WithTimeouts(connect, response time.Duration) Option {
+	return func(o *options) error {
+		if connect < 0 || response < 0 {
+			return fmt.Errorf("timeouts cannot be negative")
+		}
+		if connect > 0 {
+			o.connectTimeout = connect
+		}
+		if response > 0 {
+			o.responseTimeout = response
+		}
+		return nil
+	}
+}
+
+// WithScheduler paces and retries the client's requests with s instead of
+// the scheduler shared by all clients.
+func WithScheduler(s *Scheduler) Option {
+	return func(o *options) error {
+		if s == nil {
+			return fmt.Errorf("scheduler cannot be nil")
+		}
+		o.scheduler = s
+		return nil
+	}
+}
+
+// WithCache makes the client use c instead of the cache set with SetCache.
+// Nil disables caching.
+func WithCache(c *Cache) Option {
+	return func(o *options) error {
+		o.cache, o.cacheSet = c, true
+		return nil
+	}
+}
+
+// transport returns the client's HTTP transport: the cache (if any) in
+// front of the scheduler in front of the network.
+func (o *options) transport() http.RoundTripper {
+	var base http.RoundTripper
+	if o.httpClient != nil {
+		base = o.httpClient.Transport
+	}
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if t, ok := base.(*http.Transport); ok {
+		t = t.Clone()
+		t.DialContext = (&net.Dialer{Timeout: o.connectTimeout, KeepAlive: 30 * time.Second}).DialContext
+		t.TLSHandshakeTimeout = o.connectTimeout
+		t.ResponseHeaderTimeout = o.responseTimeout
+		base = t
+	}
+
+	rt := o.scheduler.Transport(base)
+	if o.cache != nil {
+		rt = &cachingTransport{cache: o.cache, next: rt}
+	}
+	return rt
+}
+
+// client returns the HTTP client for all requests.
+func (o *options) client() *http.Client {
+	hc := &http.Client{}
+	if o.httpClient != nil {
+		*hc = *o.httpClient
+	}
+	hc.Transport = o.transport()
+	return hc
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package arxiv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewClientInvalidOptions(t *testing.T) {
+	invalid := map[string]Option{
+		"API URL":     WithAPIBaseURL("ftp://export.arxiv.org"),
+		"download":    WithDownloadBaseURL("arxiv.org"),
+		"mirror":      WithMirrors("https://ok.example", "::"),
+		"timeouts":    WithTimeouts(-1, 0),
+		"http client": WithHTTPClient(nil),
+		"scheduler":   WithScheduler(nil),
+	}
+	for name, opt := range invalid {
+		if _, err := NewClient(opt); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestDownloadFailover(t *testing.T) {
+	var primary, mirror []string
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		primary = append(primary, r.URL.Path)
+		http.Error(w, "down", http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+	gone := httptest.NewServer(http.NotFoundHandler())
+	gone.Close()
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mirror = append(mirror, r.URL.Path)
+		fmt.Fprint(w, "%PDF-1.5 mirrored")
+	}))
+	defer up.Close()
+
+	c := testClient(t, WithDownloadBaseURL(down.URL+"/"), WithMirrors(gone.URL, up.URL))
+	dest := filepath.Join(t.TempDir(), "paper.pdf")
+	if err := c.DownloadPDF(context.Background(), "2304.00067v2", dest, nil); err != nil {
+		t.Fatalf("DownloadPDF: %v", err)
+	}
+	if data, err := os.ReadFile(dest); err != nil || string(data) != "%PDF-1.5 mirrored" {
+		t.Errorf("saved %q (%v)", data, err)
+	}
+	if len(primary) != 3 || primary[0] != "/pdf/2304.00067v2.pdf" {
+		t.Errorf("primary requests = %v, want 3 tries", primary)
+	}
+	if len(mirror) != 1 || mirror[0] != "/pdf/2304.00067v2.pdf" {
+		t.Errorf("mirror requests = %v", mirror)
+	}
+}
+
+func TestDownloadNoFailoverWhenMissing(t *testing.T) {
+	missing := httptest.NewServer(http.NotFoundHandler())
+	defer missing.Close()
+	asked := false
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		asked = true
+	}))
+	defer mirror.Close()
+
+	c := testClient(t, WithDownloadBaseURL(missing.URL), WithMirrors(mirror.URL))
+	err := c.DownloadSource(context.Background(), "2304.00067", filepath.Join(t.TempDir(), "src"), nil)
+	var se *StatusError
+	if !errors.As(err, &se) || se.Code != http.StatusNotFound {
+		t.Errorf("err = %v, want 404", err)
+	}
+	if asked {
+		t.Error("mirror asked for a paper the site reported missing")
+	}
+}
+
+type countingTransport struct{ n int }
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.n++
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestWithHTTPClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "ok")
+	}))
+	defer srv.Close()
+
+	rt := &countingTransport{}
+	c := testClient(t, WithHTTPClient(&http.Client{Transport: rt}))
+	resp, err := c.get(context.Background(), srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if rt.n != 1 {
+		t.Errorf("injected transport used %d times", rt.n)
+	}
+}
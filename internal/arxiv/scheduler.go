@@ -0,0 +1,320 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package arxiv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Limits controls how requests to arXiv are paced and retried. Zero fields
+// take the default.
+type Limits struct {
+	// Interval is the least time between the starts of two requests. arXiv
+	// asks for about three seconds.
+	Interval time.Duration
+	// MaxRetries is how often a request that failed with a network error,
+	// 429 or 5xx is tried again. A negative value disables retries.
+	MaxRetries int
+	// Backoff is the pause before the first retry; it doubles with every
+	// further retry, with random jitter.
+	Backoff time.Duration
+	// MaxWait caps a single pause, whether from backoff or Retry-After.
+	MaxWait time.Duration
+}
+
+// DefaultLimits returns the limits used unless configured otherwise.
+func DefaultLimits() Limits {
+	return Limits{
+		Interval:   3 * time.Second,
+		MaxRetries: 5,
+		Backoff:    2 * time.Second,
+		MaxWait:    5 * time.Minute,
+	}
+}
+
+// withDefaults fills in the zero fields of l.
+func (l Limits) withDefaults() Limits {
+	d := DefaultLimits()
+	if l.Interval <= 0 {
+		l.Interval = d.Interval
+	}
+	if l.MaxRetries == 0 {
+		l.MaxRetries = d.MaxRetries
+	}
+	if l.MaxRetries < 0 {
+		l.MaxRetries = 0
+	}
+	if l.Backoff <= 0 {
+		l.Backoff = d.Backoff
+	}
+	if l.MaxWait <= 0 {
+		l.MaxWait = d.MaxWait
+	}
+	return l
+}
+
+// Scheduler paces and retries HTTP requests. A request takes its turn once
+// the previous one has its response headers, then waits for its start
+// slot, one Interval after the previous start, so requests from any number
+// of goroutines reach the server one at a time; only response bodies are
+// read concurrently. When the server asks for a pause (Retry-After) or a
+// request is backing off, the pause applies to all requests, not just the
+// one retried.
+type Scheduler struct {
+	mu     sync.Mutex
+	limits Limits
+	next   time.Time
+	// turn holds a token while a request is between its start and its
+	// response headers.
+	turn chan struct{}
+}
+
+// NewScheduler returns a scheduler with the given limits.
+func NewScheduler(limits Limits) *Scheduler {
+	return &Scheduler{limits: limits.withDefaults(), turn: make(chan struct{}, 1)}
+}
+
+// shared schedules the requests of every Client made by NewClient, so that
+// the limits hold across commands that create their own clients.
+var shared = NewScheduler(Limits{})
+
+// SetLimits changes the limits of the scheduler shared by all clients.
+func SetLimits(limits Limits) {
+	shared.SetLimits(limits)
+}
+
+// SetLimits changes the scheduler's limits; requests already waiting keep
+// their slots.
+func (s *Scheduler) SetLimits(limits Limits) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.limits = limits.withDefaults()
+}
+
+// Limits returns the scheduler's limits with defaults filled in.
+func (s *Scheduler) Limits() Limits {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.limits
+}
+
+// Transport wraps base (http.DefaultTransport if nil) so that every request
+// through it is scheduled.
+func (s *Scheduler) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &scheduledTransport{s: s, base: base}
+}
+
+// wait blocks until it is the request's turn and then until its start
+// slot. Unless it fails, the caller must call done once the response
+// headers are in.
+func (s *Scheduler) wait(ctx context.Context) error {
+	select {
+	case s.turn <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	s.mu.Lock()
+	now := time.Now()
+	start := s.next
+	if start.Before(now) {
+		start = now
+	}
+	s.next = start.Add(s.limits.Interval)
+	s.mu.Unlock()
+
+	if err := sleep(ctx, time.Until(start)); err != nil {
+		s.done()
+		return err
+	}
+	return nil
+}
+
+// done ends the turn taken by wait.
+func (s *Scheduler) done() {
+	<-s.turn
+}
+
+// pause holds back every request for at least d.
+func (s *Scheduler) pause(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if until := time.Now().Add(d); until.After(s.next) {
+		s.next = until
+	}
+}
+
+// backoff returns the pause before retry number attempt+1: Backoff doubled
+// attempt times and capped at MaxWait, of which a random half is dropped
+// so that clients do not retry in lockstep.
+func (s *Scheduler) backoff(attempt int) time.Duration {
+	l := s.Limits()
+	d := l.Backoff
+	for i := 0; i < attempt && d < l.MaxWait; i++ {
+		d *= 2
+	}
+	d = min(d, l.MaxWait)
+	return d/2 + rand.N(d/2+1)
+}
+
+type scheduledTransport struct {
+	s    *Scheduler
+	base http.RoundTripper
+}
+
+func (t *scheduledTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	maxRetries := t.s.Limits().MaxRetries
+	if req.Body != nil && req.GetBody == nil {
+		maxRetries = 0
+	}
+
+	for attempt := 0; ; attempt++ {
+		if err := t.s.wait(ctx); err != nil {
+			return nil, err
+		}
+
+		r := req
+		if attempt > 0 && req.Body != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				t.s.done()
+				return nil, err
+			}
+			r = req.Clone(ctx)
+			r.Body = body
+		}
+
+		resp, err := t.base.RoundTrip(r)
+		t.s.done()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, err
+			}
+			if attempt >= maxRetries || unreachable(err) {
+				return nil, retryError(attempt, err)
+			}
+			t.s.pause(t.s.backoff(attempt))
+			continue
+		}
+		if !retryable(resp.StatusCode) {
+			return resp, nil
+		}
+
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		_ = resp.Body.Close()
+		statusErr := &StatusError{Code: resp.StatusCode, Status: resp.Status}
+		if attempt >= maxRetries {
+			return nil, retryError(attempt, statusErr)
+		}
+		delay := t.s.backoff(attempt)
+		if v := resp.Header.Get("Retry-After"); v != "" {
+			delay = min(retryAfter(v), t.s.Limits().MaxWait)
+		}
+		t.s.pause(delay)
+	}
+}
+
+// retryable reports whether a response status is worth retrying: the
+// server is overloaded, throttling or briefly broken.
+func retryable(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// unreachable reports errors that retrying within minutes will not fix:
+// there is no network, the host does not exist or nothing listens. Giving
+// up at once lets cached responses be served without delay.
+func unreachable(err error) bool {
+	if errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ENETUNREACH) ||
+		errors.Is(err, syscall.EHOSTUNREACH) {
+		return true
+	}
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
+
+// retryError reports the last failure of a request. It deliberately hides
+// whether the failure was a timeout, so that callers with retry logic of
+// their own (such as goarxiv) do not retry again.
+func retryError(attempt int, err error) error {
+	return &retriesError{err: err, attempts: attempt + 1}
+}
+
+type retriesError struct {
+	err      error
+	attempts int
+}
+
+func (e *retriesError) Error() string {
+	if e.attempts == 1 {
+		return e.err.Error()
+	}
+	return fmt.Sprintf("%v (gave up after %d attempts)", e.err, e.attempts)
+}
+
+func (e *retriesError) Unwrap() error { return e.err }
+
+// retryAfter parses a Retry-After header given in seconds or as an HTTP
+// date. A missing or unparsable value means a short default pause.
+func retryAfter(value string) time.Duration {
+	const fallback = 10 * time.Second
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return fallback
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+		return 0
+	}
+	return fallback
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// unpaced satisfies goarxiv's rate limiter without waiting: the client's
+// transport already schedules every request.
+type unpaced struct{}
+
+func (unpaced) Wait(ctx context.Context) error { return ctx.Err() }
+func (unpaced) IsDebugMode() bool              { return false }
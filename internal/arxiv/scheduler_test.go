@@ -0,0 +1,201 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package arxiv
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testClient returns a client without a cache that paces and backs off in
+// milliseconds, configured further by opts.
+func testClient(t *testing.T, opts ...Option) *Client {
+	t.Helper()
+	fast := NewScheduler(Limits{Interval: time.Millisecond, Backoff: time.Millisecond, MaxRetries: 2})
+	c, err := NewClient(append([]Option{WithScheduler(fast), WithCache(nil)}, opts...)...)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return c
+}
+
+// statusServer answers with the given statuses in turn, then 200, and
+// records when each request arrived.
+func statusServer(t *testing.T, header http.Header, statuses ...int) (*httptest.Server, *[]time.Time) {
+	var mu sync.Mutex
+	var times []time.Time
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		n := len(times)
+		times = append(times, time.Now())
+		mu.Unlock()
+		if n < len(statuses) {
+			for k, v := range header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(statuses[n])
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &times
+}
+
+func TestSchedulerPacesRequests(t *testing.T) {
+	srv, times := statusServer(t, nil)
+	c := testClient(t, WithScheduler(NewScheduler(Limits{Interval: 50 * time.Millisecond})))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := c.get(context.Background(), srv.URL)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			_ = resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+
+	if len(*times) != 3 {
+		t.Fatalf("got %d requests", len(*times))
+	}
+	for i := 1; i < 3; i++ {
+		if gap := (*times)[i].Sub((*times)[i-1]); gap < 45*time.Millisecond {
+			t.Errorf("request %d came %v after the previous one", i, gap)
+		}
+	}
+}
+
+func TestSchedulerSerializesRequests(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, most := 0, 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		most = max(most, inFlight)
+		mu.Unlock()
+		time.Sleep(20 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		_, _ = w.Write([]byte("ok"))
+	}))
+	t.Cleanup(srv.Close)
+	c := testClient(t)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := c.get(context.Background(), srv.URL)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			_ = resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+
+	if most != 1 {
+		t.Errorf("%d requests were at the server at once, want 1", most)
+	}
+}
+
+func TestSchedulerRetries(t *testing.T) {
+	srv, times := statusServer(t, http.Header{"Retry-After": {"0"}},
+		http.StatusTooManyRequests, http.StatusServiceUnavailable)
+	resp, err := testClient(t).get(context.Background(), srv.URL)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	_ = resp.Body.Close()
+	if len(*times) != 3 {
+		t.Errorf("got %d requests, want 3", len(*times))
+	}
+}
+
+func TestSchedulerGivesUp(t *testing.T) {
+	srv, times := statusServer(t, nil, 500, 502, 504, 500)
+	_, err := testClient(t).get(context.Background(), srv.URL)
+	if err == nil || !strings.Contains(err.Error(), "HTTP 504") || !strings.Contains(err.Error(), "after 3 attempts") {
+		t.Errorf("err = %v", err)
+	}
+	if len(*times) != 3 {
+		t.Errorf("got %d requests, want 3", len(*times))
+	}
+
+	srv, times = statusServer(t, nil, http.StatusNotFound)
+	if _, err := testClient(t).get(context.Background(), srv.URL); err == nil || !strings.Contains(err.Error(), "HTTP 404") {
+		t.Errorf("err = %v", err)
+	}
+	if len(*times) != 1 {
+		t.Errorf("404 retried: %d requests", len(*times))
+	}
+}
+
+func TestSchedulerCancel(t *testing.T) {
+	s := NewScheduler(Limits{Interval: time.Hour})
+	if err := s.wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	s.done()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := s.wait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("wait = %v, want deadline exceeded", err)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	s := NewScheduler(Limits{Backoff: 100 * time.Millisecond, MaxWait: time.Second})
+	tests := []struct {
+		attempt int
+		max     time.Duration
+	}{
+		{0, 100 * time.Millisecond},
+		{3, 800 * time.Millisecond},
+		{10, time.Second},
+	}
+	for _, tt := range tests {
+		for i := 0; i < 20; i++ {
+			if d := s.backoff(tt.attempt); d < tt.max/2 || d > tt.max {
+				t.Errorf("backoff(%d) = %v, want %v to %v", tt.attempt, d, tt.max/2, tt.max)
+			}
+		}
+	}
+}
+
+func TestLimitsDefaults(t *testing.T) {
+	l := Limits{Interval: 5 * time.Second, MaxRetries: -1}.withDefaults()
+	want := DefaultLimits()
+	want.Interval = 5 * time.Second
+	want.MaxRetries = 0
+	if l != want {
+		t.Errorf("limits = %+v, want %+v", l, want)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	if d := retryAfter("20"); d.Seconds() != 20 {
+		t.Errorf("retryAfter(20) = %v", d)
+	}
+	if d := retryAfter("Wed, 21 Oct 2015 07:28:00 GMT"); d != 0 {
+		t.Errorf("past date = %v", d)
+	}
+	if d := retryAfter("soon"); d.Seconds() != 10 {
+		t.Errorf("unparsable = %v", d)
+	}
+}
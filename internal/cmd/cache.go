@@ -0,0 +1,207 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mtreilly/arc-arxiv/internal/arxiv"
+	"github.com/mtreilly/arc-arxiv/internal/settings"
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-sdk/config"
+	"github.com/yourorg/arc-sdk/output"
+	"github.com/yourorg/arc-sdk/utils"
+)
+
+// responseCacheDir returns the directory holding cached arXiv responses.
+func responseCacheDir(cfg *config.Config) string {
+	return filepath.Join(cfg.ResearchRoot, "cache", "http")
+}
+
+// responseCache returns the response cache configured in set.
+func responseCache(cfg *config.Config, set *settings.Settings) (*arxiv.Cache, error) {
+	for kind := range set.Cache.TTL {
+		if !slices.Contains(arxiv.CacheKinds, kind) {
+			return nil, fmt.Errorf("unknown cache kind %q in settings (use %s)", kind, strings.Join(arxiv.CacheKinds, ", "))
+		}
+	}
+	return arxiv.NewCache(responseCacheDir(cfg), set.Cache.TTL), nil
+}
+
+// warnStale tells the user when arXiv could not be reached and cached
+// responses were shown instead.
+func warnStale(report *arxiv.CacheReport) {
+	if storedAt, stale := report.Stale(); stale {
+		fmt.Fprintf(os.Stderr, "Warning: arXiv could not be reached; showing stale cached results from %s\n",
+			storedAt.Local().Format("2006-01-02 15:04"))
+	}
+}
+
+// fetchRemoteMeta fetches a paper's metadata from arXiv (or the response
+// cache) for display.
+func fetchRemoteMeta(cmd *cobra.Command, cfg *config.Config, input string) (*arxiv.ArxivMeta, error) {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	id, err := arxiv.NormalizeArxivID(input)
+	if err != nil {
+		return nil, err
+	}
+	client, err := newClient(cmd, cfg)
+	if err != nil {
+		return nil, err
+	}
+	ctx, report := arxiv.WithCacheReport(ctx)
+	meta, err := client.FetchArticle(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	warnStale(report)
+	return meta, nil
+}
+
+func newCacheCmd(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Inspect or clear the arXiv response cache",
+		Long: `Searches, metadata lookups (fetch, info --remote) and daily listings are
+cached in cache/http/ in the research root. A cached response is used
+without asking arXiv until it expires; after that it is revalidated, and it
+is still shown, marked as stale, when arXiv cannot be reached. --no-cache
+bypasses the cache for one command.
+
+Default lifetimes are 1h for searches, 24h for metadata and 1h for
+listings; set cache.ttl in arc-arxiv.yaml to change them.
+
+Examples:
+  arc-arxiv cache stats
+  arc-arxiv cache clear --expired
+  arc-arxiv cache clear`,
+	}
+	cmd.AddCommand(newCacheStatsCmd(cfg))
+	cmd.AddCommand(newCacheClearCmd(cfg))
+	return cmd
+}
+
+func newCacheStatsCmd(cfg *config.Config) *cobra.Command {
+	var out output.OutputOptions
+
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show what the response cache holds",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := out.Resolve(); err != nil {
+				return err
+			}
+
+			set, err := settings.Load(cfg.ResearchRoot)
+			if err != nil {
+				return err
+			}
+			cache, err := responseCache(cfg, set)
+			if err != nil {
+				return err
+			}
+			stats, err := cache.Stats()
+			if err != nil {
+				return err
+			}
+
+			if out.Is(output.OutputJSON) {
+				return output.JSON(stats)
+			}
+
+			ttls := arxiv.DefaultTTLs()
+			for kind, ttl := range set.Cache.TTL {
+				ttls[kind] = ttl
+			}
+			table := output.NewTable("Kind", "TTL", "Entries", "Fresh", "Size", "Newest")
+			var total int64
+			for _, s := range stats {
+				newest := "-"
+				if !s.Newest.IsZero() {
+					newest = utils.HumanizeTime(s.Newest.Unix())
+				}
+				table.AddRow(s.Kind, formatTTL(ttls[s.Kind]), fmt.Sprintf("%d", s.Entries),
+					fmt.Sprintf("%d", s.Fresh), formatSize(s.Bytes), newest)
+				total += s.Bytes
+			}
+			table.Render()
+			fmt.Printf("\nCache directory: %s (%s)\n", responseCacheDir(cfg), formatSize(total))
+			return nil
+		},
+	}
+
+	out.AddOutputFlags(cmd, output.OutputTable)
+
+	return cmd
+}
+
+func newCacheClearCmd(cfg *config.Config) *cobra.Command {
+	var expired bool
+
+	cmd := &cobra.Command{
+		Use:   "clear",
+		Short: "Remove cached responses",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Only --expired needs the configured lifetimes.
+			cache := arxiv.NewCache(responseCacheDir(cfg), nil)
+			if expired {
+				set, err := settings.Load(cfg.ResearchRoot)
+				if err != nil {
+					return err
+				}
+				if cache, err = responseCache(cfg, set); err != nil {
+					return err
+				}
+			}
+			n, err := cache.Clear(expired)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Removed %d cached response(s).\n", n)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&expired, "expired", false, "Remove only expired responses")
+
+	return cmd
+}
+
+// formatTTL renders a duration without zero minutes and seconds ("1h",
+// "1h30m", "10m").
+func formatTTL(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = s[:len(s)-2]
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = s[:len(s)-2]
+	}
+	return s
+}
+
+// formatSize renders a byte count for humans.
+func formatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGT"[exp])
+}
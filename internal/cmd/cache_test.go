@@ -0,0 +1,55 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mtreilly/arc-arxiv/internal/settings"
+	"github.com/yourorg/arc-sdk/config"
+)
+
+func TestResponseCacheKinds(t *testing.T) {
+	cfg := &config.Config{ResearchRoot: t.TempDir()}
+	set := &settings.Settings{Cache: settings.Cache{TTL: map[string]time.Duration{"search": time.Minute}}}
+	if _, err := responseCache(cfg, set); err != nil {
+		t.Errorf("responseCache: %v", err)
+	}
+	set.Cache.TTL["pdf"] = time.Hour
+	if _, err := responseCache(cfg, set); err == nil {
+		t.Error("expected error for unknown kind")
+	}
+}
+
+func TestFormatTTL(t *testing.T) {
+	tests := map[time.Duration]string{
+		time.Hour:                  "1h",
+		24 * time.Hour:             "24h",
+		90 * time.Minute:           "1h30m",
+		10 * time.Minute:           "10m",
+		45 * time.Second:           "45s",
+		0:                          "0s",
+		time.Hour + 30*time.Second: "1h0m30s",
+	}
+	for d, want := range tests {
+		if got := formatTTL(d); got != want {
+			t.Errorf("formatTTL(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	tests := map[int64]string{
+		0:               "0 B",
+		1023:            "1023 B",
+		1536:            "1.5 KB",
+		5 * 1024 * 1024: "5.0 MB",
+	}
+	for n, want := range tests {
+		if got := formatSize(n); got != want {
+			t.Errorf("formatSize(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/mtreilly/arc-arxiv/internal/arxiv"
+	"github.com/mtreilly/arc-arxiv/internal/settings"
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-sdk/config"
+)
+
+// newClient creates an arxiv client configured by the settings file; extra
+// options override the settings. It also applies the rate limits and the
+// response cache, which all clients share, so that only commands that reach
+// arXiv depend on those settings. With --no-cache on cmd the command neither
+// uses nor updates the response cache.
+func newClient(cmd *cobra.Command, cfg *config.Config, extra ...arxiv.Option) (*arxiv.Client, error) {
+	set, err := settings.Load(cfg.ResearchRoot)
+	if err != nil {
+		return nil, err
+	}
+	arxiv.SetLimits(arxiv.Limits(set.RateLimit))
+	if noCache, _ := cmd.Flags().GetBool("no-cache"); noCache {
+		arxiv.SetCache(nil)
+	} else {
+		cache, err := responseCache(cfg, set)
+		if err != nil {
+			return nil, err
+		}
+		arxiv.SetCache(cache)
+	}
+
+	opts, err := clientOptions(set)
+	if err != nil {
+		return nil, err
+	}
+	client, err := arxiv.NewClient(append(opts, extra...)...)
+	if err != nil {
+		return nil, fmt.Errorf("create arxiv client: %w", err)
+	}
+	return client, nil
+}
+
+// clientOptions turns the client section of the settings (and the OAI-PMH
+// endpoint) into client options.
+func clientOptions(set *settings.Settings) ([]arxiv.Option, error) {
+	c := set.Client
+	var opts []arxiv.Option
+	if c.APIBaseURL != "" {
+		opts = append(opts, arxiv.WithAPIBaseURL(c.APIBaseURL))
+	}
+	if c.DownloadBaseURL != "" {
+		opts = append(opts, arxiv.WithDownloadBaseURL(c.DownloadBaseURL))
+	}
+	if len(c.Mirrors) > 0 {
+		opts = append(opts, arxiv.WithMirrors(c.Mirrors...))
+	}
+	if c.ListingBaseURL != "" {
+		opts = append(opts, arxiv.WithListingBaseURL(c.ListingBaseURL))
+	}
+	if set.OAI.BaseURL != "" {
+		opts = append(opts, arxiv.WithOAIBaseURL(set.OAI.BaseURL))
+	}
+	if c.ConnectTimeout != 0 || c.ResponseTimeout != 0 {
+		opts = append(opts, arxiv.WithTimeouts(c.ConnectTimeout, c.ResponseTimeout))
+	}
+
+	if c.Proxy == "" && c.CAFile == "" {
+		return opts, nil
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if c.Proxy != "" {
+		u, err := url.Parse(c.Proxy)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL %q in settings", c.Proxy)
+		}
+		transport.Proxy = http.ProxyURL(u)
+	}
+	if c.CAFile != "" {
+		path := set.Resolve(c.CAFile)
+		pem, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read CA file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", path)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+	return append(opts, arxiv.WithHTTPClient(&http.Client{Transport: transport})), nil
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mtreilly/arc-arxiv/internal/arxiv"
+	"github.com/mtreilly/arc-arxiv/internal/settings"
+	"github.com/yourorg/arc-sdk/config"
+)
+
+func TestClientOptions(t *testing.T) {
+	set := &settings.Settings{
+		Client: settings.Client{
+			APIBaseURL:      "http://export.arxiv.org/api/query",
+			DownloadBaseURL: "https://mirror.example/arxiv",
+			Mirrors:         []string{"https://a.example", "https://b.example"},
+			Proxy:           "http://proxy.example:3128",
+			ConnectTimeout:  5 * time.Second,
+		},
+		OAI: settings.OAI{BaseURL: "https://oai.example/oai"},
+	}
+	opts, err := clientOptions(set)
+	if err != nil {
+		t.Fatalf("clientOptions: %v", err)
+	}
+	if len(opts) != 6 {
+		t.Errorf("got %d options, want 6", len(opts))
+	}
+	if _, err := arxiv.NewClient(append(opts, arxiv.WithCache(nil))...); err != nil {
+		t.Errorf("NewClient: %v", err)
+	}
+
+	if opts, err := clientOptions(&settings.Settings{}); err != nil || len(opts) != 0 {
+		t.Errorf("defaults: %d options, %v", len(opts), err)
+	}
+}
+
+func TestClientOptionsErrors(t *testing.T) {
+	dir := t.TempDir()
+	notPEM := filepath.Join(dir, "ca.pem")
+	if err := os.WriteFile(notPEM, []byte("not a certificate"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range []settings.Client{
+		{Proxy: "proxy.example:3128"},
+		{CAFile: filepath.Join(dir, "missing.pem")},
+		{CAFile: notPEM},
+	} {
+		if _, err := clientOptions(&settings.Settings{Client: c}); err == nil {
+			t.Errorf("%+v: expected error", c)
+		}
+	}
+
+	opts, err := clientOptions(&settings.Settings{Client: settings.Client{Mirrors: []string{"not a url"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := arxiv.NewClient(opts...); err == nil {
+		t.Error("expected error for an invalid mirror")
+	}
+}
+
+func TestMalformedSettingsOnlyAffectNetworkCommands(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv(settings.EnvPath, "")
+	if err := os.WriteFile(filepath.Join(root, settings.FileName), []byte("rate_limit: [\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{ResearchRoot: root}
+
+	cmd := NewRootCmd(cfg, nil)
+	cmd.SetArgs([]string{"cache", "clear"})
+	if err := cmd.Execute(); err != nil {
+		t.Errorf("cache clear: %v", err)
+	}
+	if _, err := newClient(cmd, cfg); err == nil {
+		t.Error("newClient accepted malformed settings")
+	}
+}
+
+func TestNewClientNoCache(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv(settings.EnvPath, "")
+	if err := os.WriteFile(filepath.Join(root, settings.FileName), []byte("cache:\n  ttl:\n    bogus: 1h\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{ResearchRoot: root}
+	t.Cleanup(func() { arxiv.SetCache(nil) })
+
+	search, _, err := NewRootCmd(cfg, nil).Find([]string{"search"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := search.ParseFlags(nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newClient(search, cfg); err == nil {
+		t.Error("newClient accepted an unknown cache kind")
+	}
+	// With --no-cache the cache settings are not consulted at all.
+	if err := search.ParseFlags([]string{"--no-cache"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newClient(search, cfg); err != nil {
+		t.Errorf("newClient with --no-cache: %v", err)
+	}
+}
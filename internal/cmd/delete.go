@@ -5,6 +5,8 @@ package cmd
 
 import (
 	"bufio"
+	"context"
+	"database/sql"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -15,7 +17,7 @@ import (
 	"github.com/yourorg/arc-sdk/config"
 )
 
-func newDeleteCmd(cfg *config.Config) *cobra.Command {
+func newDeleteCmd(cfg *config.Config, db *sql.DB) *cobra.Command {
 	var force bool
 	var dryRun bool
 
@@ -31,6 +33,11 @@ Examples:
   arc-arxiv delete 2304.00067 --dry-run # Show what would be deleted`,
 		Args: cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
 			papersRoot := filepath.Join(cfg.ResearchRoot, "papers")
 
 			// Normalize and validate all IDs first
@@ -41,12 +48,9 @@ Examples:
 			}
 
 			for _, arg := range args {
-				id, err := arxiv.NormalizeArxivID(arg)
-				if err != nil {
-					id = arg
-				}
+				id, _ := libraryID(arg)
 
-				paperDir := filepath.Join(papersRoot, id)
+				paperDir := libraryDir(papersRoot, id)
 				if _, err := os.Stat(paperDir); os.IsNotExist(err) {
 					fmt.Printf("Paper not found: %s\n", id)
 					continue
@@ -104,6 +108,7 @@ Examples:
 					fmt.Printf("Failed to delete %s: %v\n", p.id, err)
 					continue
 				}
+				unindex(ctx, db, papersRoot, p.id)
 				fmt.Printf("Deleted: %s\n", p.id)
 				deleted++
 			}
@@ -0,0 +1,571 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mtreilly/arc-arxiv/internal/arxiv"
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-sdk/config"
+	"github.com/yourorg/arc-sdk/output"
+)
+
+// Problem kinds reported by doctor.
+const (
+	issueMissingPDF   = "missing-pdf"
+	issueEmptyPDF     = "empty-pdf"
+	issueNotPDF       = "not-pdf"
+	issueTruncatedPDF = "truncated-pdf"
+	issueMissingMeta  = "missing-meta"
+	issueBadMeta      = "bad-meta"
+	issueIDMismatch   = "id-mismatch"
+	issuePartial      = "partial-download"
+	issueNestedDir    = "nested-dir"
+)
+
+// Repair actions doctor can apply.
+const (
+	fixRedownload = "redownload"
+	fixRefetch    = "refetch-meta"
+	fixQuarantine = "quarantine"
+	fixRemove     = "remove"
+	fixMerge      = "merge"
+	fixRelocate   = "relocate"
+	fixNone       = "none"
+)
+
+type doctorIssue struct {
+	ID     string `json:"id,omitempty"`
+	Dir    string `json:"dir"`
+	Kind   string `json:"kind"`
+	Detail string `json:"detail"`
+	Fix    string `json:"fix"`
+	// Path is the offending file, when the issue concerns a single file.
+	Path string `json:"path,omitempty"`
+}
+
+// partialSuffixes identify files left behind by interrupted downloads or writes.
+var partialSuffixes = []string{".tmp", ".part", ".partial", ".download", ".crdownload"}
+
+func newDoctorCmd(cfg *config.Config, db *sql.DB) *cobra.Command {
+	var out output.OutputOptions
+	var fix bool
+	var quarantine bool
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "doctor [id...]",
+		Short: "Check and repair library integrity",
+		Long: `Check downloaded papers for problems that other commands silently skip.
+
+Reports:
+  missing-pdf / empty-pdf   paper.pdf is absent or zero bytes
+  not-pdf                   paper.pdf or a paper.vN.pdf has no %PDF header
+                            (e.g. a saved HTML error page)
+  truncated-pdf             paper.pdf or a paper.vN.pdf has no %%EOF trailer
+  missing-meta / bad-meta   meta.yaml is absent or cannot be parsed
+  id-mismatch               directory name disagrees with meta.yaml arxiv_id
+                            (a <id>vN directory is merged into <id> by --fix)
+  partial-download          leftover temporary files from an interrupted download
+  nested-dir                old-style ID stored as papers/<archive>/<number>
+                            instead of papers/<archive>-<number>
+
+With --fix, PDF problems are re-downloaded, metadata problems are re-fetched,
+partial files are removed, versioned directories left by older releases are
+folded into the paper's canonical directory, nested old-style directories
+are moved to their flat location, and anything that cannot be repaired is
+moved to research_root/quarantine/. Use --quarantine to quarantine every broken paper
+instead of repairing it.
+
+Examples:
+  arc-arxiv doctor
+  arc-arxiv doctor 2304.00067
+  arc-arxiv doctor --fix --dry-run
+  arc-arxiv doctor --fix --quarantine`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := out.Resolve(); err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			papersRoot := filepath.Join(cfg.ResearchRoot, "papers")
+
+			var dirs, nested []string
+			if len(args) > 0 {
+				for _, arg := range args {
+					id, _ := libraryID(arg)
+					dir := libraryDir(papersRoot, id)
+					if _, err := os.Stat(dir); os.IsNotExist(err) {
+						// Old-style IDs used to be stored nested.
+						if legacy := filepath.Join(papersRoot, id); strings.Contains(id, "/") && isDir(legacy) {
+							nested = append(nested, legacy)
+							continue
+						}
+						fmt.Printf("Paper not found: %s\n", id)
+						continue
+					}
+					dirs = append(dirs, dir)
+				}
+			} else {
+				entries, err := os.ReadDir(papersRoot)
+				if err != nil {
+					if os.IsNotExist(err) {
+						fmt.Println("No papers downloaded yet.")
+						return nil
+					}
+					return err
+				}
+				for _, entry := range entries {
+					if !entry.IsDir() {
+						continue
+					}
+					dir := filepath.Join(papersRoot, entry.Name())
+					if papers := nestedPaperDirs(dir); len(papers) > 0 {
+						nested = append(nested, papers...)
+						continue
+					}
+					dirs = append(dirs, dir)
+				}
+			}
+
+			var issues []doctorIssue
+			for _, dir := range nested {
+				id := filepath.Base(filepath.Dir(dir)) + "/" + filepath.Base(dir)
+				issues = append(issues, doctorIssue{
+					ID:     id,
+					Dir:    dir,
+					Kind:   issueNestedDir,
+					Detail: fmt.Sprintf("belongs in %s", arxiv.DirName(id)),
+					Fix:    fixRelocate,
+				})
+			}
+			for _, dir := range dirs {
+				issues = append(issues, diagnosePaper(dir)...)
+			}
+			checked := len(dirs) + len(nested)
+
+			if quarantine {
+				for i := range issues {
+					if issues[i].Fix != fixRemove && issues[i].Fix != fixNone {
+						issues[i].Fix = fixQuarantine
+					}
+				}
+			}
+
+			if out.Is(output.OutputJSON) && !fix {
+				return output.JSON(issues)
+			}
+
+			if len(issues) == 0 {
+				fmt.Printf("Checked %d paper(s): no problems found.\n", checked)
+				return nil
+			}
+
+			table := output.NewTable("Paper", "Problem", "Detail", "Fix")
+			for _, issue := range issues {
+				table.AddRow(issueName(issue), issue.Kind, truncate(issue.Detail, 50), issue.Fix)
+			}
+			table.Render()
+			fmt.Printf("\nChecked %d paper(s): %d problem(s) found.\n", checked, len(issues))
+
+			if !fix {
+				fmt.Println("Run with --fix to repair.")
+				return nil
+			}
+
+			fmt.Println()
+			d := &doctor{cmd: cmd, cfg: cfg, db: db, dryRun: dryRun}
+			fixed := 0
+			for _, issue := range issues {
+				if err := d.repair(ctx, issue); err != nil {
+					fmt.Printf("  %s: %s failed: %v\n", filepath.Base(issue.Dir), issue.Fix, err)
+					continue
+				}
+				if issue.Fix != fixNone {
+					fixed++
+				}
+			}
+
+			if dryRun {
+				fmt.Printf("\n[dry-run] Would apply %d fix(es).\n", fixed)
+			} else {
+				fmt.Printf("\nApplied %d fix(es).\n", fixed)
+			}
+			return nil
+		},
+	}
+
+	out.AddOutputFlags(cmd, output.OutputTable)
+	cmd.Flags().BoolVar(&fix, "fix", false, "Repair problems (re-download, re-fetch metadata, quarantine)")
+	cmd.Flags().BoolVar(&quarantine, "quarantine", false, "With --fix, quarantine broken papers instead of repairing them")
+	cmd.Flags().BoolVarP(&dryRun, "dry-run", "d", false, "Show fixes without applying them")
+
+	return cmd
+}
+
+// diagnosePaper inspects one paper directory and returns every problem found.
+func diagnosePaper(dir string) []doctorIssue {
+	name := filepath.Base(dir)
+	metaPath := filepath.Join(dir, "meta.yaml")
+	meta, metaErr := readMeta(metaPath)
+	id := idForRepair(name, meta)
+
+	var issues []doctorIssue
+	add := func(kind, detail, fix, path string) {
+		issues = append(issues, doctorIssue{ID: id, Dir: dir, Kind: kind, Detail: detail, Fix: fix, Path: path})
+	}
+
+	// meta.yaml
+	switch {
+	case os.IsNotExist(metaErr):
+		add(issueMissingMeta, "meta.yaml not found", refetchOrQuarantine(name), metaPath)
+	case metaErr != nil:
+		add(issueBadMeta, firstLine(metaErr.Error()), refetchOrQuarantine(name), metaPath)
+	case meta.ArxivID == "":
+		add(issueBadMeta, "meta.yaml has no arxiv_id", refetchOrQuarantine(name), metaPath)
+	case arxiv.DirName(meta.ArxivID) != name:
+		detail := fmt.Sprintf("directory %s holds arxiv_id %s", name, meta.ArxivID)
+		fix := fixQuarantine
+		if base, _, ok := versionedDirBase(name); ok && base == meta.ArxivID {
+			detail = fmt.Sprintf("versioned directory for %s", meta.ArxivID)
+			fix = fixMerge
+		}
+		add(issueIDMismatch, detail, fix, metaPath)
+	}
+
+	// paper.pdf and the PDF of every version held
+	pdfFix := fixRedownload
+	if !arxiv.IsValidArxivID(id) {
+		pdfFix = fixQuarantine
+	}
+	pdfPath := filepath.Join(dir, "paper.pdf")
+	if kind, detail := checkPDF(pdfPath); kind != "" {
+		add(kind, detail, pdfFix, pdfPath)
+	}
+	for _, v := range localVersions(dir) {
+		path := filepath.Join(dir, versionedPDFName(v))
+		if kind, detail := checkPDF(path); kind != "" {
+			add(kind, detail, pdfFix, path)
+		}
+	}
+
+	// Leftovers from interrupted downloads
+	entries, _ := os.ReadDir(dir)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		for _, suffix := range partialSuffixes {
+			if strings.HasSuffix(entry.Name(), suffix) {
+				add(issuePartial, entry.Name(), fixRemove, filepath.Join(dir, entry.Name()))
+				break
+			}
+		}
+	}
+
+	return issues
+}
+
+// checkPDF validates a PDF file's presence, header and trailer. It returns an
+// empty kind when the file looks complete.
+func checkPDF(path string) (kind, detail string) {
+	name := filepath.Base(path)
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return issueMissingPDF, name + " not found"
+	}
+	if err != nil {
+		return issueMissingPDF, err.Error()
+	}
+	defer func() { _ = f.Close() }()
+
+	info, err := f.Stat()
+	if err != nil {
+		return issueMissingPDF, err.Error()
+	}
+	if info.Size() == 0 {
+		return issueEmptyPDF, name + " is 0 bytes"
+	}
+
+	head := make([]byte, 1024)
+	n, _ := io.ReadFull(f, head)
+	head = head[:n]
+	if !bytes.HasPrefix(bytes.TrimLeft(head, "\xef\xbb\xbf \t\r\n"), []byte("%PDF-")) {
+		sample := strings.ToLower(string(head))
+		if strings.Contains(sample, "<html") || strings.Contains(sample, "<!doctype") {
+			return issueNotPDF, name + " is an HTML page"
+		}
+		return issueNotPDF, name + " has no %PDF header"
+	}
+
+	tailSize := int64(1024)
+	if info.Size() < tailSize {
+		tailSize = info.Size()
+	}
+	tail := make([]byte, tailSize)
+	if _, err := f.ReadAt(tail, info.Size()-tailSize); err != nil && err != io.EOF {
+		return issueTruncatedPDF, err.Error()
+	}
+	if !bytes.Contains(tail, []byte("%%EOF")) {
+		return issueTruncatedPDF, fmt.Sprintf("%s has no %%%%EOF trailer (%d bytes)", name, info.Size())
+	}
+
+	return "", ""
+}
+
+func refetchOrQuarantine(dirName string) string {
+	if _, ok := arxiv.IDFromDirName(dirName); ok {
+		return fixRefetch
+	}
+	return fixQuarantine
+}
+
+// redownloadMeta describes the version a broken PDF is replaced with: the
+// version of a broken paper.vN.pdf, and for paper.pdf the pinned version or
+// else the one meta.yaml records. downloadVersion then refreshes paper.pdf
+// from the versioned copies, keeping a pin. Without a known version the
+// latest PDF is saved as paper.pdf.
+func redownloadMeta(issue doctorIssue) *arxiv.ArxivMeta {
+	meta := &arxiv.ArxivMeta{ArxivID: issue.ID}
+	if prev, err := readMeta(filepath.Join(issue.Dir, "meta.yaml")); err == nil {
+		meta.Version = prev.Version
+		meta.PinnedVersion = prev.PinnedVersion
+		if prev.PinnedVersion > 0 {
+			meta.Version = prev.PinnedVersion
+		}
+	}
+	if m := versionedPDFPattern.FindStringSubmatch(filepath.Base(issue.Path)); m != nil {
+		meta.Version, _ = strconv.Atoi(m[1])
+	}
+	return meta
+}
+
+// idForRepair picks the arXiv ID to use when re-downloading a paper.
+func idForRepair(dirName string, meta *arxiv.ArxivMeta) string {
+	if meta != nil && meta.ArxivID != "" {
+		return meta.ArxivID
+	}
+	if id, ok := arxiv.IDFromDirName(dirName); ok {
+		return id
+	}
+	return dirName
+}
+
+// nestedPaperDirs returns the paper directories inside dir when dir is an
+// archive directory left by storing old-style IDs as papers/<archive>/<number>.
+func nestedPaperDirs(dir string) []string {
+	if _, err := os.Stat(filepath.Join(dir, "meta.yaml")); err == nil {
+		return nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	archive := filepath.Base(dir)
+	var papers []string
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		if id, ok := arxiv.IDFromDirName(archive + "-" + e.Name()); ok && strings.Contains(id, "/") {
+			papers = append(papers, filepath.Join(dir, e.Name()))
+		}
+	}
+	return papers
+}
+
+// issueName labels an issue by its paper directory, including the archive
+// for nested old-style directories.
+func issueName(issue doctorIssue) string {
+	if issue.Kind == issueNestedDir {
+		return issue.ID
+	}
+	return filepath.Base(issue.Dir)
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
+
+// doctor applies repairs, creating an arxiv client only when one is needed.
+type doctor struct {
+	// cmd is the doctor command, whose flags configure the arxiv client.
+	cmd    *cobra.Command
+	cfg    *config.Config
+	db     *sql.DB
+	dryRun bool
+	client *arxiv.Client
+	// quarantined tracks directories already moved (quarantined or merged),
+	// so later issues for the same paper are skipped.
+	quarantined map[string]bool
+	// redownloaded tracks the versions already downloaded again, so that a
+	// broken paper.pdf and its broken paper.vN.pdf cost one download.
+	redownloaded map[string]bool
+}
+
+func (d *doctor) arxivClient() (*arxiv.Client, error) {
+	if d.client == nil {
+		c, err := newClient(d.cmd, d.cfg)
+		if err != nil {
+			return nil, err
+		}
+		d.client = c
+	}
+	return d.client, nil
+}
+
+func (d *doctor) repair(ctx context.Context, issue doctorIssue) error {
+	name := issueName(issue)
+	papersRoot := filepath.Join(d.cfg.ResearchRoot, "papers")
+	if d.quarantined[issue.Dir] {
+		return nil
+	}
+
+	if d.dryRun {
+		if issue.Fix != fixNone {
+			fmt.Printf("  [dry-run] %s: %s (%s)\n", name, issue.Fix, issue.Kind)
+		}
+		return nil
+	}
+
+	switch issue.Fix {
+	case fixNone:
+		return nil
+
+	case fixRemove:
+		if err := os.Remove(issue.Path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		fmt.Printf("  %s: removed %s\n", name, filepath.Base(issue.Path))
+
+	case fixRedownload:
+		meta := redownloadMeta(issue)
+		key := fmt.Sprintf("%s v%d", issue.Dir, meta.Version)
+		if d.redownloaded[key] {
+			return nil
+		}
+		client, err := d.arxivClient()
+		if err != nil {
+			return err
+		}
+		if err := downloadVersion(ctx, client, meta, issue.Dir, nil); err != nil {
+			return err
+		}
+		if d.redownloaded == nil {
+			d.redownloaded = make(map[string]bool)
+		}
+		d.redownloaded[key] = true
+		if meta.Version > 0 {
+			fmt.Printf("  %s: re-downloaded %s\n", name, versionedPDFName(meta.Version))
+		} else {
+			fmt.Printf("  %s: re-downloaded paper.pdf\n", name)
+		}
+
+	case fixRefetch:
+		client, err := d.arxivClient()
+		if err != nil {
+			return err
+		}
+		meta, err := client.FetchArticle(ctx, issue.ID)
+		if err != nil {
+			return err
+		}
+		if err := writeMeta(filepath.Join(issue.Dir, "meta.yaml"), meta); err != nil {
+			return err
+		}
+		syncIndex(ctx, d.db, papersRoot, meta, issue.Dir)
+		fmt.Printf("  %s: re-fetched meta.yaml\n", name)
+
+	case fixMerge:
+		dest, err := mergeVersionedDir(issue.Dir)
+		if err != nil {
+			return err
+		}
+		if d.quarantined == nil {
+			d.quarantined = make(map[string]bool)
+		}
+		d.quarantined[issue.Dir] = true
+		if meta, err := readMeta(filepath.Join(dest, "meta.yaml")); err == nil {
+			syncIndex(ctx, d.db, papersRoot, meta, dest)
+		}
+		fmt.Printf("  %s: merged into %s\n", name, filepath.Base(dest))
+
+	case fixRelocate:
+		dest, err := relocateNestedDir(issue)
+		if err != nil {
+			return err
+		}
+		if meta, err := readMeta(filepath.Join(dest, "meta.yaml")); err == nil {
+			syncIndex(ctx, d.db, papersRoot, meta, dest)
+		}
+		fmt.Printf("  %s: moved to %s\n", name, filepath.Base(dest))
+
+	case fixQuarantine:
+		dest, err := quarantineDir(d.cfg, issue.Dir)
+		if err != nil {
+			return err
+		}
+		if d.quarantined == nil {
+			d.quarantined = make(map[string]bool)
+		}
+		d.quarantined[issue.Dir] = true
+		unindexMoved(ctx, d.db, papersRoot, issue.ID, issue.Dir)
+		fmt.Printf("  %s: quarantined to %s\n", name, dest)
+
+	default:
+		return fmt.Errorf("unknown fix %q", issue.Fix)
+	}
+	return nil
+}
+
+// relocateNestedDir moves a papers/<archive>/<number> directory to its flat
+// location and removes the archive directory once it is empty.
+func relocateNestedDir(issue doctorIssue) (string, error) {
+	archiveDir := filepath.Dir(issue.Dir)
+	dest := filepath.Join(filepath.Dir(archiveDir), arxiv.DirName(issue.ID))
+	if _, err := os.Stat(dest); err == nil {
+		return "", fmt.Errorf("%s already exists", dest)
+	}
+	if err := os.Rename(issue.Dir, dest); err != nil {
+		return "", err
+	}
+	_ = os.Remove(archiveDir) // fails harmlessly while other papers remain
+	return dest, nil
+}
+
+// quarantineDir moves a paper directory out of papers/ into
+// research_root/quarantine/, returning its new location.
+func quarantineDir(cfg *config.Config, dir string) (string, error) {
+	qRoot := filepath.Join(cfg.ResearchRoot, "quarantine")
+	if err := os.MkdirAll(qRoot, 0o755); err != nil {
+		return "", err
+	}
+	dest := filepath.Join(qRoot, filepath.Base(dir)+"-"+time.Now().Format("20060102-150405"))
+	if err := os.Rename(dir, dest); err != nil {
+		return "", err
+	}
+	return dest, nil
+}
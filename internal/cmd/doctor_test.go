@@ -0,0 +1,317 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mtreilly/arc-arxiv/internal/arxiv"
+	"github.com/mtreilly/arc-arxiv/internal/index"
+	"github.com/yourorg/arc-sdk/config"
+	"github.com/yourorg/arc-sdk/db"
+)
+
+const validPDF = "%PDF-1.5\n1 0 obj\n<<>>\nendobj\ntrailer\n<<>>\n%%EOF\n"
+
+func writeTestPaper(t *testing.T, root, dirName, arxivID, pdf string) string {
+	t.Helper()
+	dir := filepath.Join(root, dirName)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if arxivID != "" {
+		if err := writeMeta(filepath.Join(dir, "meta.yaml"), &arxiv.ArxivMeta{ArxivID: arxivID, Title: "T"}); err != nil {
+			t.Fatalf("writeMeta: %v", err)
+		}
+	}
+	if pdf != "" {
+		if err := os.WriteFile(filepath.Join(dir, "paper.pdf"), []byte(pdf), 0o644); err != nil {
+			t.Fatalf("write pdf: %v", err)
+		}
+	}
+	return dir
+}
+
+func issueKinds(issues []doctorIssue) map[string]string {
+	kinds := make(map[string]string)
+	for _, i := range issues {
+		kinds[i.Kind] = i.Fix
+	}
+	return kinds
+}
+
+func TestDiagnosePaper(t *testing.T) {
+	t.Run("healthy paper", func(t *testing.T) {
+		dir := writeTestPaper(t, t.TempDir(), "2304.00067", "2304.00067", validPDF)
+		if issues := diagnosePaper(dir); len(issues) != 0 {
+			t.Errorf("expected no issues, got %+v", issues)
+		}
+	})
+
+	t.Run("missing pdf", func(t *testing.T) {
+		dir := writeTestPaper(t, t.TempDir(), "2304.00067", "2304.00067", "")
+		kinds := issueKinds(diagnosePaper(dir))
+		if kinds[issueMissingPDF] != fixRedownload {
+			t.Errorf("issues = %v, want missing-pdf/redownload", kinds)
+		}
+	})
+
+	t.Run("zero-byte pdf", func(t *testing.T) {
+		dir := writeTestPaper(t, t.TempDir(), "2304.00067", "2304.00067", "")
+		if err := os.WriteFile(filepath.Join(dir, "paper.pdf"), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if kinds := issueKinds(diagnosePaper(dir)); kinds[issueEmptyPDF] == "" {
+			t.Errorf("issues = %v, want empty-pdf", kinds)
+		}
+	})
+
+	t.Run("html saved as pdf", func(t *testing.T) {
+		dir := writeTestPaper(t, t.TempDir(), "2304.00067", "2304.00067", "<!DOCTYPE html><html>Rate limited</html>")
+		issues := diagnosePaper(dir)
+		if len(issues) != 1 || issues[0].Kind != issueNotPDF {
+			t.Fatalf("issues = %+v, want one not-pdf", issues)
+		}
+		if issues[0].Detail != "paper.pdf is an HTML page" {
+			t.Errorf("Detail = %q", issues[0].Detail)
+		}
+	})
+
+	t.Run("truncated pdf", func(t *testing.T) {
+		dir := writeTestPaper(t, t.TempDir(), "2304.00067", "2304.00067", "%PDF-1.5\n1 0 obj\n<<")
+		if kinds := issueKinds(diagnosePaper(dir)); kinds[issueTruncatedPDF] == "" {
+			t.Errorf("issues = %v, want truncated-pdf", kinds)
+		}
+	})
+
+	t.Run("malformed meta", func(t *testing.T) {
+		dir := writeTestPaper(t, t.TempDir(), "2304.00067", "", validPDF)
+		if err := os.WriteFile(filepath.Join(dir, "meta.yaml"), []byte("title: [unclosed"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if kinds := issueKinds(diagnosePaper(dir)); kinds[issueBadMeta] != fixRefetch {
+			t.Errorf("issues = %v, want bad-meta/refetch", kinds)
+		}
+	})
+
+	t.Run("missing meta in unrecognised directory", func(t *testing.T) {
+		dir := writeTestPaper(t, t.TempDir(), "not-an-id", "", validPDF)
+		if kinds := issueKinds(diagnosePaper(dir)); kinds[issueMissingMeta] != fixQuarantine {
+			t.Errorf("issues = %v, want missing-meta/quarantine", kinds)
+		}
+	})
+
+	t.Run("directory name disagrees with arxiv_id", func(t *testing.T) {
+		dir := writeTestPaper(t, t.TempDir(), "2304.00067", "2101.00001", validPDF)
+		if kinds := issueKinds(diagnosePaper(dir)); kinds[issueIDMismatch] != fixQuarantine {
+			t.Errorf("issues = %v, want id-mismatch/quarantine", kinds)
+		}
+	})
+
+	t.Run("versioned directory is merged", func(t *testing.T) {
+		dir := writeTestPaper(t, t.TempDir(), "2304.00067v2", "2304.00067", validPDF)
+		if kinds := issueKinds(diagnosePaper(dir)); kinds[issueIDMismatch] != fixMerge {
+			t.Errorf("issues = %v, want id-mismatch/merge", kinds)
+		}
+	})
+
+	t.Run("leftover partial download", func(t *testing.T) {
+		dir := writeTestPaper(t, t.TempDir(), "2304.00067", "2304.00067", validPDF)
+		if err := os.WriteFile(filepath.Join(dir, "paper.pdf.part"), []byte("%PDF"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		issues := diagnosePaper(dir)
+		if len(issues) != 1 || issues[0].Kind != issuePartial || issues[0].Fix != fixRemove {
+			t.Errorf("issues = %+v, want one partial-download/remove", issues)
+		}
+	})
+}
+
+func TestVersionedDirBase(t *testing.T) {
+	tests := []struct {
+		name    string
+		base    string
+		version int
+		ok      bool
+	}{
+		{"2304.00067v2", "2304.00067", 2, true},
+		{"2304.00067", "2304.00067", 0, false},
+		{"notes-v2", "", 0, false},
+	}
+	for _, tt := range tests {
+		base, version, ok := versionedDirBase(tt.name)
+		if ok != tt.ok || (ok && (base != tt.base || version != tt.version)) {
+			t.Errorf("versionedDirBase(%q) = %q, %d, %v; want %q, %d, %v", tt.name, base, version, ok, tt.base, tt.version, tt.ok)
+		}
+	}
+}
+
+func TestNestedOldStyleDirs(t *testing.T) {
+	root := t.TempDir()
+	archive := filepath.Join(root, "hep-th")
+	old := writeTestPaper(t, archive, "9901001", "hep-th/9901001", validPDF)
+	writeTestPaper(t, root, "2304.00067", "2304.00067", validPDF)
+
+	if got := nestedPaperDirs(archive); len(got) != 1 || got[0] != old {
+		t.Fatalf("nestedPaperDirs = %v, want [%s]", got, old)
+	}
+	if got := nestedPaperDirs(filepath.Join(root, "2304.00067")); got != nil {
+		t.Errorf("paper directory reported as nested: %v", got)
+	}
+
+	dest, err := relocateNestedDir(doctorIssue{ID: "hep-th/9901001", Dir: old, Kind: issueNestedDir, Fix: fixRelocate})
+	if err != nil {
+		t.Fatalf("relocateNestedDir: %v", err)
+	}
+	if dest != filepath.Join(root, "hep-th-9901001") {
+		t.Errorf("dest = %q", dest)
+	}
+	if _, err := os.Stat(archive); !os.IsNotExist(err) {
+		t.Error("empty archive directory should be removed")
+	}
+	if issues := diagnosePaper(dest); len(issues) != 0 {
+		t.Errorf("relocated paper has issues: %+v", issues)
+	}
+}
+
+func TestRepairRedownloadKeepsPin(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "2304.00067")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	meta := &arxiv.ArxivMeta{ArxivID: "2304.00067", Title: "T", Version: 1, PinnedVersion: 1}
+	if err := writeMeta(filepath.Join(dir, "meta.yaml"), meta); err != nil {
+		t.Fatal(err)
+	}
+	v2 := validPDF + "% v2\n"
+	truncated := "%PDF-1.5\n1 0 obj\n"
+	for name, content := range map[string]string{"paper.v1.pdf": truncated, "paper.v2.pdf": v2, "paper.pdf": truncated} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	issues := diagnosePaper(dir)
+	var broken []string
+	for _, issue := range issues {
+		if issue.Kind == issueTruncatedPDF && issue.Fix == fixRedownload {
+			broken = append(broken, filepath.Base(issue.Path))
+		}
+	}
+	if len(broken) != 2 || broken[0] != "paper.pdf" || broken[1] != "paper.v1.pdf" {
+		t.Fatalf("truncated PDFs reported: %v (issues %+v)", broken, issues)
+	}
+
+	v1 := validPDF + "% v1\n"
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path)
+		if r.URL.Path != "/pdf/2304.00067v1.pdf" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(v1))
+	}))
+	defer srv.Close()
+	client, err := arxiv.NewClient(
+		arxiv.WithDownloadBaseURL(srv.URL),
+		arxiv.WithScheduler(arxiv.NewScheduler(arxiv.Limits{Interval: time.Millisecond, MaxRetries: -1})),
+		arxiv.WithCache(nil),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := &doctor{cfg: &config.Config{ResearchRoot: root}, client: client}
+	for _, issue := range issues {
+		if err := d.repair(context.Background(), issue); err != nil {
+			t.Fatalf("repair %s: %v", issue.Kind, err)
+		}
+	}
+
+	if len(requests) != 1 {
+		t.Errorf("requests = %v, want one download of v1", requests)
+	}
+	for name, want := range map[string]string{"paper.v1.pdf": v1, "paper.pdf": v1, "paper.v2.pdf": v2} {
+		got, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil || string(got) != want {
+			t.Errorf("%s = %q, %v; want %q", name, got, err, want)
+		}
+	}
+	if len(diagnosePaper(dir)) != 0 {
+		t.Errorf("issues left after repair: %+v", diagnosePaper(dir))
+	}
+}
+
+func TestQuarantineKeepsOwnerIndexed(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	papersRoot := filepath.Join(root, "papers")
+	owner := writeTestPaper(t, papersRoot, "2304.00067", "2304.00067", validPDF)
+	stray := writeTestPaper(t, papersRoot, "2301.99999", "2304.00067", validPDF)
+	other := writeTestPaper(t, papersRoot, "2302.00002", "2302.00002", validPDF)
+
+	database, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("db.Open: %v", err)
+	}
+	t.Cleanup(func() { _ = database.Close() })
+	ix, err := index.Open(ctx, database)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Index the stray copy under the ID, as a scan may have.
+	if err := ix.Replace(ctx, []index.Entry{
+		{Meta: &arxiv.ArxivMeta{ArxivID: "2304.00067"}, Dir: stray},
+		{Meta: &arxiv.ArxivMeta{ArxivID: "2302.00002"}, Dir: other},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	issues := diagnosePaper(stray)
+	if kinds := issueKinds(issues); kinds[issueIDMismatch] != fixQuarantine {
+		t.Fatalf("issues = %+v", issues)
+	}
+	d := &doctor{cfg: &config.Config{ResearchRoot: root}, db: database}
+	for _, issue := range issues {
+		if err := d.repair(ctx, issue); err != nil {
+			t.Fatalf("repair: %v", err)
+		}
+	}
+
+	e, err := ix.Get(ctx, "2304.00067")
+	if err != nil {
+		t.Fatalf("owner dropped from the index: %v", err)
+	}
+	if e.Dir != owner {
+		t.Errorf("indexed dir = %s, want %s", e.Dir, owner)
+	}
+	if n, _ := ix.Count(ctx); n != 2 {
+		t.Errorf("index holds %d papers, want 2", n)
+	}
+
+	// Without an owner, the row pointing at the quarantined copy goes.
+	stray = writeTestPaper(t, papersRoot, "2303.88888", "2302.00002", validPDF)
+	if err := ix.Upsert(ctx, index.Entry{Meta: &arxiv.ArxivMeta{ArxivID: "2302.00002"}, Dir: stray}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.RemoveAll(other); err != nil {
+		t.Fatal(err)
+	}
+	for _, issue := range diagnosePaper(stray) {
+		if err := d.repair(ctx, issue); err != nil {
+			t.Fatalf("repair: %v", err)
+		}
+	}
+	if _, err := ix.Get(ctx, "2302.00002"); err == nil {
+		t.Error("quarantined copy still indexed")
+	}
+}
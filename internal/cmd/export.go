@@ -4,6 +4,8 @@
 package cmd
 
 import (
+	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -13,13 +15,16 @@ import (
 	"github.com/mtreilly/goarxiv"
 	"github.com/spf13/cobra"
 	"github.com/mtreilly/arc-arxiv/internal/arxiv"
+	"github.com/mtreilly/arc-arxiv/internal/index"
 	"github.com/yourorg/arc-sdk/config"
 )
 
-func newExportCmd(cfg *config.Config) *cobra.Command {
+func newExportCmd(cfg *config.Config, db *sql.DB) *cobra.Command {
 	var format string
 	var all bool
 	var outputFile string
+	var tag string
+	var collection string
 
 	cmd := &cobra.Command{
 		Use:   "export [id...]",
@@ -32,33 +37,34 @@ Examples:
   arc-arxiv export --all --format csv            # CSV export
   arc-arxiv export --all --format json           # JSON export
   arc-arxiv export --all -f bibtex -o refs.bib   # Save to file
+  arc-arxiv export --collection thesis -o thesis.bib  # One project's bibliography
+  arc-arxiv export --tag attention --format json
+
+--tag and --collection select papers from the whole library, as --all does.
 
 Formats: bibtex (default), csv, json`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
 			papersRoot := filepath.Join(cfg.ResearchRoot, "papers")
 
 			var metas []*arxiv.ArxivMeta
 
-			if all {
-				// Export all papers
-				entries, err := os.ReadDir(papersRoot)
+			if all || tag != "" || collection != "" {
+				// Export all papers, optionally narrowed by tag or collection
+				ix, err := openIndex(ctx, cfg, db)
 				if err != nil {
-					if os.IsNotExist(err) {
-						return fmt.Errorf("no papers found")
-					}
 					return err
 				}
-
-				for _, entry := range entries {
-					if !entry.IsDir() {
-						continue
-					}
-					metaPath := filepath.Join(papersRoot, entry.Name(), "meta.yaml")
-					meta, err := readMeta(metaPath)
-					if err != nil {
-						continue
-					}
-					metas = append(metas, meta)
+				entries, err := ix.List(ctx, index.Filter{Tag: tag, Collection: collection})
+				if err != nil {
+					return err
+				}
+				for _, e := range entries {
+					metas = append(metas, e.Meta)
 				}
 			} else {
 				// Export specific papers
@@ -67,11 +73,8 @@ Formats: bibtex (default), csv, json`,
 				}
 
 				for _, arg := range args {
-					id, err := arxiv.NormalizeArxivID(arg)
-					if err != nil {
-						id = arg
-					}
-					metaPath := filepath.Join(papersRoot, id, "meta.yaml")
+					id, _ := libraryID(arg)
+					metaPath := filepath.Join(libraryDir(papersRoot, id), "meta.yaml")
 					meta, err := readMeta(metaPath)
 					if err != nil {
 						return fmt.Errorf("paper not found: %s", id)
@@ -118,6 +121,8 @@ Formats: bibtex (default), csv, json`,
 	cmd.Flags().StringVarP(&format, "format", "f", "bibtex", "Export format: bibtex, csv, json")
 	cmd.Flags().BoolVar(&all, "all", false, "Export all downloaded papers")
 	cmd.Flags().StringVarP(&outputFile, "output", "o", "", "Write output to file")
+	cmd.Flags().StringVar(&tag, "tag", "", "Export papers with this tag")
+	cmd.Flags().StringVar(&collection, "collection", "", "Export papers in this collection")
 
 	return cmd
 }
@@ -0,0 +1,90 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/mtreilly/arc-arxiv/internal/fsutil"
+	"github.com/mtreilly/arc-arxiv/internal/pdftext"
+)
+
+// Files written by text extraction.
+const (
+	bodyFile      = "body.md"
+	bodyPagesFile = "body.pages.json"
+)
+
+// Text extraction backends. The built-in extractor needs no external
+// tools; pdftotext (from poppler) copes with more unusual PDFs.
+const (
+	extractorBuiltin   = "builtin"
+	extractorPdftotext = "pdftotext"
+)
+
+var extractors = []string{extractorBuiltin, extractorPdftotext}
+
+// bodyPages is the JSON sidecar written next to body.md.
+type bodyPages struct {
+	Source    string         `json:"source"`
+	Extractor string         `json:"extractor"`
+	Pages     []pdftext.Page `json:"pages"`
+}
+
+// resolveExtractor picks the extraction backend: the flag if given, else the
+// settings file, else the built-in extractor.
+func resolveExtractor(flag, configured string) (string, error) {
+	backend := flag
+	if backend == "" {
+		backend = configured
+	}
+	if backend == "" {
+		return extractorBuiltin, nil
+	}
+	if !slices.Contains(extractors, backend) {
+		return "", fmt.Errorf("unknown extractor %q (use %s)", backend, strings.Join(extractors, " or "))
+	}
+	return backend, nil
+}
+
+// extractPdfText writes the text of pdfPath to body.md in dir, with a
+// marker before each page, and the per-page text to body.pages.json.
+func extractPdfText(ctx context.Context, pdfPath, dir, backend string) error {
+	var raw []string
+	switch backend {
+	case extractorPdftotext:
+		if _, err := exec.LookPath("pdftotext"); err != nil {
+			return fmt.Errorf("pdftotext not available")
+		}
+		var out bytes.Buffer
+		cmd := exec.CommandContext(ctx, "pdftotext", "-enc", "UTF-8", pdfPath, "-")
+		cmd.Stdout = &out
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("pdftotext: %w", err)
+		}
+		raw = pdftext.SplitPages(out.String())
+	default:
+		var err error
+		if raw, err = pdftext.ExtractFile(pdfPath); err != nil {
+			return err
+		}
+	}
+
+	pages := pdftext.Clean(raw)
+	if err := fsutil.WriteFile(filepath.Join(dir, bodyFile), []byte(pdftext.Markdown(pages)), 0o644); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(bodyPages{Source: filepath.Base(pdfPath), Extractor: backend, Pages: pages}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return fsutil.WriteFile(filepath.Join(dir, bodyPagesFile), append(data, '\n'), 0o644)
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// textPDF is a two-page PDF with uncompressed content streams.
+const textPDF = `%PDF-1.4
+1 0 obj << /Type /Catalog /Pages 2 0 R >> endobj
+2 0 obj << /Type /Pages /Kids [3 0 R 4 0 R] /Count 2 /Resources << /Font << /F1 5 0 R >> >> >> endobj
+3 0 obj << /Type /Page /Parent 2 0 R /Contents 6 0 R >> endobj
+4 0 obj << /Type /Page /Parent 2 0 R /Contents 7 0 R >> endobj
+5 0 obj << /Type /Font /Subtype /Type1 /BaseFont /Helvetica >> endobj
+6 0 obj << /Length 58 >>
+stream
+BT /F1 10 Tf 72 700 Td (Attention is) Tj 0 -12 Td (all) Tj ET
+endstream
+endobj
+7 0 obj << /Length 44 >>
+stream
+BT /F1 10 Tf 72 700 Td (you need.) Tj ET
+endstream
+endobj
+trailer << /Root 1 0 R >>
+%%EOF
+`
+
+func TestExtractPdfText(t *testing.T) {
+	dir := t.TempDir()
+	pdfPath := filepath.Join(dir, "paper.pdf")
+	writeFile(t, pdfPath, textPDF)
+
+	if err := extractPdfText(context.Background(), pdfPath, dir, extractorBuiltin); err != nil {
+		t.Fatalf("extractPdfText: %v", err)
+	}
+
+	body := readFile(t, filepath.Join(dir, bodyFile))
+	want := "<!-- page 1 -->\n\nAttention is\nall\n\n<!-- page 2 -->\n\nyou need.\n"
+	if body != want {
+		t.Errorf("body.md = %q, want %q", body, want)
+	}
+
+	var sidecar bodyPages
+	if err := json.Unmarshal([]byte(readFile(t, filepath.Join(dir, bodyPagesFile))), &sidecar); err != nil {
+		t.Fatalf("parse %s: %v", bodyPagesFile, err)
+	}
+	if sidecar.Source != "paper.pdf" || sidecar.Extractor != extractorBuiltin || len(sidecar.Pages) != 2 {
+		t.Fatalf("sidecar = %+v", sidecar)
+	}
+	if sidecar.Pages[1].Number != 2 || sidecar.Pages[1].Text != "you need." {
+		t.Errorf("page 2 = %+v", sidecar.Pages[1])
+	}
+}
+
+func TestResolveExtractor(t *testing.T) {
+	tests := []struct {
+		flag, configured, want string
+	}{
+		{"", "", extractorBuiltin},
+		{"", "pdftotext", extractorPdftotext},
+		{"builtin", "pdftotext", extractorBuiltin},
+	}
+	for _, tt := range tests {
+		got, err := resolveExtractor(tt.flag, tt.configured)
+		if err != nil || got != tt.want {
+			t.Errorf("resolveExtractor(%q, %q) = %q, %v; want %q", tt.flag, tt.configured, got, err, tt.want)
+		}
+	}
+	if _, err := resolveExtractor("ocr", ""); err == nil || !strings.Contains(err.Error(), "unknown extractor") {
+		t.Errorf("expected unknown extractor error, got %v", err)
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mtreilly/arc-arxiv/internal/arxiv"
+	"github.com/mtreilly/arc-arxiv/internal/feed"
+	"github.com/mtreilly/arc-arxiv/internal/index"
+	"github.com/mtreilly/arc-arxiv/internal/watch"
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-sdk/config"
+)
+
+func newFeedCmd(cfg *config.Config, db *sql.DB) *cobra.Command {
+	var format string
+	var outputFile string
+	var tag string
+	var collection string
+	var search string
+	var since string
+	var limit int
+	var title string
+	var link string
+
+	cmd := &cobra.Command{
+		Use:   "feed",
+		Short: "Write an Atom or RSS feed of papers",
+		Long: `Write an Atom 1.0 (default) or RSS 2.0 feed of the most recently fetched
+papers in the library, of the papers with a tag or in a collection, or of
+the papers a saved search (see the watch command) has reported.
+
+Each entry carries the paper's title, authors, abstract and categories, and
+links to its arXiv page and PDF. Entries are ordered by when the paper was
+fetched, or reported by the saved search, newest first. Publish the file
+somewhere your team's feed readers can reach, for example from a cron job.
+
+Examples:
+  arc-arxiv feed -o library.atom
+  arc-arxiv feed --tag attention --format rss -o attention.xml
+  arc-arxiv feed --search hinton -o hinton.atom
+  arc-arxiv feed --since 2025-01-01 --limit 100 --link https://example.org/papers.atom -o papers.atom`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			format = strings.ToLower(format)
+			if !slices.Contains(feed.Formats, format) {
+				return fmt.Errorf("unknown format: %s (use %s)", format, strings.Join(feed.Formats, ", "))
+			}
+			if search != "" && (tag != "" || collection != "") {
+				return fmt.Errorf("--search cannot be combined with --tag or --collection")
+			}
+
+			var sinceTime time.Time
+			if since != "" {
+				t, err := time.Parse("2006-01-02", since)
+				if err != nil {
+					return fmt.Errorf("invalid date format for --since (use YYYY-MM-DD): %w", err)
+				}
+				sinceTime = t
+			}
+
+			var papers []*arxiv.ArxivMeta
+			var defaultTitle, feedID string
+			if search != "" {
+				st, err := watch.Load(watch.Path(cfg.ResearchRoot))
+				if err != nil {
+					return err
+				}
+				s := st.Get(search)
+				if s == nil {
+					return fmt.Errorf("no saved search named %q", search)
+				}
+				for _, m := range s.Recent {
+					if sinceTime.IsZero() || parseTime(m.FetchedAt) >= sinceTime.Unix() {
+						papers = append(papers, m)
+					}
+				}
+				defaultTitle = "arXiv: new papers for " + describeSearch(s)
+				feedID = "urn:arc-arxiv:search:" + s.Name
+			} else {
+				ix, err := openIndex(ctx, cfg, db)
+				if err != nil {
+					return err
+				}
+				entries, err := ix.List(ctx, index.Filter{Tag: tag, Collection: collection, Since: sinceTime})
+				if err != nil {
+					return err
+				}
+				for _, e := range entries {
+					papers = append(papers, e.Meta)
+				}
+				switch {
+				case tag != "":
+					defaultTitle, feedID = "Papers tagged "+tag, "urn:arc-arxiv:tag:"+strings.ToLower(tag)
+				case collection != "":
+					defaultTitle, feedID = "Papers in "+collection, "urn:arc-arxiv:collection:"+strings.ToLower(collection)
+				default:
+					defaultTitle, feedID = "Recently fetched papers", "urn:arc-arxiv:library"
+				}
+			}
+
+			sortPapers(papers, "fetched")
+			if limit > 0 && len(papers) > limit {
+				papers = papers[:limit]
+			}
+
+			f := buildFeed(papers, feedID, title, link)
+			if f.Title == "" {
+				f.Title = defaultTitle
+			}
+
+			var buf bytes.Buffer
+			if err := f.Write(&buf, format); err != nil {
+				return err
+			}
+			if outputFile == "" {
+				fmt.Print(buf.String())
+				return nil
+			}
+			if err := os.WriteFile(outputFile, buf.Bytes(), 0o644); err != nil {
+				return fmt.Errorf("write file: %w", err)
+			}
+			fmt.Printf("Wrote %s feed of %d paper(s) to %s\n", format, len(f.Entries), outputFile)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&format, "format", "f", feed.FormatAtom, "Feed format: atom, rss")
+	cmd.Flags().StringVarP(&outputFile, "output", "o", "", "Write the feed to file (default: stdout)")
+	cmd.Flags().StringVar(&tag, "tag", "", "Only papers with this tag")
+	cmd.Flags().StringVar(&collection, "collection", "", "Only papers in this collection")
+	cmd.Flags().StringVar(&search, "search", "", "Papers reported by this saved search")
+	cmd.Flags().StringVar(&since, "since", "", "Only papers fetched or reported since date (YYYY-MM-DD)")
+	cmd.Flags().IntVarP(&limit, "limit", "l", 50, "Maximum number of entries (0 for all)")
+	cmd.Flags().StringVar(&title, "title", "", "Feed title")
+	cmd.Flags().StringVar(&link, "link", "", "URL the feed will be published at")
+
+	return cmd
+}
+
+// buildFeed turns papers into feed entries, in order. When link is set it
+// also serves as the feed ID.
+func buildFeed(papers []*arxiv.ArxivMeta, feedID, title, link string) *feed.Feed {
+	f := &feed.Feed{ID: feedID, Title: title, Link: link}
+	if link != "" {
+		f.ID = link
+	}
+	for _, m := range papers {
+		e := feed.Entry{
+			ID:         m.URL,
+			Title:      oneline(m.Title),
+			Summary:    oneline(m.Abstract),
+			Link:       m.URL,
+			PDFLink:    m.PDFURL,
+			Categories: m.Categories,
+		}
+		if e.ID == "" {
+			e.ID = "https://arxiv.org/abs/" + m.ArxivID
+		}
+		for _, a := range m.Authors {
+			e.Authors = append(e.Authors, a.Name)
+		}
+		e.Published, _ = time.Parse(time.RFC3339, m.Published)
+		e.Updated, _ = time.Parse(time.RFC3339, m.FetchedAt)
+		f.Entries = append(f.Entries, e)
+	}
+	return f
+}
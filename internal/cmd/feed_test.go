@@ -0,0 +1,45 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/mtreilly/arc-arxiv/internal/arxiv"
+)
+
+func TestBuildFeed(t *testing.T) {
+	papers := []*arxiv.ArxivMeta{
+		{
+			ArxivID:    "1706.03762",
+			Title:      "Attention Is\n  All You Need",
+			URL:        "http://arxiv.org/abs/1706.03762v7",
+			PDFURL:     "http://arxiv.org/pdf/1706.03762v7",
+			Authors:    []arxiv.Author{{Name: "Ashish Vaswani"}},
+			Published:  "2017-06-12T17:57:34Z",
+			FetchedAt:  "2025-03-01T10:00:00+01:00",
+			Categories: []string{"cs.CL"},
+		},
+		{ArxivID: "2304.00067", Title: "No URL"},
+	}
+
+	f := buildFeed(papers, "urn:arc-arxiv:library", "", "")
+	if f.ID != "urn:arc-arxiv:library" || len(f.Entries) != 2 {
+		t.Fatalf("feed = %+v", f)
+	}
+	e := f.Entries[0]
+	if e.Title != "Attention Is All You Need" || e.PDFLink != papers[0].PDFURL || e.Authors[0] != "Ashish Vaswani" {
+		t.Errorf("entry = %+v", e)
+	}
+	if e.Updated.UTC().Hour() != 9 || e.Published.Year() != 2017 {
+		t.Errorf("times = %v, %v", e.Updated, e.Published)
+	}
+	if f.Entries[1].ID != "https://arxiv.org/abs/2304.00067" {
+		t.Errorf("fallback ID = %s", f.Entries[1].ID)
+	}
+
+	if f := buildFeed(nil, "urn:x", "", "https://example.org/f.atom"); f.ID != "https://example.org/f.atom" {
+		t.Errorf("feed ID with link = %s", f.ID)
+	}
+}
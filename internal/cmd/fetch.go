@@ -0,0 +1,292 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/mtreilly/arc-arxiv/internal/arxiv"
+	"github.com/mtreilly/arc-arxiv/internal/fsutil"
+	"github.com/mtreilly/arc-arxiv/internal/settings"
+	"github.com/yourorg/arc-sdk/config"
+	"github.com/yourorg/arc-sdk/output"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultFetchWorkers is how many PDFs fetch downloads at once unless
+// configured otherwise.
+const defaultFetchWorkers = 4
+
+// fetchWorkers returns the number of download workers: the flag if given,
+// else the settings, else the default.
+func fetchWorkers(flag int, set *settings.Settings) (int, error) {
+	switch {
+	case flag < 0:
+		return 0, fmt.Errorf("--workers must be at least 1")
+	case flag > 0:
+		return flag, nil
+	case set.Fetch.Workers < 0:
+		return 0, fmt.Errorf("fetch.workers in settings must be at least 1")
+	case set.Fetch.Workers > 0:
+		return set.Fetch.Workers, nil
+	}
+	return defaultFetchWorkers, nil
+}
+
+// fetchJob is a paper of a fetch batch on its way into the library.
+type fetchJob struct {
+	item     fetchItem
+	id       string // base ID, the library key
+	pinned   int
+	dir      string
+	existed  bool
+	prevMeta *arxiv.ArxivMeta
+	meta     *arxiv.ArxivMeta
+	// err says why the paper could not be fetched.
+	err error
+}
+
+// nextBatch returns how many items from the front of queue form the next
+// batch: all of them, up to the first that repeats a base ID, so that no
+// two jobs of a batch write to the same directory.
+func nextBatch(queue []fetchItem) int {
+	seen := make(map[string]bool)
+	for i, item := range queue {
+		base, _ := arxiv.SplitVersion(item.id)
+		if seen[base] {
+			return i
+		}
+		seen[base] = true
+	}
+	return len(queue)
+}
+
+// fetchMetadata fills in the metadata of jobs with one FetchArticles call.
+// If that fails, for example because arXiv rejects one of the IDs, every
+// paper is asked for on its own so that the error lands on the right one.
+func fetchMetadata(ctx context.Context, client *arxiv.Client, jobs []*fetchJob) {
+	ids := make([]string, len(jobs))
+	for i, job := range jobs {
+		ids[i] = job.item.id
+	}
+	metas, err := client.FetchArticles(ctx, ids)
+	if err == nil {
+		matchMetadata(jobs, metas)
+		return
+	}
+	for _, job := range jobs {
+		if ctx.Err() != nil {
+			job.err = fmt.Errorf("fetch metadata: %w", err)
+			continue
+		}
+		if job.meta, job.err = client.FetchArticle(ctx, job.item.id); job.err != nil {
+			job.err = fmt.Errorf("fetch metadata: %w", job.err)
+		}
+	}
+}
+
+// matchMetadata hands each job the metadata with its base ID. Jobs arXiv
+// returned nothing for fail.
+func matchMetadata(jobs []*fetchJob, metas []*arxiv.ArxivMeta) {
+	byID := make(map[string]*arxiv.ArxivMeta, len(metas))
+	for _, m := range metas {
+		byID[m.ArxivID] = m
+	}
+	for _, job := range jobs {
+		if job.meta = byID[job.id]; job.meta == nil {
+			job.err = fmt.Errorf("fetch metadata: %s not found on arXiv", job.item.id)
+		}
+	}
+}
+
+// downloadPDFs downloads the PDFs of jobs, up to workers at a time, and
+// prints a status line as each one finishes. The client's scheduler keeps
+// the requests within the rate limits however many workers there are; the
+// workers let one download's transfer overlap the next one's wait.
+func downloadPDFs(ctx context.Context, client *arxiv.Client, jobs []*fetchJob, workers int) {
+	var mu sync.Mutex
+	done := 0
+	forEachConcurrent(len(jobs), workers, func(i int) {
+		job := jobs[i]
+		err := downloadVersion(ctx, client, job.meta, job.dir, nil)
+
+		mu.Lock()
+		defer mu.Unlock()
+		done++
+		status := ""
+		if err != nil {
+			job.err = fmt.Errorf("download PDF: %w", err)
+			status = fmt.Sprintf("failed: %v", err)
+		} else {
+			status = "done"
+			if info, err := os.Stat(filepath.Join(job.dir, "paper.pdf")); err == nil {
+				status = formatSize(info.Size())
+			}
+		}
+		fmt.Printf("  [%*d/%d] %-16s %s\n", len(fmt.Sprint(len(jobs))), done, len(jobs), job.item.id, status)
+	})
+}
+
+// discardFetch removes what a failed fetch left in a directory it created:
+// the PDFs it downloaded and then the directory, if nothing else is in it.
+// A directory that existed before the fetch is left alone.
+func discardFetch(job *fetchJob) {
+	if job.existed {
+		return
+	}
+	if job.meta != nil && job.meta.Version > 0 {
+		_ = os.Remove(filepath.Join(job.dir, versionedPDFName(job.meta.Version)))
+	}
+	_ = os.Remove(filepath.Join(job.dir, "paper.pdf"))
+	_ = os.Remove(job.dir)
+}
+
+// forEachConcurrent calls fn for 0..n-1 with at most workers calls running
+// at once, and returns when all have returned.
+func forEachConcurrent(n, workers int, fn func(i int)) {
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(max(workers, 1), n); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+}
+
+// Results of a paper in a fetch.
+const (
+	fetchFetched = "fetched"
+	fetchSkipped = "skipped"
+	fetchFailed  = "failed"
+)
+
+// fetchOutcome is what became of one paper of a fetch.
+type fetchOutcome struct {
+	ID     string
+	Depth  int
+	Result string
+	Detail string
+	// Retry marks a failure worth recording for --retry-failed; retrying an
+	// ID that is not one is pointless.
+	Retry bool
+}
+
+// printFetchSummary prints a table of the outcomes, failures last, and the
+// totals.
+func printFetchSummary(outcomes []fetchOutcome) {
+	counts := make(map[string]int)
+	table := output.NewTable("Paper", "Result", "Details")
+	for _, result := range []string{fetchFetched, fetchSkipped, fetchFailed} {
+		for _, o := range outcomes {
+			if o.Result == result {
+				table.AddRow(o.ID, o.Result, truncate(o.Detail, 70))
+				counts[result]++
+			}
+		}
+	}
+	fmt.Println("Summary:")
+	table.Render()
+	fmt.Printf("\n%d fetched, %d skipped, %d failed\n", counts[fetchFetched], counts[fetchSkipped], counts[fetchFailed])
+}
+
+// failedFetchesFile records the papers whose last fetch failed, in the
+// research root, for fetch --retry-failed.
+const failedFetchesFile = "fetch-failed.yaml"
+
+// failedFetch is a recorded failure.
+type failedFetch struct {
+	ID       string `yaml:"id"`
+	Error    string `yaml:"error"`
+	FailedAt string `yaml:"failed_at"`
+}
+
+func failedFetchesPath(cfg *config.Config) string {
+	return filepath.Join(cfg.ResearchRoot, failedFetchesFile)
+}
+
+// loadFailedFetches reads the recorded failures. A missing file means
+// there are none.
+func loadFailedFetches(path string) ([]failedFetch, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read failed fetches: %w", err)
+	}
+	var failures struct {
+		Papers []failedFetch `yaml:"papers"`
+	}
+	if err := yaml.Unmarshal(data, &failures); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return failures.Papers, nil
+}
+
+// recordFetchOutcomes updates the recorded failures with the papers that
+// were asked for: failures are added or refreshed, and papers now in the
+// library are dropped. Others stay recorded. The file is removed once no
+// failure is left.
+func recordFetchOutcomes(path string, outcomes []fetchOutcome, now time.Time) error {
+	failures, err := loadFailedFetches(path)
+	if err != nil {
+		return err
+	}
+
+	byID := make(map[string]int, len(failures))
+	for i, f := range failures {
+		base, _ := arxiv.SplitVersion(f.ID)
+		byID[base] = i
+	}
+	drop := make(map[int]bool)
+	for _, o := range outcomes {
+		if o.Depth > 0 || (o.Result == fetchFailed && !o.Retry) {
+			continue
+		}
+		base, _ := arxiv.SplitVersion(o.ID)
+		i, recorded := byID[base]
+		switch {
+		case o.Result != fetchFailed && recorded:
+			drop[i] = true
+		case o.Result == fetchFailed && recorded:
+			failures[i] = failedFetch{ID: o.ID, Error: o.Detail, FailedAt: now.UTC().Format(time.RFC3339)}
+			delete(drop, i)
+		case o.Result == fetchFailed:
+			byID[base] = len(failures)
+			failures = append(failures, failedFetch{ID: o.ID, Error: o.Detail, FailedAt: now.UTC().Format(time.RFC3339)})
+		}
+	}
+
+	kept := make([]failedFetch, 0, len(failures))
+	for i, f := range failures {
+		if !drop[i] {
+			kept = append(kept, f)
+		}
+	}
+	if len(kept) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	data, err := yaml.Marshal(map[string][]failedFetch{"papers": kept})
+	if err != nil {
+		return err
+	}
+	return fsutil.WriteFile(path, data, 0o644)
+}
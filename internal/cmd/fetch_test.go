@@ -12,10 +12,12 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/mtreilly/arc-arxiv/internal/arxiv"
+	"github.com/mtreilly/arc-arxiv/internal/settings"
 	"gopkg.in/yaml.v3"
 )
 
@@ -1308,3 +1310,154 @@ func TestEdgeCases_SymlinkHandling(t *testing.T) {
 		t.Error("File should exist in real location")
 	}
 }
+
+func TestNextBatch(t *testing.T) {
+	queue := []fetchItem{{id: "2304.00067"}, {id: "2301.12345v2"}, {id: "2304.00067v1"}, {id: "2312.99999"}}
+	if n := nextBatch(queue); n != 2 {
+		t.Fatalf("nextBatch = %d, want 2 (stop before the repeated paper)", n)
+	}
+	if n := nextBatch(queue[2:]); n != 2 {
+		t.Fatalf("nextBatch of the rest = %d, want 2", n)
+	}
+}
+
+func TestMatchMetadata(t *testing.T) {
+	jobs := []*fetchJob{
+		{item: fetchItem{id: "2304.00067v1"}, id: "2304.00067", pinned: 1},
+		{item: fetchItem{id: "2301.12345"}, id: "2301.12345"},
+	}
+	matchMetadata(jobs, []*arxiv.ArxivMeta{{ArxivID: "2304.00067", Version: 1}})
+
+	if jobs[0].meta == nil || jobs[0].err != nil {
+		t.Fatalf("job 0: meta %v, err %v", jobs[0].meta, jobs[0].err)
+	}
+	if jobs[1].meta != nil || jobs[1].err == nil || !strings.Contains(jobs[1].err.Error(), "not found") {
+		t.Fatalf("job 1: meta %v, err %v", jobs[1].meta, jobs[1].err)
+	}
+}
+
+func TestForEachConcurrent(t *testing.T) {
+	var mu sync.Mutex
+	running, peak := 0, 0
+	seen := make([]bool, 20)
+	forEachConcurrent(len(seen), 3, func(i int) {
+		mu.Lock()
+		running++
+		peak = max(peak, running)
+		seen[i] = true
+		mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+		mu.Lock()
+		running--
+		mu.Unlock()
+	})
+	if peak > 3 {
+		t.Errorf("%d calls ran at once, want at most 3", peak)
+	}
+	for i, ok := range seen {
+		if !ok {
+			t.Errorf("fn not called for %d", i)
+		}
+	}
+}
+
+func TestFetchWorkers(t *testing.T) {
+	set := &settings.Settings{}
+	if n, err := fetchWorkers(0, set); err != nil || n != defaultFetchWorkers {
+		t.Errorf("default = %d, %v", n, err)
+	}
+	set.Fetch.Workers = 8
+	if n, _ := fetchWorkers(0, set); n != 8 {
+		t.Errorf("from settings = %d, want 8", n)
+	}
+	if n, _ := fetchWorkers(2, set); n != 2 {
+		t.Errorf("flag = %d, want 2", n)
+	}
+	if _, err := fetchWorkers(-1, set); err == nil {
+		t.Error("negative --workers accepted")
+	}
+}
+
+func TestRecordFetchOutcomes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), failedFetchesFile)
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	err := recordFetchOutcomes(path, []fetchOutcome{
+		{ID: "2304.00067", Result: fetchFetched},
+		{ID: "2301.12345v2", Result: fetchFailed, Detail: "download PDF: HTTP 503", Retry: true},
+		{ID: "2312.99999", Result: fetchFailed, Detail: "fetch metadata: not found", Retry: true},
+		{ID: "not-an-id", Result: fetchFailed, Detail: "invalid arXiv ID or URL"},
+		{ID: "2201.00001", Depth: 1, Result: fetchSkipped, Detail: "reference not fetched"},
+	}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	failures, err := loadFailedFetches(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(failures) != 2 || failures[0].ID != "2301.12345v2" || failures[1].ID != "2312.99999" {
+		t.Fatalf("failures = %+v", failures)
+	}
+	if failures[0].Error != "download PDF: HTTP 503" || failures[0].FailedAt != "2025-03-01T12:00:00Z" {
+		t.Errorf("failure = %+v", failures[0])
+	}
+
+	// A paper fetched later drops off the record; the other stays until it is
+	// in the library too.
+	if err := recordFetchOutcomes(path, []fetchOutcome{
+		{ID: "2301.12345v2", Result: fetchFetched},
+	}, now); err != nil {
+		t.Fatal(err)
+	}
+	if failures, _ = loadFailedFetches(path); len(failures) != 1 || failures[0].ID != "2312.99999" {
+		t.Fatalf("after retry: %+v", failures)
+	}
+	if err := recordFetchOutcomes(path, []fetchOutcome{
+		{ID: "2312.99999", Result: fetchSkipped, Detail: "already in library"},
+	}, now); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("record not removed once empty: %v", err)
+	}
+}
+
+func TestDiscardFetch(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "2304.00067")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"paper.v2.pdf", "paper.pdf"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("%PDF"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	job := &fetchJob{dir: dir, meta: &arxiv.ArxivMeta{Version: 2}}
+	discardFetch(job)
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("new directory not removed: %v", err)
+	}
+
+	// Files this fetch did not write are never removed.
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	other := filepath.Join(dir, "mine.txt")
+	if err := os.WriteFile(other, []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	discardFetch(job)
+	if _, err := os.Stat(other); err != nil {
+		t.Errorf("unrelated file removed: %v", err)
+	}
+
+	job.existed = true
+	if err := os.WriteFile(filepath.Join(dir, "paper.pdf"), []byte("%PDF"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	discardFetch(job)
+	if _, err := os.Stat(filepath.Join(dir, "paper.pdf")); err != nil {
+		t.Errorf("PDF in an existing directory removed: %v", err)
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mtreilly/arc-arxiv/internal/arxiv"
+	"github.com/mtreilly/arc-arxiv/internal/fulltext"
+	"github.com/mtreilly/arc-arxiv/internal/pdftext"
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-sdk/config"
+	"github.com/yourorg/arc-sdk/output"
+)
+
+// Relative weight of each searchable field when ranking local papers.
+var findFieldWeights = map[string]float64{
+	"title":    3.0,
+	"abstract": 1.5,
+	"notes":    1.2,
+	"body":     0.5,
+}
+
+type findResult struct {
+	ID      string           `json:"id"`
+	Title   string           `json:"title"`
+	Score   float64          `json:"score"`
+	Matches []fulltext.Match `json:"matches"`
+}
+
+func newFindCmd(cfg *config.Config) *cobra.Command {
+	var out output.OutputOptions
+	var limit int
+	var fields []string
+
+	cmd := &cobra.Command{
+		Use:   "find <query>",
+		Short: "Full-text search over downloaded papers",
+		Long: `Search the local library by title, abstract, notes.md and body.md.
+
+Works offline: only the files under papers/<id>/ are read. Every query word
+must appear in at least one field; results are ranked by BM25 with title
+matches weighted highest.
+
+Examples:
+  arc-arxiv find "sparse attention"
+  arc-arxiv find renormalization --in notes,body
+  arc-arxiv find "diffusion" --limit 5 --output json`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := out.Resolve(); err != nil {
+				return err
+			}
+
+			query := strings.Join(args, " ")
+			if len(fulltext.Terms(query)) == 0 {
+				return fmt.Errorf("query has no searchable words")
+			}
+
+			include := make(map[string]bool)
+			for _, f := range fields {
+				f = strings.ToLower(strings.TrimSpace(f))
+				if _, ok := findFieldWeights[f]; !ok {
+					return fmt.Errorf("unknown field: %s (use title, abstract, notes, body)", f)
+				}
+				include[f] = true
+			}
+
+			papersRoot := filepath.Join(cfg.ResearchRoot, "papers")
+			entries, _, err := scanLibrary(papersRoot)
+			if err != nil {
+				return err
+			}
+			if len(entries) == 0 {
+				fmt.Println("No papers downloaded yet.")
+				return nil
+			}
+
+			metas := make(map[string]*arxiv.ArxivMeta, len(entries))
+			docs := make([]fulltext.Doc, 0, len(entries))
+			for _, e := range entries {
+				metas[e.Meta.ArxivID] = e.Meta
+				docs = append(docs, findDoc(e.Meta, e.Dir, include))
+			}
+
+			highlight := func(s string) string { return "**" + s + "**" }
+			if isTerminal(os.Stdout) {
+				highlight = func(s string) string { return "\x1b[1;33m" + s + "\x1b[0m" }
+			}
+			if out.Is(output.OutputJSON) {
+				highlight = nil
+			}
+
+			results := fulltext.Search(docs, query, fulltext.Options{Limit: limit, Highlight: highlight})
+			if len(results) == 0 {
+				fmt.Println("No matching papers.")
+				return nil
+			}
+
+			if out.Is(output.OutputJSON) {
+				found := make([]findResult, 0, len(results))
+				for _, r := range results {
+					found = append(found, findResult{ID: r.ID, Title: metas[r.ID].Title, Score: r.Score, Matches: r.Matches})
+				}
+				return output.JSON(found)
+			}
+
+			for _, r := range results {
+				fmt.Printf("%s  %s  (score %.2f)\n", r.ID, truncate(metas[r.ID].Title, 70), r.Score)
+				for _, m := range r.Matches {
+					fmt.Printf("  %-10s %s\n", m.Field+":", m.Snippet)
+				}
+				fmt.Println()
+			}
+			fmt.Printf("%d matching paper(s).\n", len(results))
+
+			return nil
+		},
+	}
+
+	out.AddOutputFlags(cmd, output.OutputTable)
+	cmd.Flags().IntVarP(&limit, "limit", "l", 20, "Maximum number of results (0 for all)")
+	cmd.Flags().StringSliceVar(&fields, "in", nil, "Restrict search to fields: title, abstract, notes, body")
+
+	return cmd
+}
+
+// findDoc builds the searchable document for a paper. Missing notes.md or
+// body.md files simply contribute no text.
+func findDoc(meta *arxiv.ArxivMeta, dir string, include map[string]bool) fulltext.Doc {
+	texts := map[string]string{
+		"title":    meta.Title,
+		"abstract": meta.Abstract,
+	}
+	for field, name := range map[string]string{"notes": "notes.md", "body": "body.md"} {
+		if len(include) > 0 && !include[field] {
+			continue
+		}
+		if data, err := os.ReadFile(filepath.Join(dir, name)); err == nil {
+			texts[field] = pdftext.StripMarkers(string(data))
+		}
+	}
+
+	doc := fulltext.Doc{ID: meta.ArxivID}
+	for _, field := range []string{"title", "abstract", "notes", "body"} {
+		if len(include) > 0 && !include[field] {
+			continue
+		}
+		doc.Fields = append(doc.Fields, fulltext.Field{Name: field, Text: texts[field], Weight: findFieldWeights[field]})
+	}
+	return doc
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
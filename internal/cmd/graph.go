@@ -0,0 +1,207 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/mtreilly/arc-arxiv/internal/graph"
+	"github.com/mtreilly/arc-arxiv/internal/index"
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-sdk/config"
+	"github.com/yourorg/arc-sdk/output"
+)
+
+func newGraphCmd(cfg *config.Config, db *sql.DB) *cobra.Command {
+	var format string
+	var outputFile string
+	var tag string
+	var category string
+	var top int
+	var minCited int
+	var withMissing bool
+
+	cmd := &cobra.Command{
+		Use:   "graph",
+		Short: "Citation graph of the library",
+		Long: `Build the citation graph between the papers in the library from their
+references.yaml files (see the refs command) and report on it: the most
+cited papers, papers with no citations in either direction, and papers
+missing from the library that several library papers cite.
+
+With --format the graph is exported instead, as dot (Graphviz), graphml
+or json. --with-missing adds the heavily cited missing papers as nodes.
+
+--tag and --category restrict the graph to matching papers; citations to
+papers outside the selection are left out.
+
+Examples:
+  arc-arxiv graph
+  arc-arxiv graph --category cs.CL --top 20
+  arc-arxiv graph --format dot -o library.dot && dot -Tsvg library.dot > library.svg
+  arc-arxiv graph --tag attention --format graphml --with-missing -o attention.graphml`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			format = strings.ToLower(format)
+			if format != "" && !slices.Contains(graph.Formats, format) {
+				return fmt.Errorf("unknown format: %s (use %s)", format, strings.Join(graph.Formats, ", "))
+			}
+
+			g, unparsed, err := libraryGraph(ctx, cfg, db, index.Filter{Tag: tag, Category: category})
+			if err != nil {
+				return err
+			}
+			if len(g.Nodes) == 0 {
+				fmt.Println("No matching papers.")
+				return nil
+			}
+
+			if format == "" {
+				printGraphReport(g, unparsed, top, minCited)
+				return nil
+			}
+
+			if withMissing {
+				g.AddMissing(minCited)
+			}
+			var buf bytes.Buffer
+			if err := g.Write(&buf, format); err != nil {
+				return err
+			}
+			if outputFile == "" {
+				fmt.Print(buf.String())
+				return nil
+			}
+			if err := os.WriteFile(outputFile, buf.Bytes(), 0o644); err != nil {
+				return fmt.Errorf("write file: %w", err)
+			}
+			fmt.Printf("Wrote graph of %d paper(s) and %d citation(s) to %s\n", len(g.Nodes), len(g.Edges), outputFile)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&format, "format", "f", "", "Export format: dot, graphml, json (default: print a report)")
+	cmd.Flags().StringVarP(&outputFile, "output", "o", "", "Write the export to file")
+	cmd.Flags().StringVar(&tag, "tag", "", "Only papers with this tag")
+	cmd.Flags().StringVarP(&category, "category", "c", "", "Only papers in this category (e.g., cs.LG or cs)")
+	cmd.Flags().IntVar(&top, "top", 10, "Number of papers in each report list (0 for all)")
+	cmd.Flags().IntVar(&minCited, "min-cited", 2, "Report missing papers cited by at least this many library papers")
+	cmd.Flags().BoolVar(&withMissing, "with-missing", false, "Include heavily cited missing papers in the export")
+
+	return cmd
+}
+
+// libraryGraph builds the citation graph of the papers matching filter. The
+// references are linked against the whole library again, so papers fetched
+// after a references.yaml was written are picked up. unparsed counts the
+// papers without a references.yaml.
+func libraryGraph(ctx context.Context, cfg *config.Config, db *sql.DB, filter index.Filter) (*graph.Graph, int, error) {
+	ix, err := openIndex(ctx, cfg, db)
+	if err != nil {
+		return nil, 0, err
+	}
+	entries, err := ix.List(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	lib, err := loadLibraryLinks(filepath.Join(cfg.ResearchRoot, "papers"))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	unparsed := 0
+	papers := make([]graph.Paper, 0, len(entries))
+	for _, e := range entries {
+		p := graph.Paper{
+			ID:         e.Meta.ArxivID,
+			Title:      e.Meta.Title,
+			Categories: e.Meta.Categories,
+			Tags:       e.Meta.Tags,
+		}
+		if len(e.Meta.Published) >= 4 {
+			p.Year = e.Meta.Published[:4]
+		}
+		if list, err := readReferences(e.Dir); err == nil {
+			lib.link(list.References, p.ID)
+			p.References, p.HasReferences = list.References, true
+		} else {
+			unparsed++
+		}
+		papers = append(papers, p)
+	}
+	return graph.Build(papers), unparsed, nil
+}
+
+func printGraphReport(g *graph.Graph, unparsed, top, minCited int) {
+	fmt.Printf("Citation graph: %d paper(s), %d citation(s) between them.\n", len(g.Nodes), len(g.Edges))
+	if unparsed > 0 {
+		fmt.Printf("%d paper(s) have no references.yaml yet (run 'arc-arxiv refs <id>').\n", unparsed)
+	}
+
+	if cited := g.MostCited(top); len(cited) > 0 {
+		fmt.Printf("\nMost cited in the library:\n")
+		table := output.NewTable("ID", "Title", "Cited by")
+		for _, n := range cited {
+			table.AddRow(n.ID, truncate(n.Title, 60), fmt.Sprintf("%d", n.CitedBy))
+		}
+		table.Render()
+	}
+
+	if isolated := g.Isolated(); len(isolated) > 0 {
+		fmt.Printf("\nIsolated (no citations to or from other library papers): %d\n", len(isolated))
+		table := output.NewTable("ID", "Title", "References")
+		shown := isolated
+		if top > 0 && len(shown) > top {
+			shown = shown[:top]
+		}
+		for _, n := range shown {
+			parsed := "parsed"
+			if !n.HasReferences {
+				parsed = "not parsed"
+			}
+			table.AddRow(n.ID, truncate(n.Title, 60), parsed)
+		}
+		table.Render()
+		if len(isolated) > len(shown) {
+			fmt.Printf("  ... and %d more\n", len(isolated)-len(shown))
+		}
+	}
+
+	heavy := g.HeavilyCited(minCited)
+	if len(heavy) == 0 {
+		return
+	}
+	fmt.Printf("\nMissing from the library, cited by %d or more papers:\n", minCited)
+	table := output.NewTable("arXiv / DOI", "Title", "Cited by")
+	var fetchable []string
+	for i, m := range heavy {
+		if top > 0 && i == top {
+			break
+		}
+		ident := m.ArxivID
+		if ident == "" {
+			ident = m.DOI
+		}
+		if m.ArxivID != "" {
+			fetchable = append(fetchable, m.ArxivID)
+		}
+		table.AddRow(ident, truncate(m.Title, 50), fmt.Sprintf("%d", len(m.CitedBy)))
+	}
+	table.Render()
+	if len(fetchable) > 0 {
+		fmt.Printf("\nFetch them with: arc-arxiv fetch %s\n", strings.Join(fetchable, " "))
+	}
+}
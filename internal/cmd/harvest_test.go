@@ -0,0 +1,61 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mtreilly/arc-arxiv/internal/arxiv"
+)
+
+func TestHarvestRecords(t *testing.T) {
+	record := func(id string) string {
+		return `<record><header><identifier>oai:arXiv.org:` + id + `</identifier></header><metadata>
+<arXivRaw><id>` + id + `</id><title>Paper ` + id + `</title><license>CC BY 4.0</license></arXivRaw></metadata></record>`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("resumptionToken") {
+		case "":
+			fmt.Fprint(w, `<OAI-PMH><ListRecords>`+record("2501.00001")+record("2501.00002")+
+				`<resumptionToken completeListSize="3">next</resumptionToken></ListRecords></OAI-PMH>`)
+		default:
+			http.Error(w, "unavailable", http.StatusBadGateway)
+		}
+	}))
+	defer srv.Close()
+
+	fast := arxiv.NewScheduler(arxiv.Limits{Interval: time.Millisecond, Backoff: time.Millisecond, MaxRetries: 1})
+	client, err := arxiv.NewClient(arxiv.WithOAIBaseURL(srv.URL), arxiv.WithScheduler(fast), arxiv.WithCache(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out, status bytes.Buffer
+	n, err := harvestRecords(context.Background(), client, arxiv.HarvestOptions{Set: "cs"}, &out, &status)
+	var herr *arxiv.HarvestError
+	if !errors.As(err, &herr) || herr.Token != "next" || n != 2 {
+		t.Fatalf("n = %d, err = %v", n, err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("output = %q", out.String())
+	}
+	var meta arxiv.ArxivMeta
+	if err := json.Unmarshal([]byte(lines[1]), &meta); err != nil || meta.ArxivID != "2501.00002" || meta.License != "CC BY 4.0" {
+		t.Errorf("line 2 = %s (%v)", lines[1], err)
+	}
+	if !strings.Contains(status.String(), "2 record(s) of 3") {
+		t.Errorf("status = %q", status.String())
+	}
+}
@@ -0,0 +1,218 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mtreilly/arc-arxiv/internal/arxiv"
+)
+
+// libraryID maps a user-supplied arXiv ID or URL to the key the library is
+// stored under: the base ID without a version suffix. pinned is the version
+// the input asked for, or 0. Input that is not a valid arXiv ID is returned
+// unchanged so hand-made directories can still be looked up.
+func libraryID(input string) (id string, pinned int) {
+	normalized, err := arxiv.NormalizeArxivID(input)
+	if err != nil {
+		// Accept directory names such as hep-th-9901001 too.
+		if id, ok := arxiv.IDFromDirName(input); ok {
+			return arxiv.SplitVersion(id)
+		}
+		return input, 0
+	}
+	return arxiv.SplitVersion(normalized)
+}
+
+// libraryDir returns the directory holding a paper. Every version of a paper
+// shares the directory of its base ID, and old-style IDs are flattened by
+// arxiv.DirName so that every paper sits directly under papersRoot.
+func libraryDir(papersRoot, id string) string {
+	base, _ := arxiv.SplitVersion(id)
+	return filepath.Join(papersRoot, arxiv.DirName(base))
+}
+
+// carryUserFields copies the fields a user maintains locally (tags,
+// collections and reading state) from prev into meta, so that re-fetching or
+// updating a paper from arXiv never drops them. prev may be nil.
+func carryUserFields(meta, prev *arxiv.ArxivMeta) {
+	if prev == nil {
+		return
+	}
+	meta.Tags = prev.Tags
+	meta.Collections = prev.Collections
+	meta.Status = prev.Status
+	meta.Priority = prev.Priority
+	meta.Rating = prev.Rating
+}
+
+// editPapers applies edit to the metadata of each paper in args, writing
+// back and re-indexing only papers for which edit reports a change. It
+// returns how many papers changed; papers that cannot be found are skipped
+// and reported in the returned error.
+func editPapers(ctx context.Context, db *sql.DB, papersRoot string, args []string, edit func(*arxiv.ArxivMeta) bool) (int, error) {
+	changed := 0
+	var missing []string
+	for _, arg := range args {
+		id, _ := libraryID(arg)
+		dir := libraryDir(papersRoot, id)
+		metaPath := filepath.Join(dir, "meta.yaml")
+		meta, err := readMeta(metaPath)
+		if err != nil {
+			missing = append(missing, id)
+			continue
+		}
+
+		if !edit(meta) {
+			continue
+		}
+		if err := writeMeta(metaPath, meta); err != nil {
+			return changed, fmt.Errorf("write meta for %s: %w", id, err)
+		}
+		syncIndex(ctx, db, papersRoot, meta, dir)
+		changed++
+	}
+	if len(missing) > 0 {
+		return changed, fmt.Errorf("paper(s) not found: %s", strings.Join(missing, ", "))
+	}
+	return changed, nil
+}
+
+// versionedDirBase reports whether a paper directory name carries a version
+// suffix (as created by fetching "<id>vN" before the library was keyed on
+// base IDs) and returns the base ID and version.
+func versionedDirBase(name string) (string, int, bool) {
+	id, ok := arxiv.IDFromDirName(name)
+	if !ok {
+		return "", 0, false
+	}
+	base, version := arxiv.SplitVersion(id)
+	return base, version, version > 0
+}
+
+// mergeVersionedDir folds a "<id>vN" paper directory into the canonical
+// directory of its base ID and returns the canonical path.
+//
+// If no canonical directory exists, the versioned one is renamed and pinned
+// to vN, since that is the version that was asked for. Otherwise its PDF
+// becomes paper.vN.pdf, identical files are dropped, and any other file that
+// clashes is kept with a ".vN" suffix (notes.md becomes notes.vN.md). The
+// canonical meta.yaml always wins.
+func mergeVersionedDir(src string) (string, error) {
+	base, version, ok := versionedDirBase(filepath.Base(src))
+	if !ok {
+		return "", fmt.Errorf("%s is not a versioned paper directory", filepath.Base(src))
+	}
+	dst := libraryDir(filepath.Dir(src), base)
+
+	if _, err := os.Stat(dst); os.IsNotExist(err) {
+		if err := os.Rename(src, dst); err != nil {
+			return "", err
+		}
+		if err := adoptLegacyPDF(dst, version); err != nil {
+			return dst, err
+		}
+		metaPath := filepath.Join(dst, "meta.yaml")
+		if meta, err := readMeta(metaPath); err == nil {
+			meta.PinnedVersion = version
+			if err := writeMeta(metaPath, meta); err != nil {
+				return dst, err
+			}
+		}
+		return dst, nil
+	} else if err != nil {
+		return "", err
+	}
+
+	dstMeta, _ := readMeta(filepath.Join(dst, "meta.yaml"))
+	pinned := 0
+	if dstMeta != nil {
+		pinned = dstMeta.PinnedVersion
+		if err := adoptLegacyPDF(dst, dstMeta.Version); err != nil {
+			return "", err
+		}
+	}
+
+	// paper.pdf is only repointed when its version is known; an unversioned
+	// canonical PDF of unknown age is left in place.
+	_, statErr := os.Stat(filepath.Join(dst, "paper.pdf"))
+	promote := len(localVersions(dst)) > 0 || os.IsNotExist(statErr)
+
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return "", err
+	}
+	for _, e := range entries {
+		from := filepath.Join(src, e.Name())
+		to := filepath.Join(dst, e.Name())
+		switch e.Name() {
+		case "paper.pdf":
+			to = filepath.Join(dst, versionedPDFName(version))
+		case "meta.yaml":
+			if dstMeta != nil {
+				if err := os.Remove(from); err != nil {
+					return "", err
+				}
+				continue
+			}
+		}
+
+		if _, err := os.Lstat(to); err == nil {
+			if sameFile(from, to) {
+				if err := os.RemoveAll(from); err != nil {
+					return "", err
+				}
+				continue
+			}
+			to = withVersionSuffix(to, version)
+			if _, err := os.Lstat(to); err == nil {
+				return "", fmt.Errorf("cannot merge %s: %s already exists", from, to)
+			}
+		}
+		if err := os.Rename(from, to); err != nil {
+			return "", err
+		}
+	}
+
+	if err := os.Remove(src); err != nil {
+		return "", err
+	}
+	if !promote {
+		return dst, nil
+	}
+	return dst, promotePDF(dst, pinned)
+}
+
+// withVersionSuffix turns "dir/notes.md" into "dir/notes.v2.md".
+func withVersionSuffix(path string, version int) string {
+	ext := filepath.Ext(path)
+	return fmt.Sprintf("%s.v%d%s", strings.TrimSuffix(path, ext), version, ext)
+}
+
+// sameFile reports whether two regular files have identical contents.
+func sameFile(a, b string) bool {
+	ia, err := os.Stat(a)
+	if err != nil || !ia.Mode().IsRegular() {
+		return false
+	}
+	ib, err := os.Stat(b)
+	if err != nil || !ib.Mode().IsRegular() || ia.Size() != ib.Size() {
+		return false
+	}
+	da, err := os.ReadFile(a)
+	if err != nil {
+		return false
+	}
+	db, err := os.ReadFile(b)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(da, db)
+}
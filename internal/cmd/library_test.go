@@ -0,0 +1,113 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLibraryID(t *testing.T) {
+	tests := []struct {
+		input  string
+		id     string
+		pinned int
+	}{
+		{"2304.00067", "2304.00067", 0},
+		{"2304.00067v2", "2304.00067", 2},
+		{"https://arxiv.org/abs/2304.00067v3", "2304.00067", 3},
+		{"hep-th/9901001v1", "hep-th/9901001", 1},
+		{"my-notes", "my-notes", 0},
+	}
+	for _, tt := range tests {
+		id, pinned := libraryID(tt.input)
+		if id != tt.id || pinned != tt.pinned {
+			t.Errorf("libraryID(%q) = %q, %d; want %q, %d", tt.input, id, pinned, tt.id, tt.pinned)
+		}
+	}
+
+	if got := libraryDir("/lib", "2304.00067v2"); got != filepath.Join("/lib", "2304.00067") {
+		t.Errorf("libraryDir = %q", got)
+	}
+}
+
+func TestMergeVersionedDir(t *testing.T) {
+	t.Run("renames when no canonical directory exists", func(t *testing.T) {
+		root := t.TempDir()
+		src := writeTestPaper(t, root, "2304.00067v2", "2304.00067", validPDF)
+
+		dst, err := mergeVersionedDir(src)
+		if err != nil {
+			t.Fatalf("mergeVersionedDir: %v", err)
+		}
+		if dst != filepath.Join(root, "2304.00067") {
+			t.Errorf("dst = %q", dst)
+		}
+		if _, err := os.Stat(src); !os.IsNotExist(err) {
+			t.Error("versioned directory should be gone")
+		}
+		meta, err := readMeta(filepath.Join(dst, "meta.yaml"))
+		if err != nil {
+			t.Fatalf("readMeta: %v", err)
+		}
+		if meta.PinnedVersion != 2 {
+			t.Errorf("PinnedVersion = %d, want 2", meta.PinnedVersion)
+		}
+		if got := readFile(t, filepath.Join(dst, "paper.v2.pdf")); got != validPDF {
+			t.Error("paper.v2.pdf should hold the versioned PDF")
+		}
+	})
+
+	t.Run("folds into existing canonical directory", func(t *testing.T) {
+		root := t.TempDir()
+		dst := writeTestPaper(t, root, "2304.00067", "2304.00067", validPDF)
+		writeFile(t, filepath.Join(dst, "notes.md"), "my notes on v3")
+		src := writeTestPaper(t, root, "2304.00067v1", "2304.00067", "%PDF-1.4 old\n%%EOF\n")
+		writeFile(t, filepath.Join(src, "notes.md"), "my notes on v1")
+		writeFile(t, filepath.Join(src, "figure.png"), "png")
+
+		got, err := mergeVersionedDir(src)
+		if err != nil {
+			t.Fatalf("mergeVersionedDir: %v", err)
+		}
+		if got != dst {
+			t.Errorf("dst = %q, want %q", got, dst)
+		}
+		if _, err := os.Stat(src); !os.IsNotExist(err) {
+			t.Error("versioned directory should be gone")
+		}
+
+		if v := readFile(t, filepath.Join(dst, "paper.v1.pdf")); v != "%PDF-1.4 old\n%%EOF\n" {
+			t.Errorf("paper.v1.pdf = %q", v)
+		}
+		if v := readFile(t, filepath.Join(dst, "paper.pdf")); v != validPDF {
+			t.Errorf("canonical paper.pdf of unknown version should be kept, got %q", v)
+		}
+		if v := readFile(t, filepath.Join(dst, "notes.md")); v != "my notes on v3" {
+			t.Errorf("canonical notes.md changed to %q", v)
+		}
+		if v := readFile(t, filepath.Join(dst, "notes.v1.md")); v != "my notes on v1" {
+			t.Errorf("notes.v1.md = %q", v)
+		}
+		if v := readFile(t, filepath.Join(dst, "figure.png")); v != "png" {
+			t.Errorf("figure.png = %q", v)
+		}
+
+		meta, err := readMeta(filepath.Join(dst, "meta.yaml"))
+		if err != nil {
+			t.Fatalf("readMeta: %v", err)
+		}
+		if meta.PinnedVersion != 0 {
+			t.Errorf("canonical paper should not be pinned, got v%d", meta.PinnedVersion)
+		}
+	})
+
+	t.Run("rejects unversioned directory", func(t *testing.T) {
+		src := writeTestPaper(t, t.TempDir(), "2304.00067", "2304.00067", validPDF)
+		if _, err := mergeVersionedDir(src); err == nil {
+			t.Error("expected error for unversioned directory")
+		}
+	})
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/mtreilly/arc-arxiv/internal/arxiv"
+	"github.com/mtreilly/arc-arxiv/internal/index"
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-sdk/config"
+)
+
+// Reading statuses, in the order a paper usually moves through them.
+var readingStatuses = []string{index.DefaultStatus, "reading", "read", "skipped"}
+
+// Priorities, highest first.
+var priorities = []string{"high", "medium", "low"}
+
+const maxRating = 5
+
+func newMarkCmd(cfg *config.Config, db *sql.DB) *cobra.Command {
+	var status string
+	var priority string
+	var rating int
+
+	cmd := &cobra.Command{
+		Use:   "mark <id> [id...]",
+		Short: "Set reading status, priority or rating",
+		Long: `Record how far you are with a paper. The values are stored in meta.yaml and
+kept by update and fetch --force.
+
+  --status    unread, reading, read or skipped
+  --priority  high, medium or low ("none" clears it)
+  --rating    1 to 5 (0 clears it)
+
+Examples:
+  arc-arxiv mark 2304.00067 --status reading
+  arc-arxiv mark 2304.00067 --status read --rating 4
+  arc-arxiv mark 2301.12345 2312.99999 --priority high
+  arc-arxiv list --status unread --sort priority`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			setStatus := cmd.Flags().Changed("status")
+			setPriority := cmd.Flags().Changed("priority")
+			setRating := cmd.Flags().Changed("rating")
+			if !setStatus && !setPriority && !setRating {
+				return fmt.Errorf("nothing to mark: use --status, --priority or --rating")
+			}
+
+			status = strings.ToLower(strings.TrimSpace(status))
+			if setStatus && !slices.Contains(readingStatuses, status) {
+				return fmt.Errorf("invalid status %q (use %s)", status, strings.Join(readingStatuses, ", "))
+			}
+			priority = strings.ToLower(strings.TrimSpace(priority))
+			if priority == "none" {
+				priority = ""
+			}
+			if setPriority && priority != "" && !slices.Contains(priorities, priority) {
+				return fmt.Errorf("invalid priority %q (use %s or none)", priority, strings.Join(priorities, ", "))
+			}
+			if setRating && (rating < 0 || rating > maxRating) {
+				return fmt.Errorf("invalid rating %d (use 1-%d, or 0 to clear)", rating, maxRating)
+			}
+
+			papersRoot := filepath.Join(cfg.ResearchRoot, "papers")
+			changed, err := editPapers(ctx, db, papersRoot, args, func(meta *arxiv.ArxivMeta) bool {
+				before := *meta
+				if setStatus {
+					// unread is the default and is not written out.
+					meta.Status = status
+					if status == index.DefaultStatus {
+						meta.Status = ""
+					}
+				}
+				if setPriority {
+					meta.Priority = priority
+				}
+				if setRating {
+					meta.Rating = rating
+				}
+				return meta.Status != before.Status || meta.Priority != before.Priority || meta.Rating != before.Rating
+			})
+			fmt.Printf("Marked %d paper(s).\n", changed)
+			return err
+		},
+	}
+
+	cmd.Flags().StringVarP(&status, "status", "s", "", "Reading status: unread, reading, read, skipped")
+	cmd.Flags().StringVarP(&priority, "priority", "p", "", "Priority: high, medium, low, none")
+	cmd.Flags().IntVarP(&rating, "rating", "r", 0, "Rating from 1 to 5 (0 clears)")
+
+	return cmd
+}
+
+// readingStatus returns a paper's reading status, defaulting to unread.
+func readingStatus(meta *arxiv.ArxivMeta) string {
+	if meta.Status == "" {
+		return index.DefaultStatus
+	}
+	return meta.Status
+}
+
+// priorityRank orders priorities for sorting: high first, unset last.
+func priorityRank(p string) int {
+	if i := slices.Index(priorities, p); i >= 0 {
+		return i
+	}
+	return len(priorities)
+}
@@ -0,0 +1,54 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/mtreilly/arc-arxiv/internal/arxiv"
+)
+
+func TestSortPapers(t *testing.T) {
+	papers := func() []*arxiv.ArxivMeta {
+		return []*arxiv.ArxivMeta{
+			{ArxivID: "a", Priority: "low", Rating: 2, Status: "read", FetchedAt: "2024-01-01T00:00:00Z"},
+			{ArxivID: "b", Rating: 5, FetchedAt: "2024-03-01T00:00:00Z"},
+			{ArxivID: "c", Priority: "high", Status: "reading", FetchedAt: "2024-02-01T00:00:00Z"},
+		}
+	}
+	ids := func(ps []*arxiv.ArxivMeta) string {
+		s := ""
+		for _, p := range ps {
+			s += p.ArxivID
+		}
+		return s
+	}
+
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"id", "abc"},
+		{"fetched", "bca"},
+		{"priority", "cab"},
+		{"rating", "bac"},
+		{"status", "bca"},
+	}
+	for _, tt := range tests {
+		ps := papers()
+		sortPapers(ps, tt.key)
+		if got := ids(ps); got != tt.want {
+			t.Errorf("sortPapers(%s) = %s, want %s", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestReadingStatus(t *testing.T) {
+	if got := readingStatus(&arxiv.ArxivMeta{}); got != "unread" {
+		t.Errorf("readingStatus(empty) = %q, want unread", got)
+	}
+	if got := readingStatus(&arxiv.ArxivMeta{Status: "skipped"}); got != "skipped" {
+		t.Errorf("readingStatus = %q", got)
+	}
+}
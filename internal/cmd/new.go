@@ -0,0 +1,269 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mtreilly/arc-arxiv/internal/arxiv"
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-sdk/config"
+	"github.com/yourorg/arc-sdk/output"
+)
+
+// Listing groups, in the order the arXiv listing page shows them.
+const (
+	groupNew     = "new"
+	groupCross   = "cross"
+	groupReplace = "replace"
+)
+
+var listingGroups = []struct {
+	name, heading string
+}{
+	{groupNew, "New submissions"},
+	{groupCross, "Cross-lists"},
+	{groupReplace, "Replacements"},
+}
+
+// listingEntry is a numbered paper of a daily listing. It embeds the
+// metadata so that JSON output has the fields of search results.
+type listingEntry struct {
+	Number       int
+	AnnounceType string
+	*arxiv.ArxivMeta
+}
+
+func newNewCmd(cfg *config.Config, db *sql.DB) *cobra.Command {
+	var out output.OutputOptions
+	var only []string
+	var fetch string
+
+	cmd := &cobra.Command{
+		Use:   "new <category> [category...]",
+		Short: "Show a category's latest daily listing",
+		Long: `Show the papers of a category's latest arXiv announcement, grouped as on
+the listing page: new submissions, cross-lists from other categories and
+replacements. Several categories are listed together. There is no
+announcement on weekends and holidays.
+
+Entries are numbered through the whole listing, and papers already in the
+library are marked. --fetch downloads the chosen entries: numbers and
+ranges (1,4,7-9), a group (new, cross, replace) or all. The numbers stay
+the same with --only, so a listing can be narrowed and fetched in two
+steps.
+
+Examples:
+  arc-arxiv new cs.LG                          # Today's cs.LG listing
+  arc-arxiv new cs.LG stat.ML --only new       # New submissions of both
+  arc-arxiv new cs.LG --fetch 3,7-9            # Fetch entries 3, 7, 8 and 9
+  arc-arxiv new hep-th --fetch new             # Fetch every new submission
+  arc-arxiv new cs.CL --output json            # Machine-readable listing`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := out.Resolve(); err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			for i, g := range only {
+				only[i] = strings.ToLower(strings.TrimSpace(g))
+				if listingGroup(only[i]) == "" {
+					return fmt.Errorf("invalid group %q (use new, cross or replace)", g)
+				}
+			}
+
+			client, err := newClient(cmd, cfg)
+			if err != nil {
+				return err
+			}
+			listingCtx, report := arxiv.WithCacheReport(ctx)
+			listing, err := client.Listing(listingCtx, args...)
+			if err != nil {
+				return err
+			}
+			warnStale(report)
+
+			entries := numberListing(listing.Entries)
+			var ids []string
+			if fetch != "" {
+				if ids, err = selectEntries(entries, fetch); err != nil {
+					return err
+				}
+			}
+
+			shown := entries
+			if len(only) > 0 {
+				shown = nil
+				for _, e := range entries {
+					if slices.Contains(only, listingGroup(e.AnnounceType)) {
+						shown = append(shown, e)
+					}
+				}
+			}
+
+			if out.Is(output.OutputJSON) {
+				if shown == nil {
+					shown = []*listingEntry{}
+				}
+				if err := output.JSON(shown); err != nil {
+					return err
+				}
+			} else {
+				printListing(listing, shown, filepath.Join(cfg.ResearchRoot, "papers"))
+			}
+
+			if len(ids) == 0 {
+				return nil
+			}
+			if !out.Is(output.OutputJSON) {
+				fmt.Printf("\nFetching %d paper(s)...\n", len(ids))
+			}
+			fetchCmd := newFetchCmd(cfg, db)
+			fetchCmd.SetContext(ctx)
+			return fetchCmd.RunE(fetchCmd, ids)
+		},
+	}
+
+	out.AddOutputFlags(cmd, output.OutputTable)
+	cmd.Flags().StringSliceVar(&only, "only", nil, "Show only these groups: new, cross, replace")
+	cmd.Flags().StringVar(&fetch, "fetch", "", "Fetch entries by number or range (1,4,7-9), group, or all")
+
+	return cmd
+}
+
+// listingGroup maps an announcement type to its listing group, or "" if it
+// names none.
+func listingGroup(announceType string) string {
+	switch announceType {
+	case arxiv.AnnounceNew:
+		return groupNew
+	case arxiv.AnnounceCross:
+		return groupCross
+	case arxiv.AnnounceReplace, arxiv.AnnounceReplaceCross:
+		return groupReplace
+	}
+	return ""
+}
+
+// numberListing orders the entries by group, keeping arXiv's order within
+// each, and numbers them from 1. Entries of unknown types are left out.
+func numberListing(entries []arxiv.ListingEntry) []*listingEntry {
+	var numbered []*listingEntry
+	for _, g := range listingGroups {
+		for _, e := range entries {
+			if listingGroup(e.Type) == g.name {
+				numbered = append(numbered, &listingEntry{
+					Number:       len(numbered) + 1,
+					AnnounceType: e.Type,
+					ArxivMeta:    e.Meta,
+				})
+			}
+		}
+	}
+	return numbered
+}
+
+// selectEntries returns the arXiv IDs of the entries a --fetch value picks:
+// a comma-separated list of numbers, ranges ("7-9"), groups and "all".
+func selectEntries(entries []*listingEntry, spec string) ([]string, error) {
+	picked := make(map[int]bool)
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		switch {
+		case part == "":
+			continue
+		case part == "all":
+			for _, e := range entries {
+				picked[e.Number] = true
+			}
+		case listingGroup(part) == part:
+			for _, e := range entries {
+				if listingGroup(e.AnnounceType) == part {
+					picked[e.Number] = true
+				}
+			}
+		default:
+			lo, hi, isRange := strings.Cut(part, "-")
+			if !isRange {
+				hi = lo
+			}
+			from, err1 := strconv.Atoi(lo)
+			to, err2 := strconv.Atoi(hi)
+			if err1 != nil || err2 != nil || from < 1 || to < from {
+				return nil, fmt.Errorf("invalid selection %q (use numbers, ranges like 7-9, new, cross, replace or all)", part)
+			}
+			if to > len(entries) {
+				return nil, fmt.Errorf("no entry %d: the listing has %d", to, len(entries))
+			}
+			for n := from; n <= to; n++ {
+				picked[n] = true
+			}
+		}
+	}
+
+	var ids []string
+	for _, e := range entries {
+		if picked[e.Number] {
+			ids = append(ids, e.ArxivID)
+		}
+	}
+	return ids, nil
+}
+
+// printListing prints the entries group by group, marking papers that are
+// already in the library.
+func printListing(listing *arxiv.Listing, entries []*listingEntry, papersRoot string) {
+	date := listing.Date
+	if date == "" {
+		date = "latest announcement"
+	}
+	fmt.Printf("%s: %s\n", strings.Join(listing.Categories, ", "), date)
+	if len(entries) == 0 {
+		fmt.Println("\nNo papers announced.")
+		return
+	}
+
+	for _, g := range listingGroups {
+		var group []*listingEntry
+		for _, e := range entries {
+			if listingGroup(e.AnnounceType) == g.name {
+				group = append(group, e)
+			}
+		}
+		if len(group) == 0 {
+			continue
+		}
+
+		fmt.Printf("\n%s (%d)\n", g.heading, len(group))
+		table := output.NewTable("#", "ID", "Title", "Authors", "Categories", "Library")
+		for _, e := range group {
+			names := make([]string, 0, 4)
+			for i, a := range e.Authors {
+				if i >= 3 {
+					names = append(names, "...")
+					break
+				}
+				names = append(names, a.Name)
+			}
+			inLibrary := ""
+			if isDir(libraryDir(papersRoot, e.ArxivID)) {
+				inLibrary = "yes"
+			}
+			table.AddRow(strconv.Itoa(e.Number), e.ArxivID, truncate(e.Title, 50),
+				truncate(strings.Join(names, ", "), 30), strings.Join(e.Categories, " "), inLibrary)
+		}
+		table.Render()
+	}
+}
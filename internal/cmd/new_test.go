@@ -0,0 +1,66 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mtreilly/arc-arxiv/internal/arxiv"
+)
+
+func testListing() []*listingEntry {
+	entry := func(id, kind string) arxiv.ListingEntry {
+		return arxiv.ListingEntry{Meta: &arxiv.ArxivMeta{ArxivID: id}, Type: kind}
+	}
+	return numberListing([]arxiv.ListingEntry{
+		entry("2503.00001", arxiv.AnnounceReplace),
+		entry("2503.00002", arxiv.AnnounceNew),
+		entry("2503.00003", arxiv.AnnounceCross),
+		entry("2503.00004", "unknown"),
+		entry("2503.00005", arxiv.AnnounceNew),
+		entry("2503.00006", arxiv.AnnounceReplaceCross),
+	})
+}
+
+func TestNumberListing(t *testing.T) {
+	var got []string
+	for i, e := range testListing() {
+		if e.Number != i+1 {
+			t.Errorf("entry %d numbered %d", i, e.Number)
+		}
+		got = append(got, e.ArxivID)
+	}
+	want := []string{"2503.00002", "2503.00005", "2503.00003", "2503.00001", "2503.00006"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+}
+
+func TestSelectEntries(t *testing.T) {
+	entries := testListing()
+	tests := []struct {
+		spec string
+		want []string
+	}{
+		{"1", []string{"2503.00002"}},
+		{"3, 1", []string{"2503.00002", "2503.00003"}},
+		{"2-4", []string{"2503.00005", "2503.00003", "2503.00001"}},
+		{"replace", []string{"2503.00001", "2503.00006"}},
+		{"cross,5", []string{"2503.00003", "2503.00006"}},
+		{"ALL", []string{"2503.00002", "2503.00005", "2503.00003", "2503.00001", "2503.00006"}},
+	}
+	for _, tt := range tests {
+		got, err := selectEntries(entries, tt.spec)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("selectEntries(%q) = %v, %v; want %v", tt.spec, got, err, tt.want)
+		}
+	}
+
+	for _, spec := range []string{"0", "6", "4-2", "x", "replace-cross", "1-"} {
+		if _, err := selectEntries(entries, spec); err == nil {
+			t.Errorf("selectEntries(%q): expected error", spec)
+		}
+	}
+}
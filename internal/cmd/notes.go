@@ -0,0 +1,295 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/mtreilly/arc-arxiv/internal/arxiv"
+	"github.com/mtreilly/arc-arxiv/internal/fsutil"
+	"github.com/mtreilly/arc-arxiv/internal/settings"
+)
+
+// notesSideFile receives a regenerated notes template when notes.md already
+// holds the user's own writing.
+const notesSideFile = "notes.template.md"
+
+// notesOutcome describes what syncNotes did with notes.md.
+type notesOutcome int
+
+const (
+	// notesCreated means notes.md did not exist and was written from the template.
+	notesCreated notesOutcome = iota
+	// notesKept means notes.md was left alone because the template is unchanged.
+	notesKept
+	// notesRefreshed means notes.md was still the untouched old template and
+	// was replaced by the new one.
+	notesRefreshed
+	// notesSideFiled means notes.md was kept and the changed template was
+	// written to notesSideFile instead.
+	notesSideFiled
+)
+
+// defaultNotesTemplate is used when no template is configured. Custom
+// templates see the same data; see notesData.
+const defaultNotesTemplate = `# {{.Title}}
+
+arXiv: {{.ArxivID}}
+Authors: {{join .AuthorNames ", "}}
+
+## Summary
+
+
+## Key Takeaways
+
+
+## Follow-ups
+
+`
+
+// notesFuncs are the helper functions available to notes templates.
+var notesFuncs = template.FuncMap{
+	"join":    strings.Join,
+	"lower":   strings.ToLower,
+	"upper":   strings.ToUpper,
+	"trim":    strings.TrimSpace,
+	"oneline": oneline,
+	"wrap":    wrap,
+}
+
+var builtinNotes = template.Must(template.New("notes").Funcs(notesFuncs).Parse(defaultNotesTemplate))
+
+// notesData is what a notes template is executed with: every ArxivMeta
+// field, plus a few derived values as methods.
+type notesData struct {
+	*arxiv.ArxivMeta
+}
+
+// AuthorNames returns the author names in order.
+func (d notesData) AuthorNames() []string {
+	names := make([]string, 0, len(d.Authors))
+	for _, a := range d.Authors {
+		names = append(names, a.Name)
+	}
+	return names
+}
+
+// FirstAuthor returns the first author's name, or "".
+func (d notesData) FirstAuthor() string {
+	if len(d.Authors) == 0 {
+		return ""
+	}
+	return d.Authors[0].Name
+}
+
+// Year returns the year of first publication, or "".
+func (d notesData) Year() string {
+	if len(d.Published) < 4 {
+		return ""
+	}
+	return d.Published[:4]
+}
+
+var bibtexKeyPattern = regexp.MustCompile(`^@\w+\{([^,}\s]+)`)
+
+// BibTeXKey returns the citation key export uses for the paper.
+func (d notesData) BibTeXKey() string {
+	if article := arxiv.MetaToArticle(d.ArxivMeta); article != nil {
+		if m := bibtexKeyPattern.FindStringSubmatch(article.ToBibTeX()); m != nil {
+			return m[1]
+		}
+	}
+	return d.ArxivID
+}
+
+// notesTemplates picks the notes template for a paper: the first category
+// override that matches, else the configured default, else the built-in one.
+// A nil *notesTemplates always uses the built-in template.
+type notesTemplates struct {
+	base       *template.Template
+	categories map[string]*template.Template
+}
+
+// loadNotesTemplates parses the templates named in the settings file.
+func loadNotesTemplates(s *settings.Settings) (*notesTemplates, error) {
+	nt := &notesTemplates{base: builtinNotes, categories: map[string]*template.Template{}}
+	if s.Notes.Template != "" {
+		tmpl, err := parseNotesTemplate(s.Resolve(s.Notes.Template))
+		if err != nil {
+			return nil, err
+		}
+		nt.base = tmpl
+	}
+	for category, path := range s.Notes.Categories {
+		tmpl, err := parseNotesTemplate(s.Resolve(path))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", category, err)
+		}
+		nt.categories[strings.ToLower(category)] = tmpl
+	}
+	return nt, nil
+}
+
+func parseNotesTemplate(path string) (*template.Template, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read notes template: %w", err)
+	}
+	tmpl, err := template.New(filepath.Base(path)).Funcs(notesFuncs).Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("parse notes template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// forPaper returns the template for a paper. The primary category is tried
+// before the others, and within a category an exact match ("cs.LG") beats
+// its archive ("cs").
+func (nt *notesTemplates) forPaper(meta *arxiv.ArxivMeta) *template.Template {
+	if nt == nil {
+		return builtinNotes
+	}
+	categories := append([]string{meta.PrimaryCategory}, meta.Categories...)
+	for _, c := range categories {
+		c = strings.ToLower(c)
+		if c == "" {
+			continue
+		}
+		if tmpl, ok := nt.categories[c]; ok {
+			return tmpl
+		}
+		if archive, _, ok := strings.Cut(c, "."); ok {
+			if tmpl, ok := nt.categories[archive]; ok {
+				return tmpl
+			}
+		}
+	}
+	return nt.base
+}
+
+// render produces the notes.md template for a paper.
+func (nt *notesTemplates) render(meta *arxiv.ArxivMeta) (string, error) {
+	tmpl := nt.forPaper(meta)
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, notesData{meta}); err != nil {
+		return "", fmt.Errorf("render notes template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// oneline collapses runs of whitespace, including newlines, to one space.
+func oneline(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// wrap re-flows s into lines of at most width characters. Words longer than
+// width are kept whole.
+func wrap(width int, s string) string {
+	var b strings.Builder
+	lineLen := 0
+	for _, word := range strings.Fields(s) {
+		switch {
+		case lineLen == 0:
+		case lineLen+1+len(word) > width:
+			b.WriteByte('\n')
+			lineLen = 0
+		default:
+			b.WriteByte(' ')
+			lineLen++
+		}
+		b.WriteString(word)
+		lineLen += len(word)
+	}
+	return b.String()
+}
+
+// syncNotes creates notes.md for a newly fetched paper without ever
+// overwriting notes a user has edited. prev is the metadata the paper had
+// before this fetch, or nil for a first fetch. nt chooses the template; nil
+// means the built-in one.
+func syncNotes(dir string, prev, meta *arxiv.ArxivMeta, nt *notesTemplates) (notesOutcome, error) {
+	notesPath := filepath.Join(dir, "notes.md")
+	fresh, err := nt.render(meta)
+	if err != nil {
+		return notesKept, err
+	}
+
+	existing, err := os.ReadFile(notesPath)
+	if os.IsNotExist(err) {
+		return notesCreated, fsutil.WriteFile(notesPath, []byte(fresh), 0o644)
+	}
+	if err != nil {
+		return notesKept, err
+	}
+
+	current := string(existing)
+	if current == fresh {
+		return notesKept, nil
+	}
+
+	// The template has changed (new title, authors, ...). Only replace
+	// notes.md if it is byte-for-byte the template we generated last time.
+	if prev != nil {
+		// A template that fails for the old metadata just means notes.md
+		// cannot be recognised as untouched.
+		previous, err := nt.render(prev)
+		if (err == nil && current == previous) || slices.Contains(legacyNotes(prev), current) {
+			return notesRefreshed, fsutil.WriteFile(notesPath, []byte(fresh), 0o644)
+		}
+		if err == nil && previous == fresh {
+			return notesKept, nil
+		}
+	}
+
+	return notesSideFiled, fsutil.WriteFile(filepath.Join(dir, notesSideFile), []byte(fresh), 0o644)
+}
+
+// legacyNotes returns what fetch wrote to notes.md for prev before notes
+// templates could be configured: the built-in template, naming the paper by
+// the ID it was fetched with, which carried the version if one was asked for.
+func legacyNotes(prev *arxiv.ArxivMeta) []string {
+	metas := []*arxiv.ArxivMeta{prev}
+	if prev.Version > 0 {
+		versioned := *prev
+		versioned.ArxivID = fmt.Sprintf("%sv%d", prev.ArxivID, prev.Version)
+		metas = append(metas, &versioned)
+	}
+	var notes []string
+	for _, m := range metas {
+		var buf strings.Builder
+		if err := builtinNotes.Execute(&buf, notesData{m}); err == nil {
+			notes = append(notes, buf.String())
+		}
+	}
+	return notes
+}
+
+// userFiles lists files in a paper directory other than the ones fetch
+// manages, so re-fetches can report what they left in place.
+func userFiles(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	managed := map[string]bool{"paper.pdf": true, "meta.yaml": true, "notes.md": true, notesSideFile: true}
+
+	var files []string
+	for _, e := range entries {
+		if managed[e.Name()] || versionedPDFPattern.MatchString(e.Name()) || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		name := e.Name()
+		if e.IsDir() {
+			name += "/"
+		}
+		files = append(files, name)
+	}
+	return files
+}
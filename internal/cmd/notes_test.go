@@ -0,0 +1,270 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mtreilly/arc-arxiv/internal/arxiv"
+	"github.com/mtreilly/arc-arxiv/internal/settings"
+)
+
+// renderTestNotes renders the notes nt chooses for meta.
+func renderTestNotes(t *testing.T, nt *notesTemplates, meta *arxiv.ArxivMeta) string {
+	t.Helper()
+	content, err := nt.render(meta)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	return content
+}
+
+// defaultNotesTemplates loads the templates of an empty settings file.
+func defaultNotesTemplates(t *testing.T) *notesTemplates {
+	t.Helper()
+	nt, err := loadNotesTemplates(&settings.Settings{})
+	if err != nil {
+		t.Fatalf("loadNotesTemplates: %v", err)
+	}
+	return nt
+}
+
+func TestRenderNotes(t *testing.T) {
+	content := renderTestNotes(t, defaultNotesTemplates(t), &arxiv.ArxivMeta{
+		ArxivID: "2304.00067",
+		Title:   "Test Paper",
+		Authors: []arxiv.Author{{Name: "Alice"}, {Name: "Bob"}},
+	})
+
+	for _, want := range []string{"# Test Paper", "arXiv: 2304.00067", "Authors: Alice, Bob", "## Summary", "## Key Takeaways", "## Follow-ups"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("notes missing %q", want)
+		}
+	}
+}
+
+func TestRenderNotesDefault(t *testing.T) {
+	got := renderTestNotes(t, defaultNotesTemplates(t), &arxiv.ArxivMeta{
+		ArxivID: "2304.00067",
+		Title:   "Test Paper",
+		Authors: []arxiv.Author{{Name: "Alice"}, {Name: "Bob"}},
+	})
+	want := "# Test Paper\n\narXiv: 2304.00067\nAuthors: Alice, Bob\n\n## Summary\n\n\n## Key Takeaways\n\n\n## Follow-ups\n\n"
+	if got != want {
+		t.Errorf("render = %q, want %q", got, want)
+	}
+}
+
+func TestNotesTemplates(t *testing.T) {
+	t.Setenv(settings.EnvPath, "")
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "default.md"), "default {{.ArxivID}}")
+	writeFile(t, filepath.Join(root, "theory.md"), "theory {{.FirstAuthor}} {{.Year}}")
+	writeFile(t, filepath.Join(root, "ml.md"), "ml {{.ArxivID}}\n{{wrap 20 .Abstract}}")
+	writeFile(t, filepath.Join(root, "cs.md"), "cs {{upper .PrimaryCategory}}")
+	writeFile(t, filepath.Join(root, settings.FileName), `notes:
+  template: default.md
+  categories:
+    hep-th: theory.md
+    cs.LG: ml.md
+    cs: cs.md
+`)
+	set, err := settings.Load(root)
+	if err != nil {
+		t.Fatalf("settings.Load: %v", err)
+	}
+	nt, err := loadNotesTemplates(set)
+	if err != nil {
+		t.Fatalf("loadNotesTemplates: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		meta *arxiv.ArxivMeta
+		want string
+	}{
+		{"no override", &arxiv.ArxivMeta{ArxivID: "2304.00067", PrimaryCategory: "math.AG"}, "default 2304.00067"},
+		{"primary category", &arxiv.ArxivMeta{
+			PrimaryCategory: "hep-th",
+			Published:       "1999-01-04T00:00:00Z",
+			Authors:         []arxiv.Author{{Name: "Juan Maldacena"}},
+		}, "theory Juan Maldacena 1999"},
+		{"exact beats archive", &arxiv.ArxivMeta{
+			ArxivID:         "1706.03762",
+			PrimaryCategory: "cs.LG",
+			Abstract:        "The dominant sequence\ntransduction models are based on",
+		}, "ml 1706.03762\nThe dominant\nsequence\ntransduction models\nare based on"},
+		{"archive", &arxiv.ArxivMeta{PrimaryCategory: "cs.CL"}, "cs CS.CL"},
+		{"cross-list", &arxiv.ArxivMeta{PrimaryCategory: "gr-qc", Categories: []string{"gr-qc", "hep-th"}}, "theory  "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := nt.render(tt.meta)
+			if err != nil {
+				t.Fatalf("render: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("render = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("bad template", func(t *testing.T) {
+		writeFile(t, filepath.Join(root, "default.md"), "{{.Title")
+		if _, err := loadNotesTemplates(set); err == nil {
+			t.Error("expected parse error")
+		}
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		writeFile(t, filepath.Join(root, "default.md"), "{{.Nope}}")
+		nt, err := loadNotesTemplates(set)
+		if err != nil {
+			t.Fatalf("loadNotesTemplates: %v", err)
+		}
+		if _, err := syncNotes(t.TempDir(), nil, &arxiv.ArxivMeta{}, nt); err == nil {
+			t.Error("expected render error")
+		}
+	})
+}
+
+func TestSyncNotes(t *testing.T) {
+	oldMeta := &arxiv.ArxivMeta{ArxivID: "2304.00067", Title: "Old Title"}
+	newMeta := &arxiv.ArxivMeta{ArxivID: "2304.00067", Title: "New Title"}
+
+	readNotes := func(t *testing.T, dir string) string {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join(dir, "notes.md"))
+		if err != nil {
+			t.Fatalf("read notes: %v", err)
+		}
+		return string(data)
+	}
+
+	t.Run("creates notes on first fetch", func(t *testing.T) {
+		dir := t.TempDir()
+		got, err := syncNotes(dir, nil, newMeta, nil)
+		if err != nil {
+			t.Fatalf("syncNotes: %v", err)
+		}
+		if got != notesCreated {
+			t.Errorf("outcome = %v, want notesCreated", got)
+		}
+		if !strings.Contains(readNotes(t, dir), "# New Title") {
+			t.Error("notes should be rendered from template")
+		}
+	})
+
+	t.Run("keeps edited notes when template unchanged", func(t *testing.T) {
+		dir := t.TempDir()
+		edited := renderTestNotes(t, nil, newMeta) + "My hard-won insights.\n"
+		if err := os.WriteFile(filepath.Join(dir, "notes.md"), []byte(edited), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := syncNotes(dir, newMeta, newMeta, nil)
+		if err != nil {
+			t.Fatalf("syncNotes: %v", err)
+		}
+		if got != notesKept {
+			t.Errorf("outcome = %v, want notesKept", got)
+		}
+		if readNotes(t, dir) != edited {
+			t.Error("edited notes were modified")
+		}
+		if _, err := os.Stat(filepath.Join(dir, notesSideFile)); !os.IsNotExist(err) {
+			t.Error("side file should not be written when template is unchanged")
+		}
+	})
+
+	t.Run("refreshes untouched template", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "notes.md"), []byte(renderTestNotes(t, nil, oldMeta)), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := syncNotes(dir, oldMeta, newMeta, nil)
+		if err != nil {
+			t.Fatalf("syncNotes: %v", err)
+		}
+		if got != notesRefreshed {
+			t.Errorf("outcome = %v, want notesRefreshed", got)
+		}
+		if !strings.Contains(readNotes(t, dir), "# New Title") {
+			t.Error("untouched template should be refreshed")
+		}
+	})
+
+	t.Run("refreshes untouched notes from a versioned fetch", func(t *testing.T) {
+		// Before notes templates, fetch 2304.00067v2 named the paper by the
+		// ID as given, version and all.
+		dir := t.TempDir()
+		legacy := "# Old Title\n\narXiv: 2304.00067v2\nAuthors: \n\n## Summary\n\n\n## Key Takeaways\n\n\n## Follow-ups\n\n"
+		if err := os.WriteFile(filepath.Join(dir, "notes.md"), []byte(legacy), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		old := *oldMeta
+		old.Version = 2
+		got, err := syncNotes(dir, &old, newMeta, nil)
+		if err != nil {
+			t.Fatalf("syncNotes: %v", err)
+		}
+		if got != notesRefreshed {
+			t.Errorf("outcome = %v, want notesRefreshed", got)
+		}
+		if !strings.Contains(readNotes(t, dir), "# New Title") {
+			t.Error("untouched notes should be refreshed")
+		}
+		if _, err := os.Stat(filepath.Join(dir, notesSideFile)); !os.IsNotExist(err) {
+			t.Error("side file should not be written for untouched notes")
+		}
+	})
+
+	t.Run("writes side file when edited notes and template both changed", func(t *testing.T) {
+		dir := t.TempDir()
+		edited := renderTestNotes(t, nil, oldMeta) + "Do not lose me.\n"
+		if err := os.WriteFile(filepath.Join(dir, "notes.md"), []byte(edited), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := syncNotes(dir, oldMeta, newMeta, nil)
+		if err != nil {
+			t.Fatalf("syncNotes: %v", err)
+		}
+		if got != notesSideFiled {
+			t.Errorf("outcome = %v, want notesSideFiled", got)
+		}
+		if readNotes(t, dir) != edited {
+			t.Error("edited notes were modified")
+		}
+		side, err := os.ReadFile(filepath.Join(dir, notesSideFile))
+		if err != nil {
+			t.Fatalf("side file: %v", err)
+		}
+		if !strings.Contains(string(side), "# New Title") {
+			t.Error("side file should hold the new template")
+		}
+	})
+}
+
+func TestUserFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"paper.pdf", "meta.yaml", "notes.md", "body.md", "figure.png", ".paper.pdf.123.tmp"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "code"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	got := strings.Join(userFiles(dir), ",")
+	if got != "body.md,code/,figure.png" {
+		t.Errorf("userFiles = %q", got)
+	}
+}
@@ -0,0 +1,357 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mtreilly/arc-arxiv/internal/arxiv"
+	"github.com/mtreilly/arc-arxiv/internal/fsutil"
+	"github.com/mtreilly/arc-arxiv/internal/pdftext"
+	"github.com/mtreilly/arc-arxiv/internal/refs"
+	"github.com/mtreilly/arc-arxiv/internal/source"
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-sdk/config"
+	"github.com/yourorg/arc-sdk/output"
+	"gopkg.in/yaml.v3"
+)
+
+// referencesFile holds a paper's parsed reference list.
+const referencesFile = "references.yaml"
+
+// referenceList is the content of references.yaml. File is the input the
+// references were read from, relative to the paper directory.
+type referenceList struct {
+	ArxivID     string           `yaml:"arxiv_id" json:"arxiv_id"`
+	Source      string           `yaml:"source" json:"source"`
+	File        string           `yaml:"file" json:"file"`
+	GeneratedAt string           `yaml:"generated_at" json:"generated_at"`
+	References  []refs.Reference `yaml:"references" json:"references"`
+}
+
+func newRefsCmd(cfg *config.Config) *cobra.Command {
+	var out output.OutputOptions
+	var missing bool
+
+	cmd := &cobra.Command{
+		Use:   "refs <id>",
+		Short: "Extract a paper's reference list",
+		Long: `Parse the bibliography of a paper into papers/<id>/references.yaml.
+
+The references are read from the LaTeX source when it has been downloaded
+(see the source command): the .bbl file if there is one, else the .bib
+entries the paper cites. Otherwise they are read from the text extracted
+from the PDF (body.md, or paper.pdf directly), which is less precise.
+
+Each entry records the arXiv ID, DOI, title, authors and year found for it.
+References that are already in the library are linked to it by arXiv ID,
+DOI or title; --missing lists only those with an arXiv ID that are not.
+Use fetch --refs to download them.
+
+Examples:
+  arc-arxiv refs 2304.00067
+  arc-arxiv refs 2304.00067 --missing
+  arc-arxiv refs 2304.00067 --output json
+  arc-arxiv fetch 2304.00067 --source --refs`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := out.Resolve(); err != nil {
+				return err
+			}
+
+			papersRoot := filepath.Join(cfg.ResearchRoot, "papers")
+			id, _ := libraryID(args[0])
+			dir := libraryDir(papersRoot, id)
+			meta, err := readMeta(filepath.Join(dir, "meta.yaml"))
+			if err != nil {
+				return fmt.Errorf("paper not found: %s", id)
+			}
+
+			lib, err := loadLibraryLinks(papersRoot)
+			if err != nil {
+				return err
+			}
+			list, err := updateReferences(dir, meta.ArxivID, lib)
+			if err != nil {
+				return err
+			}
+
+			shown := list.References
+			if missing {
+				shown = nil
+				for _, r := range list.References {
+					if r.ArxivID != "" && r.Library == "" {
+						shown = append(shown, r)
+					}
+				}
+			}
+
+			if out.Is(output.OutputJSON) {
+				if missing {
+					return output.JSON(shown)
+				}
+				return output.JSON(list)
+			}
+
+			if len(shown) > 0 {
+				table := output.NewTable("#", "Title", "Year", "arXiv / DOI", "Library")
+				for i, r := range shown {
+					title := r.Title
+					if title == "" {
+						title = r.Raw
+					}
+					ident := r.ArxivID
+					if ident == "" {
+						ident = r.DOI
+					}
+					linked := ""
+					if r.Library != "" {
+						linked = "yes"
+					}
+					table.AddRow(fmt.Sprintf("%d", i+1), truncate(title, 50), r.Year, truncate(ident, 30), linked)
+				}
+				table.Render()
+				fmt.Println()
+			}
+
+			var withArxiv, withDOI, inLibrary, toFetch int
+			for _, r := range list.References {
+				if r.ArxivID != "" {
+					withArxiv++
+				}
+				if r.DOI != "" {
+					withDOI++
+				}
+				if r.Library != "" {
+					inLibrary++
+				} else if r.ArxivID != "" {
+					toFetch++
+				}
+			}
+			fmt.Printf("%d reference(s) from %s: %d with arXiv IDs, %d with DOIs, %d in the library.\n",
+				len(list.References), list.File, withArxiv, withDOI, inLibrary)
+			fmt.Printf("Saved: %s\n", filepath.Join(dir, referencesFile))
+			if toFetch > 0 {
+				fmt.Printf("Fetch the %d missing arXiv paper(s) with: arc-arxiv fetch %s --refs\n", toFetch, meta.ArxivID)
+			}
+			return nil
+		},
+	}
+
+	out.AddOutputFlags(cmd, output.OutputTable)
+	cmd.Flags().BoolVar(&missing, "missing", false, "Only list references on arXiv that are not in the library")
+
+	return cmd
+}
+
+// updateReferences parses the reference list of the paper in dir, links it
+// to the library and writes references.yaml.
+func updateReferences(dir, id string, lib *libraryLinks) (*referenceList, error) {
+	list, err := buildReferences(dir)
+	if err != nil {
+		return nil, err
+	}
+	list.ArxivID = id
+	lib.link(list.References, id)
+	if err := writeReferences(dir, list); err != nil {
+		return nil, fmt.Errorf("write %s: %w", referencesFile, err)
+	}
+	return list, nil
+}
+
+// buildReferences reads a paper's references from the best input available:
+// the LaTeX source, then body.md, then the PDF itself.
+func buildReferences(dir string) (*referenceList, error) {
+	list := &referenceList{GeneratedAt: time.Now().UTC().Format(time.RFC3339)}
+
+	if srcDir := filepath.Join(dir, sourceDirName); isDir(srcDir) {
+		found, kind, file := referencesFromSource(srcDir)
+		if len(found) > 0 {
+			list.References, list.Source, list.File = found, kind, filepath.ToSlash(filepath.Join(sourceDirName, file))
+			return list, nil
+		}
+	}
+
+	if data, err := os.ReadFile(filepath.Join(dir, bodyFile)); err == nil {
+		if found := refs.ParseText(pdftext.StripMarkers(string(data))); len(found) > 0 {
+			list.References, list.Source, list.File = found, refs.SourceText, bodyFile
+			return list, nil
+		}
+	}
+
+	raw, err := pdftext.ExtractFile(filepath.Join(dir, "paper.pdf"))
+	if err != nil {
+		return nil, fmt.Errorf("no source or text to read references from (run 'arc-arxiv source' first): %w", err)
+	}
+	found := refs.ParseText(pdftext.StripMarkers(pdftext.Markdown(pdftext.Clean(raw))))
+	if len(found) == 0 {
+		return nil, fmt.Errorf("no reference list found in paper.pdf")
+	}
+	list.References, list.Source, list.File = found, refs.SourceText, "paper.pdf"
+	return list, nil
+}
+
+// referencesFromSource parses the .bbl file of an unpacked e-print or,
+// without one, the entries of its .bib files that the .tex files cite. It
+// returns the references, refs.SourceBBL or refs.SourceBib, and the file
+// read relative to srcDir.
+func referencesFromSource(srcDir string) ([]refs.Reference, string, string) {
+	var bbls, bibs, texs []string
+	_ = filepath.WalkDir(srcDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		rel, _ := filepath.Rel(srcDir, p)
+		switch strings.ToLower(filepath.Ext(p)) {
+		case ".bbl":
+			bbls = append(bbls, rel)
+		case ".bib":
+			bibs = append(bibs, rel)
+		case ".tex":
+			if !strings.HasSuffix(p, ".flat.tex") {
+				texs = append(texs, rel)
+			}
+		}
+		return nil
+	})
+	sort.Strings(bbls)
+	sort.Strings(bibs)
+
+	// The .bbl of the main file comes first.
+	if main, err := source.MainFile(srcDir); err == nil {
+		want := filepath.FromSlash(strings.TrimSuffix(main, filepath.Ext(main)) + ".bbl")
+		sort.SliceStable(bbls, func(i, j int) bool { return bbls[i] == want && bbls[j] != want })
+	}
+	for _, name := range bbls {
+		data, err := os.ReadFile(filepath.Join(srcDir, name))
+		if err != nil {
+			continue
+		}
+		if found := refs.ParseBBL(string(data)); len(found) > 0 {
+			return found, refs.SourceBBL, name
+		}
+	}
+
+	if len(bibs) == 0 {
+		return nil, "", ""
+	}
+	var tex strings.Builder
+	for _, name := range texs {
+		if data, err := os.ReadFile(filepath.Join(srcDir, name)); err == nil {
+			tex.Write(data)
+			tex.WriteByte('\n')
+		}
+	}
+	cited, all := refs.CitedKeys(tex.String())
+	if all || len(cited) == 0 {
+		cited = nil
+	}
+	var found []refs.Reference
+	for _, name := range bibs {
+		if data, err := os.ReadFile(filepath.Join(srcDir, name)); err == nil {
+			found = append(found, refs.ParseBib(string(data), cited)...)
+		}
+	}
+	return found, refs.SourceBib, strings.Join(bibs, ", ")
+}
+
+func writeReferences(dir string, list *referenceList) error {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(list); err != nil {
+		return err
+	}
+	return fsutil.WriteFile(filepath.Join(dir, referencesFile), buf.Bytes(), 0o644)
+}
+
+// readReferences reads a paper's references.yaml.
+func readReferences(dir string) (*referenceList, error) {
+	data, err := os.ReadFile(filepath.Join(dir, referencesFile))
+	if err != nil {
+		return nil, err
+	}
+	var list referenceList
+	if err := yaml.Unmarshal(data, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// libraryLinks finds library papers by arXiv ID, DOI or title.
+type libraryLinks struct {
+	ids    map[string]bool
+	dois   map[string]string
+	titles map[string]string
+}
+
+func loadLibraryLinks(papersRoot string) (*libraryLinks, error) {
+	entries, _, err := scanLibrary(papersRoot)
+	if err != nil {
+		return nil, err
+	}
+	lib := &libraryLinks{ids: make(map[string]bool), dois: make(map[string]string), titles: make(map[string]string)}
+	for _, e := range entries {
+		lib.add(e.Meta)
+	}
+	return lib, nil
+}
+
+func (l *libraryLinks) add(meta *arxiv.ArxivMeta) {
+	l.ids[meta.ArxivID] = true
+	if meta.DOI != "" {
+		l.dois[strings.ToLower(meta.DOI)] = meta.ArxivID
+	}
+	if t := refs.NormalizeTitle(meta.Title); t != "" {
+		l.titles[t] = meta.ArxivID
+	}
+}
+
+// minTitleMatch is the shortest normalized title matched on its own;
+// shorter titles ("Introduction", "Deep learning") are too ambiguous.
+const minTitleMatch = 20
+
+// link sets Library on every reference found in the library. References to
+// the paper itself (self) are left unlinked.
+func (l *libraryLinks) link(list []refs.Reference, self string) {
+	for i := range list {
+		r := &list[i]
+		r.Library = ""
+		switch {
+		case r.ArxivID != "" && l.ids[r.ArxivID]:
+			r.Library = r.ArxivID
+		case r.DOI != "" && l.dois[strings.ToLower(r.DOI)] != "":
+			r.Library = l.dois[strings.ToLower(r.DOI)]
+		default:
+			if t := refs.NormalizeTitle(r.Title); len(t) >= minTitleMatch {
+				r.Library = l.titles[t]
+			}
+		}
+		if r.Library == self {
+			r.Library = ""
+		}
+	}
+}
+
+// missingReferences returns the arXiv IDs of references not in the library,
+// in order and without duplicates.
+func missingReferences(list *referenceList) []string {
+	var ids []string
+	seen := make(map[string]bool)
+	for _, r := range list.References {
+		if r.ArxivID == "" || r.Library != "" || r.ArxivID == list.ArxivID || seen[r.ArxivID] {
+			continue
+		}
+		seen[r.ArxivID] = true
+		ids = append(ids, r.ArxivID)
+	}
+	return ids
+}
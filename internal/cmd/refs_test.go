@@ -0,0 +1,72 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/mtreilly/arc-arxiv/internal/arxiv"
+	"github.com/mtreilly/arc-arxiv/internal/refs"
+)
+
+func TestBuildReferencesFromSource(t *testing.T) {
+	dir := t.TempDir()
+	srcDir := filepath.Join(dir, sourceDirName)
+	if err := os.MkdirAll(srcDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(srcDir, "main.tex"), "\\documentclass{article}\n\\begin{document}\nSee \\cite{vaswani}.\n% \\cite{unused}\n\\bibliography{refs}\n\\end{document}\n")
+	writeFile(t, filepath.Join(srcDir, "refs.bib"), `@article{vaswani, title={Attention Is All You Need}, author={Vaswani, Ashish}, year={2017}, eprint={1706.03762}}
+@article{unused, title={Never Cited}, author={Nobody}}`)
+
+	list, err := buildReferences(dir)
+	if err != nil {
+		t.Fatalf("buildReferences: %v", err)
+	}
+	if list.Source != refs.SourceBib || list.File != "source/refs.bib" || len(list.References) != 1 {
+		t.Fatalf("list = %+v", list)
+	}
+
+	// A .bbl takes precedence over the .bib.
+	writeFile(t, filepath.Join(srcDir, "main.bbl"), "\\begin{thebibliography}{1}\n\\bibitem{vaswani} A.~Vaswani.\n\\newblock Attention is all you need.\n\\newblock arXiv:1706.03762, 2017.\n\\end{thebibliography}\n")
+	list, err = buildReferences(dir)
+	if err != nil {
+		t.Fatalf("buildReferences: %v", err)
+	}
+	if list.Source != refs.SourceBBL || list.File != "source/main.bbl" || len(list.References) != 1 {
+		t.Fatalf("list = %+v", list)
+	}
+}
+
+func TestLinkReferences(t *testing.T) {
+	lib := &libraryLinks{ids: map[string]bool{}, dois: map[string]string{}, titles: map[string]string{}}
+	lib.add(&arxiv.ArxivMeta{ArxivID: "1706.03762", Title: "Attention Is All You Need"})
+	lib.add(&arxiv.ArxivMeta{ArxivID: "1512.03385", Title: "Deep Residual Learning for Image Recognition", DOI: "10.1109/CVPR.2016.90"})
+	lib.add(&arxiv.ArxivMeta{ArxivID: "2304.00067", Title: "The paper itself"})
+
+	list := &referenceList{ArxivID: "2304.00067", References: []refs.Reference{
+		{ArxivID: "1706.03762"},
+		{DOI: "10.1109/cvpr.2016.90"},
+		{Title: "Attention is all you need."},
+		{ArxivID: "2304.00067"},
+		{ArxivID: "1810.04805"},
+		{ArxivID: "1810.04805", Title: "BERT again"},
+	}}
+	lib.link(list.References, list.ArxivID)
+
+	var got []string
+	for _, r := range list.References {
+		got = append(got, r.Library)
+	}
+	want := []string{"1706.03762", "1512.03385", "1706.03762", "", "", ""}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("links = %q, want %q", got, want)
+	}
+	if missing := missingReferences(list); !reflect.DeepEqual(missing, []string{"1810.04805"}) {
+		t.Errorf("missing = %q", missing)
+	}
+}
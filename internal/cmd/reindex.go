@@ -0,0 +1,183 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/mtreilly/arc-arxiv/internal/arxiv"
+	"github.com/mtreilly/arc-arxiv/internal/index"
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-sdk/config"
+)
+
+func newReindexCmd(cfg *config.Config, db *sql.DB) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reindex",
+		Short: "Rebuild the library index from disk",
+		Long: `Rebuild the SQLite library index by reading every papers/<id>/meta.yaml.
+
+The index is kept in sync by fetch, update and delete. Run reindex after
+editing or copying paper directories by hand.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			ix, err := index.Open(ctx, db)
+			if err != nil {
+				return err
+			}
+
+			papersRoot := filepath.Join(cfg.ResearchRoot, "papers")
+			entries, skipped, err := scanLibrary(papersRoot)
+			if err != nil {
+				return err
+			}
+
+			if err := ix.Replace(ctx, entries); err != nil {
+				return fmt.Errorf("rebuild index: %w", err)
+			}
+
+			fmt.Printf("Indexed %d paper(s).\n", len(entries))
+			for _, dir := range skipped {
+				if len(nestedPaperDirs(dir)) > 0 {
+					fmt.Printf("  skipped %s: old nested layout (run 'arc-arxiv doctor --fix')\n", dir)
+					continue
+				}
+				fmt.Printf("  skipped %s: unreadable meta.yaml\n", dir)
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// scanLibrary reads every paper directory under papersRoot. Directories whose
+// meta.yaml cannot be read are returned separately in skipped.
+func scanLibrary(papersRoot string) (entries []index.Entry, skipped []string, err error) {
+	dirs, err := os.ReadDir(papersRoot)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		dir := filepath.Join(papersRoot, d.Name())
+		meta, err := readMeta(filepath.Join(dir, "meta.yaml"))
+		if err == nil && meta.ArxivID == "" {
+			// Directory names map back to IDs, see arxiv.DirName.
+			if id, ok := arxiv.IDFromDirName(d.Name()); ok {
+				meta.ArxivID, _ = arxiv.SplitVersion(id)
+			}
+		}
+		if err != nil || meta.ArxivID == "" {
+			skipped = append(skipped, dir)
+			continue
+		}
+		entries = append(entries, index.Entry{Meta: meta, Dir: dir})
+	}
+	return entries, skipped, nil
+}
+
+// openIndex opens the library index, building it from disk the first time it
+// is used against an existing library.
+func openIndex(ctx context.Context, cfg *config.Config, db *sql.DB) (*index.Index, error) {
+	return openLibraryIndex(ctx, db, filepath.Join(cfg.ResearchRoot, "papers"))
+}
+
+// openLibraryIndex is openIndex for the library in papersRoot.
+func openLibraryIndex(ctx context.Context, db *sql.DB, papersRoot string) (*index.Index, error) {
+	ix, err := index.Open(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := ix.Count(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if n > 0 {
+		return ix, nil
+	}
+
+	entries, _, err := scanLibrary(papersRoot)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) > 0 {
+		if err := ix.Replace(ctx, entries); err != nil {
+			return nil, fmt.Errorf("build index: %w", err)
+		}
+	}
+	return ix, nil
+}
+
+// syncIndex records a paper's current metadata in the index. An index used
+// for the first time is built from the whole library first, so that it
+// never holds just the papers written since. The files on disk are
+// authoritative, so failures are reported but not fatal.
+func syncIndex(ctx context.Context, db *sql.DB, papersRoot string, meta *arxiv.ArxivMeta, dir string) {
+	ix, err := openLibraryIndex(ctx, db, papersRoot)
+	if err == nil {
+		err = ix.Upsert(ctx, index.Entry{Meta: meta, Dir: dir})
+	}
+	if err != nil {
+		fmt.Printf("Warning: index not updated for %s: %v (run 'arc-arxiv reindex')\n", meta.ArxivID, err)
+	}
+}
+
+// unindex removes a paper from the index, reporting but not failing on errors.
+func unindex(ctx context.Context, db *sql.DB, papersRoot string, id string) {
+	ix, err := openLibraryIndex(ctx, db, papersRoot)
+	if err == nil {
+		err = ix.Delete(ctx, id)
+	}
+	if err != nil {
+		fmt.Printf("Warning: index not updated for %s: %v (run 'arc-arxiv reindex')\n", id, err)
+	}
+}
+
+// unindexMoved updates the index after dir, a directory whose meta.yaml
+// names id, has left the library. The ID may belong to another paper that
+// is still in the library, as when dir held a copy under the wrong name;
+// that paper is indexed again. Otherwise the row for id is removed only if
+// it points at dir.
+func unindexMoved(ctx context.Context, db *sql.DB, papersRoot, id, dir string) {
+	ownerDir := libraryDir(papersRoot, id)
+	if ownerDir != dir {
+		if meta, err := readMeta(filepath.Join(ownerDir, "meta.yaml")); err == nil && meta.ArxivID == id {
+			syncIndex(ctx, db, papersRoot, meta, ownerDir)
+			return
+		}
+	}
+
+	ix, err := openLibraryIndex(ctx, db, papersRoot)
+	if err == nil {
+		var e *index.Entry
+		e, err = ix.Get(ctx, id)
+		switch {
+		case errors.Is(err, index.ErrNotFound):
+			err = nil
+		case err == nil && filepath.Clean(e.Dir) == filepath.Clean(dir):
+			err = ix.Delete(ctx, id)
+		}
+	}
+	if err != nil {
+		fmt.Printf("Warning: index not updated for %s: %v (run 'arc-arxiv reindex')\n", id, err)
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mtreilly/arc-arxiv/internal/arxiv"
+	"github.com/mtreilly/arc-arxiv/internal/index"
+	"github.com/yourorg/arc-sdk/db"
+)
+
+func TestSyncIndexBuildsIndexFirst(t *testing.T) {
+	ctx := context.Background()
+	papersRoot := filepath.Join(t.TempDir(), "papers")
+	for _, id := range []string{"2301.00001", "2302.00002", "2303.00003"} {
+		dir := libraryDir(papersRoot, id)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := writeMeta(filepath.Join(dir, "meta.yaml"), &arxiv.ArxivMeta{ArxivID: id, Title: "T"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	database, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("db.Open: %v", err)
+	}
+	t.Cleanup(func() { _ = database.Close() })
+
+	// The first write to an index that does not exist yet, as by a tag edit
+	// on a library fetched before there was an index.
+	meta := &arxiv.ArxivMeta{ArxivID: "2302.00002", Title: "T", Tags: []string{"new"}}
+	syncIndex(ctx, database, papersRoot, meta, libraryDir(papersRoot, meta.ArxivID))
+
+	ix, err := index.Open(ctx, database)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, err := ix.List(ctx, index.Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("index holds %d papers, want all 3 in the library", len(entries))
+	}
+	for _, e := range entries {
+		if e.Meta.ArxivID == meta.ArxivID && len(e.Meta.Tags) != 1 {
+			t.Errorf("%s not updated: tags %v", e.Meta.ArxivID, e.Meta.Tags)
+		}
+	}
+}
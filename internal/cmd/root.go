@@ -11,11 +11,16 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/mtreilly/arc-arxiv/internal/arxiv"
+	"github.com/mtreilly/arc-arxiv/internal/fsutil"
+	"github.com/mtreilly/arc-arxiv/internal/index"
+	"github.com/mtreilly/arc-arxiv/internal/settings"
+	"github.com/mtreilly/arc-arxiv/internal/source"
 	"github.com/yourorg/arc-sdk/config"
 	"github.com/yourorg/arc-sdk/output"
 	"github.com/yourorg/arc-sdk/utils"
@@ -34,25 +39,55 @@ Papers are saved to the research root under papers/<arxiv-id>/ with:
 - paper.pdf: The PDF file
 - notes.md: Template for your notes`,
 	}
+	root.PersistentFlags().Bool("no-cache", false, "Do not use or update the arXiv response cache")
 
-	root.AddCommand(newFetchCmd(cfg))
-	root.AddCommand(newListCmd(cfg))
+	root.AddCommand(newFetchCmd(cfg, db))
+	root.AddCommand(newListCmd(cfg, db))
 	root.AddCommand(newInfoCmd(cfg))
 	root.AddCommand(newOpenCmd(cfg))
-	root.AddCommand(newSearchCmd(cfg))
-	root.AddCommand(newExportCmd(cfg))
-	root.AddCommand(newUpdateCmd(cfg))
-	root.AddCommand(newDeleteCmd(cfg))
-	root.AddCommand(newStatsCmd(cfg))
+	root.AddCommand(newSearchCmd(cfg, db))
+	root.AddCommand(newExportCmd(cfg, db))
+	root.AddCommand(newUpdateCmd(cfg, db))
+	root.AddCommand(newDeleteCmd(cfg, db))
+	root.AddCommand(newStatsCmd(cfg, db))
+	root.AddCommand(newReindexCmd(cfg, db))
+	root.AddCommand(newFindCmd(cfg))
+	root.AddCommand(newDoctorCmd(cfg, db))
+	root.AddCommand(newTagCmd(cfg, db))
+	root.AddCommand(newCollectionCmd(cfg, db))
+	root.AddCommand(newMarkCmd(cfg, db))
+	root.AddCommand(newSourceCmd(cfg))
+	root.AddCommand(newRefsCmd(cfg))
+	root.AddCommand(newGraphCmd(cfg, db))
+	root.AddCommand(newSimilarCmd(cfg))
+	root.AddCommand(newWatchCmd(cfg, db))
+	root.AddCommand(newFeedCmd(cfg, db))
+	root.AddCommand(newHarvestCmd(cfg))
+	root.AddCommand(newNewCmd(cfg, db))
+	root.AddCommand(newCacheCmd(cfg))
 
 	return root
 }
 
-func newFetchCmd(cfg *config.Config) *cobra.Command {
+// fetchItem is a paper queued by fetch; depth counts the references
+// followed to reach it, 0 for papers named on the command line.
+type fetchItem struct {
+	id    string
+	depth int
+}
+
+func newFetchCmd(cfg *config.Config, db *sql.DB) *cobra.Command {
 	var extractText bool
+	var extractor string
+	var fetchSrc bool
+	var flatten bool
+	var withRefs bool
+	var refsDepth int
 	var openNotes bool
 	var dryRun bool
 	var force bool
+	var workers int
+	var retryFailed bool
 
 	cmd := &cobra.Command{
 		Use:   "fetch <id-or-url> [id-or-url...]",
@@ -67,230 +102,490 @@ Accepts arXiv IDs or URLs in any format:
 Multiple papers can be fetched at once:
   arc-arxiv fetch 2304.00067 2301.12345 2312.99999
 
+The metadata of all papers is fetched together, then the PDFs are
+downloaded by --workers at a time (default 4, or fetch.workers in
+arc-arxiv.yaml), each printing a line when it finishes. Requests still
+start no faster than the rate limit allows. A paper that fails does not
+stop the others: a batch ends with a table of the papers fetched, skipped
+and failed, and fetch exits with an error if any failed. Failures are
+recorded in fetch-failed.yaml in the research root; --retry-failed fetches
+those papers again (along with any given as arguments), and a paper drops
+off the record once it is in the library.
+
 Each paper is saved to research_root/papers/<arxiv-id>/ with meta.yaml,
-paper.pdf, and notes.md files.`,
-		Args: cobra.MinimumNArgs(1),
+paper.pdf, and notes.md files. The PDF is also kept as paper.vN.pdf for
+its arXiv version; paper.pdf always holds the newest version downloaded.
+
+A version suffix (2304.00067v1) pins the paper to that version: it is
+stored in the same directory as every other version, meta.yaml records the
+pin, paper.pdf points at it and update leaves it alone. Fetching a version
+that is not held yet adds it without --force.
+
+Re-fetching with --force downloads the requested (or current) version and
+refreshes paper.pdf and meta.yaml; without a version suffix it also removes
+any pin. PDFs of earlier versions are kept. Existing
+notes.md and any other files in the paper directory are kept; if the notes
+template has changed, the new version is written to notes.template.md.
+
+--source also downloads the paper's LaTeX source into source/ (see the
+source command); --flatten writes its main file with \input expanded.
+
+--refs parses the paper's reference list into references.yaml (see the refs
+command) and then fetches every referenced arXiv paper that is not in the
+library yet. --refs-depth sets how many levels of references to follow: 1
+fetches the papers the requested ones cite, 2 also the papers those cite,
+and so on. Papers already in the library have their references followed
+too. Combine with --source for the most accurate reference lists.
+
+--extract-text writes the PDF text to body.md, with a marker before each
+page, and the text of each page to body.pages.json. The built-in extractor
+needs no external tools; --extractor pdftotext uses poppler instead.
+
+notes.md is rendered from a text/template file when one is configured in
+arc-arxiv.yaml in the research root, optionally per category.`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if retryFailed {
+				return nil
+			}
+			return cobra.MinimumNArgs(1)(cmd, args)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 			if ctx == nil {
 				ctx = context.Background()
 			}
+			if force {
+				ctx = arxiv.SkipCache(ctx)
+			}
 
 			papersRoot := filepath.Join(cfg.ResearchRoot, "papers")
 
+			if retryFailed {
+				failures, err := loadFailedFetches(failedFetchesPath(cfg))
+				if err != nil {
+					return err
+				}
+				if len(failures) == 0 && len(args) == 0 {
+					fmt.Println("No failed fetches recorded.")
+					return nil
+				}
+				for _, f := range failures {
+					args = append(args, f.ID)
+				}
+			}
+
+			// Every paper ends up fetched, skipped or failed; a failure
+			// never stops the others.
+			var outcomes []fetchOutcome
+
 			// Normalize all IDs first
-			ids := make([]string, 0, len(args))
+			queue := make([]fetchItem, 0, len(args))
+			queued := make(map[string]bool)
 			for _, input := range args {
 				id, err := arxiv.NormalizeArxivID(input)
 				if err != nil {
-					return fmt.Errorf("invalid arXiv ID or URL: %s", input)
+					outcomes = append(outcomes, fetchOutcome{ID: input, Result: fetchFailed, Detail: "invalid arXiv ID or URL"})
+					continue
+				}
+				base, _ := arxiv.SplitVersion(id)
+				if retryFailed && queued[base] {
+					continue
 				}
-				ids = append(ids, id)
+				queue = append(queue, fetchItem{id: id})
+				queued[base] = true
+			}
+			if withRefs && refsDepth < 1 {
+				return fmt.Errorf("--refs-depth must be at least 1")
+			}
+
+			set, err := settings.Load(cfg.ResearchRoot)
+			if err != nil {
+				return err
+			}
+			notesTmpl, err := loadNotesTemplates(set)
+			if err != nil {
+				return err
+			}
+			backend, err := resolveExtractor(extractor, set.Extract.Backend)
+			if err != nil {
+				return err
+			}
+			if workers, err = fetchWorkers(workers, set); err != nil {
+				return err
 			}
 
 			// Create arxiv client
-			client, err := arxiv.NewClient()
+			client, err := newClient(cmd, cfg)
 			if err != nil {
-				return fmt.Errorf("create arxiv client: %w", err)
+				return err
 			}
 
-			for _, id := range ids {
-				destDir := filepath.Join(papersRoot, id)
+			// With --refs, the references of every paper handled are parsed
+			// and the missing ones queued, up to refsDepth levels deep.
+			var lib *libraryLinks
+			refLists := make(map[string]*referenceList)
+			followRefs := func(item fetchItem, dir string, meta *arxiv.ArxivMeta) string {
+				if lib == nil {
+					loaded, err := loadLibraryLinks(papersRoot)
+					if err != nil {
+						return fmt.Sprintf("not parsed: %v", err)
+					}
+					lib = loaded
+				}
+				lib.add(meta)
+				list, err := updateReferences(dir, meta.ArxivID, lib)
+				if err != nil {
+					return fmt.Sprintf("not parsed: %v", err)
+				}
+				refLists[dir] = list
+				if item.depth+1 > refsDepth {
+					return fmt.Sprintf("%d (depth limit reached)", len(list.References))
+				}
+				added := 0
+				for _, ref := range missingReferences(list) {
+					if !queued[ref] {
+						queued[ref] = true
+						queue = append(queue, fetchItem{id: ref, depth: item.depth + 1})
+						added++
+					}
+				}
+				return fmt.Sprintf("%d, %d new to fetch", len(list.References), added)
+			}
 
-				if _, err := os.Stat(destDir); err == nil {
-					if !force {
-						fmt.Printf("Paper %s already exists at %s (use --force to re-fetch)\n", id, destDir)
+			// Papers are fetched in batches: metadata for the whole batch in
+			// one request, then the PDFs by a pool of workers. References
+			// queued while a batch is stored form the next one.
+			for start := 0; start < len(queue); {
+				batch := queue[start : start+nextBatch(queue[start:])]
+				start += len(batch)
+
+				var jobs []*fetchJob
+				for _, item := range batch {
+					requested := item.id
+
+					// The library is keyed on the base ID; a version suffix
+					// pins the paper to that version.
+					id, pinned := arxiv.SplitVersion(requested)
+					destDir := libraryDir(papersRoot, id)
+
+					existed := false
+					if _, err := os.Stat(destDir); err == nil {
+						newVersion := pinned > 0 && !slices.Contains(localVersions(destDir), pinned)
+						if !force && !newVersion {
+							fmt.Printf("Paper %s already exists at %s (use --force to re-fetch)\n", requested, destDir)
+							outcomes = append(outcomes, fetchOutcome{ID: requested, Depth: item.depth, Result: fetchSkipped, Detail: "already in library"})
+							if withRefs && !dryRun {
+								if meta, err := readMeta(filepath.Join(destDir, "meta.yaml")); err == nil {
+									fmt.Printf("  References: %s\n", followRefs(item, destDir, meta))
+								}
+							}
+							continue
+						}
+						existed = true
+						if force {
+							fmt.Printf("Re-fetching paper %s...\n", requested)
+						} else {
+							fmt.Printf("Fetching v%d of paper %s...\n", pinned, id)
+						}
+					}
+
+					if item.depth > 0 {
+						fmt.Printf("Fetching reference %s (depth %d)...\n", requested, item.depth)
+					}
+					if dryRun {
+						fmt.Printf("[dry-run] Would fetch paper:\n")
+						fmt.Printf("  ID: %s\n", requested)
+						fmt.Printf("  Directory: %s\n", destDir)
+						if existed {
+							fmt.Printf("  Files: paper.vN.pdf, paper.pdf, meta.yaml (notes.md, earlier versions and other files kept)\n")
+						} else {
+							fmt.Printf("  Files: paper.vN.pdf, paper.pdf, meta.yaml, notes.md\n")
+						}
 						continue
 					}
-					fmt.Printf("Re-fetching paper %s...\n", id)
-				}
 
-				if dryRun {
-					fmt.Printf("[dry-run] Would fetch paper:\n")
-					fmt.Printf("  ID: %s\n", id)
-					fmt.Printf("  Directory: %s\n", destDir)
-					fmt.Printf("  Files: paper.pdf, meta.yaml, notes.md\n")
+					jobs = append(jobs, &fetchJob{item: item, id: id, pinned: pinned, dir: destDir, existed: existed})
+				}
+				if len(jobs) == 0 {
 					continue
 				}
 
-				fmt.Printf("Fetching metadata for %s...\n", id)
-				meta, err := client.FetchArticle(ctx, id)
-				if err != nil {
-					return fmt.Errorf("fetch metadata for %s: %w", id, err)
-				}
+				fmt.Printf("Fetching metadata for %d paper(s)...\n", len(jobs))
+				fetchMetadata(ctx, client, jobs)
 
-				// Create directory
-				if err := os.MkdirAll(destDir, 0o755); err != nil {
-					return fmt.Errorf("create directory: %w", err)
+				var downloads []*fetchJob
+				for _, job := range jobs {
+					if job.err != nil {
+						continue
+					}
+					if job.pinned > 0 {
+						job.meta.Version = job.pinned
+						job.meta.PinnedVersion = job.pinned
+					}
+					if err := os.MkdirAll(job.dir, 0o755); err != nil {
+						job.err = fmt.Errorf("create directory: %w", err)
+						continue
+					}
+					// Keep a PDF from before per-version storage under its
+					// own version before a newer one lands next to it.
+					job.prevMeta, _ = readMeta(filepath.Join(job.dir, "meta.yaml"))
+					if job.prevMeta != nil {
+						if err := adoptLegacyPDF(job.dir, job.prevMeta.Version); err != nil {
+							job.err = fmt.Errorf("keep previous PDF: %w", err)
+							continue
+						}
+					}
+					downloads = append(downloads, job)
+				}
+				if len(downloads) > 0 {
+					fmt.Printf("Downloading %d PDF(s), %d at a time...\n", len(downloads), min(workers, len(downloads)))
+					downloadPDFs(ctx, client, downloads, workers)
+					fmt.Println()
 				}
 
-				// Download PDF with progress
-				pdfPath := filepath.Join(destDir, "paper.pdf")
-				fmt.Printf("Downloading PDF: %s\n", meta.PDFURL)
-
-				var lastProgress int
-				err = client.DownloadPDF(ctx, id, pdfPath, func(downloaded, total int64) {
-					if total > 0 {
-						pct := int(float64(downloaded) / float64(total) * 100)
-						if pct >= lastProgress+10 || pct == 100 {
-							fmt.Printf("\r  Progress: %d%%", pct)
-							lastProgress = pct
+				for _, job := range jobs {
+					item, requested, destDir, meta := job.item, job.item.id, job.dir, job.meta
+
+					// Write meta.yaml
+					metaPath := filepath.Join(destDir, "meta.yaml")
+					pdfPath := filepath.Join(destDir, "paper.pdf")
+					if job.err == nil {
+						carryUserFields(meta, job.prevMeta)
+						if err := writeMeta(metaPath, meta); err != nil {
+							job.err = fmt.Errorf("write meta: %w", err)
+						}
+					}
+					if job.err != nil {
+						// Only clean up what this fetch created; a re-fetch
+						// must never take the user's existing files with it.
+						discardFetch(job)
+						// A bad ID in someone else's bibliography must not
+						// count against the papers that were asked for.
+						if item.depth > 0 {
+							fmt.Printf("Warning: skipping reference %s: %v\n\n", requested, job.err)
+							outcomes = append(outcomes, fetchOutcome{ID: requested, Depth: item.depth, Result: fetchSkipped,
+								Detail: fmt.Sprintf("reference not fetched: %v", job.err)})
+							continue
 						}
+						outcomes = append(outcomes, fetchOutcome{ID: requested, Result: fetchFailed, Detail: job.err.Error(), Retry: true})
+						continue
 					}
-				})
-				fmt.Println()
+					syncIndex(ctx, db, papersRoot, meta, destDir)
 
-				if err != nil {
-					_ = os.RemoveAll(destDir)
-					return fmt.Errorf("download PDF: %w", err)
-				}
+					// Create notes template, keeping any notes already written
+					notesPath := filepath.Join(destDir, "notes.md")
+					notesResult, err := syncNotes(destDir, job.prevMeta, meta, notesTmpl)
+					if err != nil {
+						fmt.Printf("Warning: notes.md not written: %v\n", err)
+					}
 
-				// Write meta.yaml
-				metaPath := filepath.Join(destDir, "meta.yaml")
-				if err := writeMeta(metaPath, meta); err != nil {
-					return fmt.Errorf("write meta: %w", err)
-				}
+					// Extract text if requested
+					if extractText {
+						if err := extractPdfText(ctx, pdfPath, destDir, backend); err != nil {
+							fmt.Printf("Warning: text extraction failed: %v\n", err)
+						}
+					}
 
-				// Create notes template
-				notesPath := filepath.Join(destDir, "notes.md")
-				authorNames := make([]string, 0, len(meta.Authors))
-				for _, a := range meta.Authors {
-					authorNames = append(authorNames, a.Name)
-				}
-				notesContent := fmt.Sprintf("# %s\n\narXiv: %s\nAuthors: %s\n\n## Summary\n\n\n## Key Takeaways\n\n\n## Follow-ups\n\n",
-					meta.Title, id, strings.Join(authorNames, ", "))
-				if err := os.WriteFile(notesPath, []byte(notesContent), 0o644); err != nil {
-					return fmt.Errorf("write notes: %w", err)
-				}
+					// Download the LaTeX source if requested
+					var src *sourceResult
+					if fetchSrc {
+						fmt.Printf("Downloading source for %s...\n", requested)
+						if src, err = fetchSource(ctx, client, meta, destDir, flatten, printProgress()); err != nil {
+							fmt.Printf("Warning: source download failed: %v\n", err)
+						}
+					}
 
-				// Extract text if requested
-				if extractText {
-					bodyPath := filepath.Join(destDir, "body.md")
-					if err := extractPdfText(ctx, pdfPath, bodyPath); err != nil {
-						fmt.Printf("Warning: text extraction failed: %v\n", err)
+					var refsSummary string
+					if withRefs {
+						refsSummary = followRefs(item, destDir, meta)
 					}
-				}
 
-				// Print summary
-				fmt.Printf("\nSaved: %s\n", destDir)
-				fmt.Printf("  Title: %s\n", truncate(meta.Title, 70))
-				if len(authorNames) > 0 {
-					fmt.Printf("  Authors: %s\n", truncate(strings.Join(authorNames, ", "), 70))
-				}
-				if len(meta.Categories) > 0 {
-					fmt.Printf("  Categories: %s\n", strings.Join(meta.Categories, ", "))
+					// Print summary
+					authorNames := make([]string, 0, len(meta.Authors))
+					for _, a := range meta.Authors {
+						authorNames = append(authorNames, a.Name)
+					}
+					fmt.Printf("Saved: %s\n", destDir)
+					fmt.Printf("  Title: %s\n", truncate(meta.Title, 70))
+					if len(authorNames) > 0 {
+						fmt.Printf("  Authors: %s\n", truncate(strings.Join(authorNames, ", "), 70))
+					}
+					if len(meta.Categories) > 0 {
+						fmt.Printf("  Categories: %s\n", strings.Join(meta.Categories, ", "))
+					}
+					if src != nil {
+						main := src.main
+						if main == "" {
+							main = "main file not found"
+						}
+						fmt.Printf("  Source: %s/ (%s)\n", sourceDirName, main)
+					}
+					if refsSummary != "" {
+						fmt.Printf("  References: %s\n", refsSummary)
+					}
+					if job.existed {
+						switch notesResult {
+						case notesKept:
+							fmt.Printf("  Kept: notes.md\n")
+						case notesRefreshed:
+							fmt.Printf("  Updated: notes.md (template had no edits)\n")
+						case notesSideFiled:
+							fmt.Printf("  Kept: notes.md (template changed; new version in %s)\n", notesSideFile)
+						}
+						if kept := userFiles(destDir); len(kept) > 0 {
+							fmt.Printf("  Kept: %s\n", strings.Join(kept, ", "))
+						}
+					}
+					fmt.Println()
+
+					detail := truncate(meta.Title, 50)
+					if item.depth > 0 {
+						detail = fmt.Sprintf("reference (depth %d): %s", item.depth, detail)
+					}
+					outcomes = append(outcomes, fetchOutcome{ID: requested, Depth: item.depth, Result: fetchFetched, Detail: detail})
+
+					if openNotes {
+						_ = openFile(ctx, notesPath)
+					}
 				}
-				fmt.Println()
+			}
 
-				if openNotes {
-					_ = openFile(ctx, notesPath)
+			// Papers fetched later in the run are now in the library; link
+			// the reference lists written before they arrived.
+			for dir, list := range refLists {
+				lib.link(list.References, list.ArxivID)
+				if err := writeReferences(dir, list); err != nil {
+					fmt.Printf("Warning: %s not updated for %s: %v\n", referencesFile, list.ArxivID, err)
 				}
 			}
 
+			if dryRun {
+				return nil
+			}
+			if err := recordFetchOutcomes(failedFetchesPath(cfg), outcomes, time.Now()); err != nil {
+				fmt.Printf("Warning: failures not recorded: %v\n", err)
+			}
+			if len(outcomes) > 1 {
+				printFetchSummary(outcomes)
+			}
+
+			var failed []fetchOutcome
+			for _, o := range outcomes {
+				if o.Result == fetchFailed {
+					failed = append(failed, o)
+				}
+			}
+			switch {
+			case len(failed) == 1 && len(outcomes) == 1:
+				return fmt.Errorf("%s: %s", failed[0].ID, failed[0].Detail)
+			case len(failed) > 0:
+				return fmt.Errorf("%d of %d paper(s) could not be fetched; retry with: arc-arxiv fetch --retry-failed", len(failed), len(outcomes))
+			}
 			return nil
 		},
 	}
 
 	cmd.Flags().BoolVarP(&extractText, "extract-text", "x", false, "Extract PDF text into body.md")
+	cmd.Flags().BoolVar(&fetchSrc, "source", false, "Also download the LaTeX source into source/")
+	cmd.Flags().BoolVar(&flatten, "flatten", false, "With --source, also write the main file with \\input expanded")
+	cmd.Flags().BoolVar(&withRefs, "refs", false, "Parse references and fetch the cited arXiv papers not yet in the library")
+	cmd.Flags().IntVar(&refsDepth, "refs-depth", 1, "With --refs, how many levels of references to follow")
+	cmd.Flags().StringVar(&extractor, "extractor", "", "Text extractor: builtin or pdftotext (default from arc-arxiv.yaml, else builtin)")
 	cmd.Flags().BoolVarP(&openNotes, "notes", "n", false, "Open notes.md after creation")
 	cmd.Flags().BoolVarP(&dryRun, "dry-run", "d", false, "Show planned actions without writing files")
 	cmd.Flags().BoolVarP(&force, "force", "f", false, "Re-fetch even if paper already exists")
+	cmd.Flags().BoolVar(&retryFailed, "retry-failed", false, "Fetch again the papers whose last fetch failed")
+	cmd.Flags().IntVarP(&workers, "workers", "j", 0, "PDFs to download at once (default 4, or fetch.workers in arc-arxiv.yaml)")
 
 	return cmd
 }
 
-func newListCmd(cfg *config.Config) *cobra.Command {
+func newListCmd(cfg *config.Config, db *sql.DB) *cobra.Command {
 	var out output.OutputOptions
 	var category string
 	var author string
 	var since string
+	var tag string
+	var collection string
+	var status string
+	var priority string
+	var minRating int
+	var sortBy string
 
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List downloaded papers",
+		Long: `List downloaded papers from the library index.
+
+Examples:
+  arc-arxiv list --category cs.LG
+  arc-arxiv list --tag attention
+  arc-arxiv list --status unread --sort priority
+  arc-arxiv list --min-rating 4 --sort rating
+
+Sort keys: id (default), fetched, published, priority, rating, status`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := out.Resolve(); err != nil {
 				return err
 			}
 
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
 			papersRoot := filepath.Join(cfg.ResearchRoot, "papers")
-			entries, err := os.ReadDir(papersRoot)
-			if err != nil {
-				if os.IsNotExist(err) {
-					fmt.Println("No papers downloaded yet.")
-					return nil
-				}
-				return err
+			if _, err := os.Stat(papersRoot); os.IsNotExist(err) {
+				fmt.Println("No papers downloaded yet.")
+				return nil
 			}
 
-			var sinceTime time.Time
+			if !slices.Contains(listSortKeys, sortBy) {
+				return fmt.Errorf("invalid sort key %q (use %s)", sortBy, strings.Join(listSortKeys, ", "))
+			}
+
+			filter := index.Filter{
+				Category:   category,
+				Author:     author,
+				Tag:        tag,
+				Collection: collection,
+				Status:     status,
+				Priority:   priority,
+				MinRating:  minRating,
+			}
 			if since != "" {
-				var err error
-				sinceTime, err = time.Parse("2006-01-02", since)
+				sinceTime, err := time.Parse("2006-01-02", since)
 				if err != nil {
 					return fmt.Errorf("invalid date format for --since (use YYYY-MM-DD): %w", err)
 				}
+				filter.Since = sinceTime
 			}
 
-			var papers []*arxiv.ArxivMeta
-			for _, entry := range entries {
-				if !entry.IsDir() {
-					continue
-				}
-				metaPath := filepath.Join(papersRoot, entry.Name(), "meta.yaml")
-				meta, err := readMeta(metaPath)
-				if err != nil {
-					continue
-				}
-
-				// Apply filters
-				if category != "" {
-					found := false
-					for _, c := range meta.Categories {
-						if strings.EqualFold(c, category) || strings.HasPrefix(strings.ToLower(c), strings.ToLower(category)) {
-							found = true
-							break
-						}
-					}
-					if !found {
-						continue
-					}
-				}
-
-				if author != "" {
-					found := false
-					authorLower := strings.ToLower(author)
-					for _, a := range meta.Authors {
-						if strings.Contains(strings.ToLower(a.Name), authorLower) {
-							found = true
-							break
-						}
-					}
-					if !found {
-						continue
-					}
-				}
-
-				if !sinceTime.IsZero() {
-					fetchedAt, err := time.Parse(time.RFC3339, meta.FetchedAt)
-					if err != nil || fetchedAt.Before(sinceTime) {
-						continue
-					}
-				}
+			ix, err := openIndex(ctx, cfg, db)
+			if err != nil {
+				return err
+			}
+			entries, err := ix.List(ctx, filter)
+			if err != nil {
+				return err
+			}
 
-				papers = append(papers, meta)
+			papers := make([]*arxiv.ArxivMeta, 0, len(entries))
+			for _, e := range entries {
+				papers = append(papers, e.Meta)
 			}
 
 			if len(papers) == 0 {
 				fmt.Println("No papers found.")
 				return nil
 			}
+			sortPapers(papers, sortBy)
 
 			if out.Is(output.OutputJSON) {
 				return output.JSON(papers)
 			}
 
-			table := output.NewTable("ID", "Title", "Authors", "Fetched")
+			table := output.NewTable("ID", "Title", "Authors", "Status", "Rating", "Fetched")
 			for _, p := range papers {
 				title := truncate(p.Title, 40)
 				authors := ""
@@ -301,7 +596,15 @@ func newListCmd(cfg *config.Config) *cobra.Command {
 					}
 					authors = truncate(strings.Join(names, ", "), 30)
 				}
-				table.AddRow(p.ArxivID, title, authors, utils.HumanizeTime(parseTime(p.FetchedAt)))
+				rating := ""
+				if p.Rating > 0 {
+					rating = fmt.Sprintf("%d/%d", p.Rating, maxRating)
+				}
+				state := readingStatus(p)
+				if p.Priority != "" {
+					state += " (" + p.Priority + ")"
+				}
+				table.AddRow(p.ArxivID, title, authors, state, rating, utils.HumanizeTime(parseTime(p.FetchedAt)))
 			}
 			table.Render()
 
@@ -313,31 +616,67 @@ func newListCmd(cfg *config.Config) *cobra.Command {
 	cmd.Flags().StringVarP(&category, "category", "c", "", "Filter by category (e.g., cs.LG)")
 	cmd.Flags().StringVarP(&author, "author", "a", "", "Filter by author name")
 	cmd.Flags().StringVar(&since, "since", "", "Filter papers fetched after date (YYYY-MM-DD)")
+	cmd.Flags().StringVar(&tag, "tag", "", "Filter by tag")
+	cmd.Flags().StringVar(&collection, "collection", "", "Filter by collection")
+	cmd.Flags().StringVar(&status, "status", "", "Filter by reading status (unread, reading, read, skipped)")
+	cmd.Flags().StringVar(&priority, "priority", "", "Filter by priority (high, medium, low)")
+	cmd.Flags().IntVar(&minRating, "min-rating", 0, "Only papers rated at least this (1-5)")
+	cmd.Flags().StringVar(&sortBy, "sort", "id", "Sort by: id, fetched, published, priority, rating, status")
 
 	return cmd
 }
 
+// listSortKeys are the orderings accepted by list --sort.
+var listSortKeys = []string{"id", "fetched", "published", "priority", "rating", "status"}
+
+// sortPapers orders papers by key. Ties keep ID order. Newest first for
+// dates, best first for priority and rating, reading-flow order for status.
+func sortPapers(papers []*arxiv.ArxivMeta, key string) {
+	slices.SortStableFunc(papers, func(a, b *arxiv.ArxivMeta) int {
+		switch key {
+		case "fetched":
+			return strings.Compare(b.FetchedAt, a.FetchedAt)
+		case "published":
+			return strings.Compare(b.Published, a.Published)
+		case "priority":
+			return priorityRank(a.Priority) - priorityRank(b.Priority)
+		case "rating":
+			return b.Rating - a.Rating
+		case "status":
+			return slices.Index(readingStatuses, readingStatus(a)) - slices.Index(readingStatuses, readingStatus(b))
+		}
+		return 0
+	})
+}
+
 func newInfoCmd(cfg *config.Config) *cobra.Command {
 	var out output.OutputOptions
+	var remote bool
 
 	cmd := &cobra.Command{
 		Use:   "info <id>",
 		Short: "Show paper details",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := out.Resolve(); err != nil {
-				return err
-			}
-
-			id, err := arxiv.NormalizeArxivID(args[0])
+			err := out.Resolve()
 			if err != nil {
-				id = args[0] // fallback to raw input for local lookup
+				return err
 			}
-			metaPath := filepath.Join(cfg.ResearchRoot, "papers", id, "meta.yaml")
 
-			meta, err := readMeta(metaPath)
-			if err != nil {
-				return fmt.Errorf("paper not found: %s", id)
+			var meta *arxiv.ArxivMeta
+			paperDir := ""
+			if remote {
+				meta, err = fetchRemoteMeta(cmd, cfg, args[0])
+				if err != nil {
+					return err
+				}
+			} else {
+				id, _ := libraryID(args[0])
+				paperDir = libraryDir(filepath.Join(cfg.ResearchRoot, "papers"), id)
+				meta, err = readMeta(filepath.Join(paperDir, "meta.yaml"))
+				if err != nil {
+					return fmt.Errorf("paper not found: %s", id)
+				}
 			}
 
 			if out.Is(output.OutputJSON) {
@@ -374,6 +713,34 @@ func newInfoCmd(cfg *config.Config) *cobra.Command {
 			if meta.Version > 0 {
 				fmt.Printf("Version:         v%d\n", meta.Version)
 			}
+			if meta.PinnedVersion > 0 {
+				fmt.Printf("Pinned:          v%d\n", meta.PinnedVersion)
+			}
+			if paperDir != "" {
+				if versions := localVersions(paperDir); len(versions) > 0 {
+					fmt.Printf("Local PDFs:      %s\n", formatVersions(versions))
+				}
+				if srcDir := filepath.Join(paperDir, sourceDirName); isDir(srcDir) {
+					if main, err := source.MainFile(srcDir); err == nil {
+						fmt.Printf("Source:          %s/%s\n", sourceDirName, main)
+					} else {
+						fmt.Printf("Source:          %s/\n", sourceDirName)
+					}
+				}
+				fmt.Printf("Status:          %s\n", readingStatus(meta))
+			}
+			if meta.Priority != "" {
+				fmt.Printf("Priority:        %s\n", meta.Priority)
+			}
+			if meta.Rating > 0 {
+				fmt.Printf("Rating:          %d/%d\n", meta.Rating, maxRating)
+			}
+			if len(meta.Tags) > 0 {
+				fmt.Printf("Tags:            %s\n", strings.Join(meta.Tags, ", "))
+			}
+			if len(meta.Collections) > 0 {
+				fmt.Printf("Collections:     %s\n", strings.Join(meta.Collections, ", "))
+			}
 			if meta.DOI != "" {
 				fmt.Printf("DOI:             %s\n", meta.DOI)
 			}
@@ -392,6 +759,7 @@ func newInfoCmd(cfg *config.Config) *cobra.Command {
 	}
 
 	out.AddOutputFlags(cmd, output.OutputTable)
+	cmd.Flags().BoolVar(&remote, "remote", false, "Show arXiv's current metadata instead of the library copy")
 
 	return cmd
 }
@@ -400,17 +768,23 @@ func newOpenCmd(cfg *config.Config) *cobra.Command {
 	var pdf bool
 	var notes bool
 	var web bool
+	var version int
 
 	cmd := &cobra.Command{
 		Use:   "open <id>",
 		Short: "Open a paper",
-		Args:  cobra.ExactArgs(1),
+		Long: `Open a paper's directory, PDF, notes or arXiv page.
+
+Examples:
+  arc-arxiv open 2304.00067              # Open the paper directory
+  arc-arxiv open 2304.00067 --pdf        # Open the newest PDF
+  arc-arxiv open 2304.00067 --pdf --version 1
+  arc-arxiv open 2304.00067v1 --pdf      # Same as --version 1
+  arc-arxiv open 2304.00067 --web`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			id, err := arxiv.NormalizeArxivID(args[0])
-			if err != nil {
-				id = args[0]
-			}
-			paperDir := filepath.Join(cfg.ResearchRoot, "papers", id)
+			id, pinned := libraryID(args[0])
+			paperDir := libraryDir(filepath.Join(cfg.ResearchRoot, "papers"), id)
 
 			if _, err := os.Stat(paperDir); os.IsNotExist(err) {
 				return fmt.Errorf("paper not found: %s", id)
@@ -426,8 +800,25 @@ func newOpenCmd(cfg *config.Config) *cobra.Command {
 				return openURL(ctx, url)
 			}
 
+			if version == 0 {
+				version = pinned
+			}
+			if version > 0 && !pdf {
+				return fmt.Errorf("--version requires --pdf")
+			}
+
 			if pdf {
 				pdfPath := filepath.Join(paperDir, "paper.pdf")
+				if version > 0 {
+					pdfPath = filepath.Join(paperDir, versionedPDFName(version))
+					if _, err := os.Stat(pdfPath); err != nil {
+						held := localVersions(paperDir)
+						if len(held) == 0 {
+							return fmt.Errorf("v%d of %s is not held locally", version, id)
+						}
+						return fmt.Errorf("v%d of %s is not held locally (have %s)", version, id, formatVersions(held))
+					}
+				}
 				return openFile(ctx, pdfPath)
 			}
 
@@ -444,6 +835,7 @@ func newOpenCmd(cfg *config.Config) *cobra.Command {
 	cmd.Flags().BoolVar(&pdf, "pdf", false, "Open the PDF file")
 	cmd.Flags().BoolVar(&notes, "notes", false, "Open the notes file")
 	cmd.Flags().BoolVar(&web, "web", false, "Open in browser")
+	cmd.Flags().IntVar(&version, "version", 0, "With --pdf, open this arXiv version instead of the newest")
 
 	return cmd
 }
@@ -457,7 +849,7 @@ func writeMeta(path string, meta *arxiv.ArxivMeta) error {
 	if err := enc.Encode(meta); err != nil {
 		return err
 	}
-	return os.WriteFile(path, buf.Bytes(), 0o644)
+	return fsutil.WriteFile(path, buf.Bytes(), 0o644)
 }
 
 func readMeta(path string) (*arxiv.ArxivMeta, error) {
@@ -472,14 +864,6 @@ func readMeta(path string) (*arxiv.ArxivMeta, error) {
 	return &meta, nil
 }
 
-func extractPdfText(ctx context.Context, pdfPath, bodyPath string) error {
-	if _, err := exec.LookPath("pdftotext"); err != nil {
-		return fmt.Errorf("pdftotext not available")
-	}
-	cmd := exec.CommandContext(ctx, "pdftotext", pdfPath, bodyPath)
-	return cmd.Run()
-}
-
 func openFile(ctx context.Context, path string) error {
 	var cmd *exec.Cmd
 	if _, err := exec.LookPath("open"); err == nil {
@@ -496,6 +880,21 @@ func openURL(ctx context.Context, url string) error {
 	return openFile(ctx, url)
 }
 
+// printProgress returns a download callback that prints progress in 10%
+// steps on a single line.
+func printProgress() arxiv.DownloadProgress {
+	var lastProgress int
+	return func(downloaded, total int64) {
+		if total > 0 {
+			pct := int(float64(downloaded) / float64(total) * 100)
+			if pct >= lastProgress+10 || pct == 100 {
+				fmt.Printf("\r  Progress: %d%%", pct)
+				lastProgress = pct
+			}
+		}
+	}
+}
+
 func parseTime(s string) int64 {
 	t, err := time.Parse(time.RFC3339, s)
 	if err != nil {
@@ -5,6 +5,7 @@ package cmd
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"strings"
 
@@ -14,7 +15,7 @@ import (
 	"github.com/yourorg/arc-sdk/output"
 )
 
-func newSearchCmd(cfg *config.Config) *cobra.Command {
+func newSearchCmd(cfg *config.Config, db *sql.DB) *cobra.Command {
 	var out output.OutputOptions
 	var author string
 	var title string
@@ -58,9 +59,9 @@ Sort options: relevance (default), submitted, updated`,
 				return fmt.Errorf("please provide a search query or use --author, --title, --abstract, or --category flags")
 			}
 
-			client, err := arxiv.NewClient()
+			client, err := newClient(cmd, cfg)
 			if err != nil {
-				return fmt.Errorf("create arxiv client: %w", err)
+				return err
 			}
 
 			opts := &arxiv.SearchOptions{
@@ -73,10 +74,12 @@ Sort options: relevance (default), submitted, updated`,
 			}
 
 			fmt.Printf("Searching arXiv...\n")
-			results, totalResults, err := client.Search(ctx, query, opts)
+			searchCtx, report := arxiv.WithCacheReport(ctx)
+			results, totalResults, err := client.Search(searchCtx, query, opts)
 			if err != nil {
 				return fmt.Errorf("search failed: %w", err)
 			}
+			warnStale(report)
 
 			if len(results) == 0 {
 				fmt.Println("No results found.")
@@ -116,7 +119,7 @@ Sort options: relevance (default), submitted, updated`,
 			// Auto-fetch if requested
 			if fetch && len(results) > 0 {
 				fmt.Printf("\nFetching top %d results...\n", len(results))
-				fetchCmd := newFetchCmd(cfg)
+				fetchCmd := newFetchCmd(cfg, db)
 				ids := make([]string, 0, len(results))
 				for _, r := range results {
 					ids = append(ids, r.ArxivID)
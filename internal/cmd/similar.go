@@ -0,0 +1,217 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mtreilly/arc-arxiv/internal/arxiv"
+	"github.com/mtreilly/arc-arxiv/internal/pdftext"
+	"github.com/mtreilly/arc-arxiv/internal/similar"
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-sdk/config"
+	"github.com/yourorg/arc-sdk/output"
+)
+
+type similarResult struct {
+	ID    string   `json:"id"`
+	Title string   `json:"title"`
+	Score float64  `json:"score"`
+	Terms []string `json:"terms"`
+}
+
+// similarCachePath returns the file caching the term vectors of the library.
+func similarCachePath(cfg *config.Config) string {
+	return filepath.Join(cfg.ResearchRoot, "cache", "similar.gob")
+}
+
+func newSimilarCmd(cfg *config.Config) *cobra.Command {
+	var out output.OutputOptions
+	var limit int
+	var withBody bool
+	var rebuild bool
+
+	cmd := &cobra.Command{
+		Use:   "similar <id | text>",
+		Short: "Find library papers similar to a paper or text",
+		Long: `Rank the papers in the local library by their similarity to a library
+paper or to any free text, such as an abstract pasted from elsewhere.
+
+Papers are compared by TF-IDF cosine similarity over their titles and
+abstracts; --body adds the extracted text (body.md) as well. Everything is
+computed locally from the files under papers/<id>/, without network access.
+
+The term vectors of every paper are cached in cache/similar.gob under the
+research root and only recomputed for papers whose meta.yaml or body.md
+changed. --rebuild recomputes all of them.
+
+Examples:
+  arc-arxiv similar 1706.03762
+  arc-arxiv similar 1706.03762 --body --limit 5
+  arc-arxiv similar "protein structure prediction with language models"
+  arc-arxiv similar 2304.00067 --output json`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := out.Resolve(); err != nil {
+				return err
+			}
+
+			papersRoot := filepath.Join(cfg.ResearchRoot, "papers")
+			entries, err := loadSimilarVectors(papersRoot, similarCachePath(cfg), rebuild)
+			if err != nil {
+				return err
+			}
+			if len(entries) == 0 {
+				fmt.Println("No papers downloaded yet.")
+				return nil
+			}
+
+			byID := make(map[string]*similar.Entry, len(entries))
+			docs := make([]similar.Doc, 0, len(entries))
+			for _, e := range entries {
+				byID[e.ID] = e
+				v := e.Meta
+				if withBody {
+					v = similar.Merge(e.Meta, e.Body)
+				}
+				docs = append(docs, similar.Doc{ID: e.ID, Vector: v})
+			}
+			corpus := similar.NewCorpus(docs)
+
+			// A single argument naming a library paper queries by that paper;
+			// anything else is free text.
+			var query similar.Vector
+			var self string
+			if len(args) == 1 {
+				id, _ := libraryID(args[0])
+				if e, ok := byID[id]; ok {
+					self = e.ID
+					query = e.Meta
+					if withBody {
+						query = similar.Merge(e.Meta, e.Body)
+					}
+				} else if _, err := arxiv.NormalizeArxivID(args[0]); err == nil {
+					return fmt.Errorf("paper not in library: %s (fetch it first)", id)
+				}
+			}
+			if query == nil {
+				query = similar.TextVector(strings.Join(args, " "))
+			}
+
+			results := corpus.Rank(query, self, limit)
+			if len(results) == 0 {
+				fmt.Println("No similar papers.")
+				return nil
+			}
+
+			found := make([]similarResult, 0, len(results))
+			for _, r := range results {
+				found = append(found, similarResult{ID: r.ID, Title: byID[r.ID].Title, Score: r.Score, Terms: r.Terms})
+			}
+			if out.Is(output.OutputJSON) {
+				return output.JSON(found)
+			}
+
+			if self != "" {
+				fmt.Printf("Papers similar to %s: %s\n\n", self, truncate(byID[self].Title, 70))
+			}
+			table := output.NewTable("#", "ID", "Score", "Title", "Shared terms")
+			for i, r := range found {
+				table.AddRow(fmt.Sprintf("%d", i+1), r.ID, fmt.Sprintf("%.3f", r.Score), truncate(r.Title, 50), strings.Join(r.Terms, ", "))
+			}
+			table.Render()
+			return nil
+		},
+	}
+
+	out.AddOutputFlags(cmd, output.OutputTable)
+	cmd.Flags().IntVarP(&limit, "limit", "l", 10, "Maximum number of results (0 for all)")
+	cmd.Flags().BoolVar(&withBody, "body", false, "Compare the extracted text (body.md) too")
+	cmd.Flags().BoolVar(&rebuild, "rebuild", false, "Recompute every cached vector")
+
+	return cmd
+}
+
+// loadSimilarVectors returns the term vectors of every paper under
+// papersRoot, sorted by ID. Vectors are read from the cache at cachePath
+// when the paper's meta.yaml and body.md are unchanged, so a warm query
+// only has to stat each paper's files. The cache is rewritten when anything
+// changed; failing to write it is reported but not fatal.
+func loadSimilarVectors(papersRoot, cachePath string, rebuild bool) ([]*similar.Entry, error) {
+	dirs, err := os.ReadDir(papersRoot)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	cache := similar.LoadCache(cachePath)
+	if rebuild {
+		cache.Entries = make(map[string]*similar.Entry)
+	}
+	fresh := make(map[string]*similar.Entry, len(dirs))
+	changed := false
+
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		dir := filepath.Join(papersRoot, d.Name())
+		metaPath := filepath.Join(dir, "meta.yaml")
+		metaStamp := similar.StampFile(metaPath)
+		if metaStamp == (similar.Stamp{}) {
+			continue
+		}
+		bodyStamp := similar.StampFile(filepath.Join(dir, bodyFile))
+
+		if e, ok := cache.Entries[d.Name()]; ok && e.MetaStamp == metaStamp && e.BodyStamp == bodyStamp {
+			fresh[d.Name()] = e
+			continue
+		}
+
+		meta, err := readMeta(metaPath)
+		if err != nil {
+			continue
+		}
+		if meta.ArxivID == "" {
+			// Directory names map back to IDs, see arxiv.DirName.
+			if id, ok := arxiv.IDFromDirName(d.Name()); ok {
+				meta.ArxivID, _ = arxiv.SplitVersion(id)
+			} else {
+				continue
+			}
+		}
+		e := &similar.Entry{
+			ID:        meta.ArxivID,
+			Title:     meta.Title,
+			MetaStamp: metaStamp,
+			BodyStamp: bodyStamp,
+			Meta:      similar.MetaVector(meta.Title, meta.Abstract),
+		}
+		if data, err := os.ReadFile(filepath.Join(dir, bodyFile)); err == nil {
+			e.Body = similar.BodyVector(pdftext.StripMarkers(string(data)))
+		}
+		fresh[d.Name()] = e
+		changed = true
+	}
+
+	if changed || len(fresh) != len(cache.Entries) {
+		cache.Entries = fresh
+		if err := cache.Save(cachePath); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: similarity cache not saved: %v\n", err)
+		}
+	}
+
+	entries := make([]*similar.Entry, 0, len(fresh))
+	for _, e := range fresh {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ID < entries[j].ID })
+	return entries, nil
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mtreilly/arc-arxiv/internal/similar"
+)
+
+func TestLoadSimilarVectors(t *testing.T) {
+	root := t.TempDir()
+	papersRoot := filepath.Join(root, "papers")
+	cachePath := filepath.Join(root, "cache", "similar.gob")
+	for id, title := range map[string]string{"1706.03762": "Attention Is All You Need", "1512.03385": "Deep Residual Learning"} {
+		dir := filepath.Join(papersRoot, id)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		writeFile(t, filepath.Join(dir, "meta.yaml"), "arxiv_id: \""+id+"\"\ntitle: "+title+"\n")
+	}
+
+	entries, err := loadSimilarVectors(papersRoot, cachePath, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].ID != "1512.03385" || entries[1].Meta["attention"] == 0 {
+		t.Fatalf("entries = %+v", entries)
+	}
+	if len(similar.LoadCache(cachePath).Entries) != 2 {
+		t.Fatal("cache not written")
+	}
+
+	// A new body.md invalidates that paper's cached vectors.
+	writeFile(t, filepath.Join(papersRoot, "1706.03762", bodyFile), "<!-- page 1 -->\nMulti-head self-attention.\n")
+	entries, err = loadSimilarVectors(papersRoot, cachePath, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entries[1].Body["head"] == 0 {
+		t.Errorf("body vector not rebuilt: %+v", entries[1].Body)
+	}
+	if similar.LoadCache(cachePath).Entries["1706.03762"].Body["head"] == 0 {
+		t.Error("cache not updated")
+	}
+
+	// Deleted papers drop out of the cache.
+	if err := os.RemoveAll(filepath.Join(papersRoot, "1512.03385")); err != nil {
+		t.Fatal(err)
+	}
+	if entries, err = loadSimilarVectors(papersRoot, cachePath, false); err != nil || len(entries) != 1 {
+		t.Fatalf("entries = %+v, %v", entries, err)
+	}
+	if len(similar.LoadCache(cachePath).Entries) != 1 {
+		t.Error("deleted paper still cached")
+	}
+}
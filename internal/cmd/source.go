@@ -0,0 +1,183 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/mtreilly/arc-arxiv/internal/arxiv"
+	"github.com/mtreilly/arc-arxiv/internal/fsutil"
+	"github.com/mtreilly/arc-arxiv/internal/source"
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-sdk/config"
+)
+
+// sourceDirName is the subdirectory of a paper holding its unpacked e-print.
+const sourceDirName = "source"
+
+func newSourceCmd(cfg *config.Config) *cobra.Command {
+	var force bool
+	var flatten bool
+
+	cmd := &cobra.Command{
+		Use:   "source <id> [id...]",
+		Short: "Download the LaTeX source of papers",
+		Long: `Download a paper's e-print (the source files the authors submitted to arXiv)
+and unpack it into papers/<id>/source/. The paper must already be in the
+library. A pinned paper gets the source of its pinned version.
+
+The main .tex file is detected and reported. With --flatten, a copy of it
+with every \input and \include expanded is written next to it as
+<main>.flat.tex, so relative figure paths keep working.
+
+Existing source is kept unless --force is given; --flatten on its own
+flattens the source already held.
+
+Examples:
+  arc-arxiv source 2304.00067
+  arc-arxiv source 2304.00067 --flatten
+  arc-arxiv fetch 2304.00067 --source`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			papersRoot := filepath.Join(cfg.ResearchRoot, "papers")
+
+			var client *arxiv.Client
+			for _, arg := range args {
+				id, _ := libraryID(arg)
+				dir := libraryDir(papersRoot, id)
+				meta, err := readMeta(filepath.Join(dir, "meta.yaml"))
+				if err != nil {
+					return fmt.Errorf("paper not found: %s (fetch it first)", id)
+				}
+
+				srcDir := filepath.Join(dir, sourceDirName)
+				if _, err := os.Stat(srcDir); err == nil && !force {
+					if !flatten {
+						fmt.Printf("Source for %s already exists at %s (use --force to re-download)\n", id, srcDir)
+						continue
+					}
+					res, err := inspectSource(srcDir, true)
+					if err != nil {
+						return fmt.Errorf("flatten %s: %w", id, err)
+					}
+					printSourceResult(srcDir, res)
+					continue
+				}
+
+				if client == nil {
+					if client, err = newClient(cmd, cfg); err != nil {
+						return err
+					}
+				}
+				fmt.Printf("Downloading source for %s...\n", id)
+				res, err := fetchSource(ctx, client, meta, dir, flatten, printProgress())
+				if err != nil {
+					return fmt.Errorf("source for %s: %w", id, err)
+				}
+				printSourceResult(srcDir, res)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&force, "force", "f", false, "Re-download source that is already present")
+	cmd.Flags().BoolVar(&flatten, "flatten", false, "Also write the main file with \\input and \\include expanded")
+
+	return cmd
+}
+
+// sourceResult summarizes an unpacked e-print.
+type sourceResult struct {
+	files     int
+	main      string // relative to the source directory; "" if none found
+	flattened string // relative to the source directory; "" if not written
+}
+
+// fetchSource downloads the e-print of the paper meta describes and unpacks
+// it into dir/source, replacing any previous source only once the new one
+// has been unpacked completely.
+func fetchSource(ctx context.Context, client *arxiv.Client, meta *arxiv.ArxivMeta, dir string, flatten bool, progress arxiv.DownloadProgress) (*sourceResult, error) {
+	id := meta.ArxivID
+	if meta.PinnedVersion > 0 {
+		id = fmt.Sprintf("%sv%d", meta.ArxivID, meta.PinnedVersion)
+	}
+
+	archive := filepath.Join(dir, ".source.download")
+	defer func() { _ = os.Remove(archive) }()
+	err := client.DownloadSource(ctx, id, archive, progress)
+	fmt.Println()
+	if err != nil {
+		return nil, err
+	}
+
+	tmp := filepath.Join(dir, ".source.tmp")
+	_ = os.RemoveAll(tmp)
+	defer func() { _ = os.RemoveAll(tmp) }()
+	files, err := source.Unpack(archive, tmp)
+	if err != nil {
+		return nil, err
+	}
+
+	srcDir := filepath.Join(dir, sourceDirName)
+	if err := os.RemoveAll(srcDir); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp, srcDir); err != nil {
+		return nil, err
+	}
+
+	res, err := inspectSource(srcDir, flatten)
+	if res != nil {
+		res.files = len(files)
+	}
+	return res, err
+}
+
+// inspectSource finds the main file of an unpacked e-print and, if asked,
+// writes its flattened version.
+func inspectSource(srcDir string, flatten bool) (*sourceResult, error) {
+	res := &sourceResult{}
+	main, err := source.MainFile(srcDir)
+	if err != nil {
+		// Source without a recognizable main file is still worth keeping.
+		return res, nil
+	}
+	res.main = main
+	if !flatten {
+		return res, nil
+	}
+
+	text, err := source.Flatten(srcDir, main)
+	if err != nil {
+		return res, err
+	}
+	res.flattened = source.FlatName(main)
+	if err := fsutil.WriteFile(filepath.Join(srcDir, filepath.FromSlash(res.flattened)), []byte(text), 0o644); err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+func printSourceResult(srcDir string, res *sourceResult) {
+	fmt.Printf("Source: %s\n", srcDir)
+	if res.files > 0 {
+		fmt.Printf("  Files: %d\n", res.files)
+	}
+	if res.main != "" {
+		fmt.Printf("  Main file: %s\n", res.main)
+	} else {
+		fmt.Printf("  Main file: not found\n")
+	}
+	if res.flattened != "" {
+		fmt.Printf("  Flattened: %s\n", res.flattened)
+	}
+}
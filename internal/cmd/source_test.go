@@ -0,0 +1,44 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInspectSource(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "sections"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(dir, "ms.tex"), "\\documentclass{article}\n\\begin{document}\n\\input{sections/intro}\n\\end{document}\n")
+	writeFile(t, filepath.Join(dir, "sections", "intro.tex"), "Hello.\n")
+
+	res, err := inspectSource(dir, false)
+	if err != nil {
+		t.Fatalf("inspectSource: %v", err)
+	}
+	if res.main != "ms.tex" || res.flattened != "" {
+		t.Fatalf("result = %+v", res)
+	}
+
+	res, err = inspectSource(dir, true)
+	if err != nil {
+		t.Fatalf("inspectSource --flatten: %v", err)
+	}
+	if res.main != "ms.tex" || res.flattened != "ms.flat.tex" {
+		t.Fatalf("result = %+v", res)
+	}
+	if flat := readFile(t, filepath.Join(dir, "ms.flat.tex")); !strings.Contains(flat, "Hello.") {
+		t.Errorf("flattened file = %q", flat)
+	}
+
+	// The flattened copy must not be mistaken for the main file next time.
+	if res, _ := inspectSource(dir, false); res.main != "ms.tex" {
+		t.Errorf("main after flatten = %q", res.main)
+	}
+}
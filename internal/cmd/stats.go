@@ -4,86 +4,79 @@
 package cmd
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
-	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/mtreilly/arc-arxiv/internal/index"
 	"github.com/yourorg/arc-sdk/config"
 	"github.com/yourorg/arc-sdk/output"
 )
 
-func newStatsCmd(cfg *config.Config) *cobra.Command {
+func newStatsCmd(cfg *config.Config, db *sql.DB) *cobra.Command {
 	var out output.OutputOptions
+	var tag string
+	var collection string
 
 	cmd := &cobra.Command{
 		Use:   "stats",
 		Short: "Show library statistics",
 		Long: `Display statistics about downloaded papers.
 
-Shows counts by category, author, publication year, and fetch date.`,
+Shows counts by category, author, reading status, priority, rating, tag,
+collection, publication year, and fetch date. Use --tag or --collection to summarize part of the library.
+
+Examples:
+  arc-arxiv stats
+  arc-arxiv stats --collection thesis`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := out.Resolve(); err != nil {
 				return err
 			}
 
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
 			papersRoot := filepath.Join(cfg.ResearchRoot, "papers")
-			entries, err := os.ReadDir(papersRoot)
+			if _, err := os.Stat(papersRoot); os.IsNotExist(err) {
+				fmt.Println("No papers downloaded yet.")
+				return nil
+			}
+
+			ix, err := openIndex(ctx, cfg, db)
+			if err != nil {
+				return err
+			}
+			counts, err := ix.Stats(ctx, index.Filter{Tag: tag, Collection: collection})
 			if err != nil {
-				if os.IsNotExist(err) {
-					fmt.Println("No papers downloaded yet.")
-					return nil
-				}
 				return err
 			}
 
-			// Collect statistics
 			stats := &libraryStats{
-				Categories:    make(map[string]int),
-				Authors:       make(map[string]int),
-				Years:         make(map[int]int),
-				FetchedMonths: make(map[string]int),
+				TotalPapers:   counts.TotalPapers,
+				Categories:    counts.Categories,
+				Authors:       counts.Authors,
+				Years:         counts.Years,
+				FetchedMonths: counts.FetchedMonths,
+				Tags:          counts.Tags,
+				Collections:   counts.Collections,
+				Statuses:      counts.Statuses,
+				Priorities:    counts.Priorities,
+				Ratings:       counts.Ratings,
 			}
-
-			for _, entry := range entries {
-				if !entry.IsDir() {
-					continue
-				}
-				metaPath := filepath.Join(papersRoot, entry.Name(), "meta.yaml")
-				meta, err := readMeta(metaPath)
-				if err != nil {
-					continue
-				}
-
-				stats.TotalPapers++
-
-				// Count categories
-				for _, cat := range meta.Categories {
-					stats.Categories[cat]++
-				}
-
-				// Count authors
-				for _, author := range meta.Authors {
-					stats.Authors[author.Name]++
-				}
-
-				// Count publication years
-				if meta.Published != "" {
-					if t, err := time.Parse(time.RFC3339, meta.Published); err == nil {
-						stats.Years[t.Year()]++
-					}
-				}
-
-				// Count fetch months
-				if meta.FetchedAt != "" {
-					if t, err := time.Parse(time.RFC3339, meta.FetchedAt); err == nil {
-						month := t.Format("2006-01")
-						stats.FetchedMonths[month]++
-					}
+			if n := countRated(stats.Ratings); n > 0 {
+				total := 0
+				for r, c := range stats.Ratings {
+					total += r * c
 				}
+				stats.AverageRating = float64(total) / float64(n)
 			}
 
 			if stats.TotalPapers == 0 {
@@ -126,6 +119,47 @@ Shows counts by category, author, publication year, and fetch date.`,
 			}
 			fmt.Println()
 
+			// Reading progress
+			fmt.Printf("Reading Status:\n")
+			for _, st := range readingStatuses {
+				fmt.Printf("  %-20s %d\n", st, stats.Statuses[st])
+			}
+			if len(stats.Priorities) > 0 {
+				fmt.Printf("Priority:\n")
+				for _, p := range priorities {
+					if n := stats.Priorities[p]; n > 0 {
+						fmt.Printf("  %-20s %d\n", p, n)
+					}
+				}
+			}
+			if n := countRated(stats.Ratings); n > 0 {
+				fmt.Printf("Ratings: %d rated, average %.1f/%d\n", n, stats.AverageRating, maxRating)
+				for r := maxRating; r >= 1; r-- {
+					if c := stats.Ratings[r]; c > 0 {
+						fmt.Printf("  %-20s %d\n", strings.Repeat("*", r), c)
+					}
+				}
+			}
+			fmt.Println()
+
+			// User labels
+			for _, labels := range []struct {
+				title  string
+				counts map[string]int
+			}{{"Tags", stats.Tags}, {"Collections", stats.Collections}} {
+				if len(labels.counts) == 0 {
+					continue
+				}
+				fmt.Printf("%s:\n", labels.title)
+				for _, kv := range topN(labels.counts, 10) {
+					fmt.Printf("  %-20s %d\n", kv.Key, kv.Value)
+				}
+				if len(labels.counts) > 10 {
+					fmt.Printf("  ... and %d more\n", len(labels.counts)-10)
+				}
+				fmt.Println()
+			}
+
 			// Publication years
 			fmt.Printf("Publication Years:\n")
 			years := topN(stats.Years, 10)
@@ -156,6 +190,8 @@ Shows counts by category, author, publication year, and fetch date.`,
 	}
 
 	out.AddOutputFlags(cmd, output.OutputTable)
+	cmd.Flags().StringVar(&tag, "tag", "", "Only count papers with this tag")
+	cmd.Flags().StringVar(&collection, "collection", "", "Only count papers in this collection")
 
 	return cmd
 }
@@ -166,6 +202,20 @@ type libraryStats struct {
 	Authors       map[string]int `json:"authors"`
 	Years         map[int]int    `json:"years"`
 	FetchedMonths map[string]int `json:"fetched_months"`
+	Tags          map[string]int `json:"tags"`
+	Collections   map[string]int `json:"collections"`
+	Statuses      map[string]int `json:"statuses"`
+	Priorities    map[string]int `json:"priorities"`
+	Ratings       map[int]int    `json:"ratings"`
+	AverageRating float64        `json:"average_rating,omitempty"`
+}
+
+func countRated(ratings map[int]int) int {
+	n := 0
+	for _, c := range ratings {
+		n += c
+	}
+	return n
 }
 
 type kv struct {
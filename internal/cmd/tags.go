@@ -0,0 +1,282 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mtreilly/arc-arxiv/internal/arxiv"
+	"github.com/mtreilly/arc-arxiv/internal/index"
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-sdk/config"
+	"github.com/yourorg/arc-sdk/output"
+)
+
+// labelKind describes one kind of user-defined label kept in meta.yaml.
+// Tags and collections behave the same way and differ only in naming rules.
+type labelKind struct {
+	noun   string // "tag" or "collection"
+	field  func(*arxiv.ArxivMeta) *[]string
+	counts func(*index.Stats) map[string]int
+	// spaces allows whitespace inside a label ("Reading Group" collections).
+	spaces bool
+}
+
+var (
+	tagKind = labelKind{
+		noun:   "tag",
+		field:  func(m *arxiv.ArxivMeta) *[]string { return &m.Tags },
+		counts: func(s *index.Stats) map[string]int { return s.Tags },
+	}
+	collectionKind = labelKind{
+		noun:   "collection",
+		field:  func(m *arxiv.ArxivMeta) *[]string { return &m.Collections },
+		counts: func(s *index.Stats) map[string]int { return s.Collections },
+		spaces: true,
+	}
+)
+
+// validate checks a label name and returns it trimmed.
+func (k labelKind) validate(label string) (string, error) {
+	label = strings.TrimSpace(label)
+	switch {
+	case label == "":
+		return "", fmt.Errorf("%s name is empty", k.noun)
+	case strings.ContainsAny(label, ",\n"):
+		return "", fmt.Errorf("%s name %q must not contain commas or newlines", k.noun, label)
+	case !k.spaces && strings.ContainsAny(label, " \t"):
+		return "", fmt.Errorf("%s name %q must not contain spaces", k.noun, label)
+	}
+	return label, nil
+}
+
+func newTagCmd(cfg *config.Config, db *sql.DB) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tag",
+		Short: "Manage paper tags",
+		Long: `Add, remove and rename user-defined tags. Tags are stored in each paper's
+meta.yaml and can be used to filter list, export and stats with --tag.
+
+Tags are matched case-insensitively and may not contain spaces.
+
+Examples:
+  arc-arxiv tag add attention 2304.00067 1706.03762
+  arc-arxiv tag remove attention 1706.03762
+  arc-arxiv tag rename attention transformers
+  arc-arxiv tag list`,
+	}
+	addLabelCmds(cmd, cfg, db, tagKind)
+	return cmd
+}
+
+func newCollectionCmd(cfg *config.Config, db *sql.DB) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "collection",
+		Short: "Manage named collections of papers",
+		Long: `Group papers into named collections, for example one per project. A paper
+can belong to any number of collections. Collections are stored in each
+paper's meta.yaml and can be used to filter list, export and stats with
+--collection.
+
+Collection names are matched case-insensitively and may contain spaces.
+
+Examples:
+  arc-arxiv collection add thesis 2304.00067 2301.12345
+  arc-arxiv collection remove thesis 2301.12345
+  arc-arxiv collection rename thesis "Thesis Ch. 2"
+  arc-arxiv collection list
+  arc-arxiv export --collection thesis -o thesis.bib`,
+	}
+	addLabelCmds(cmd, cfg, db, collectionKind)
+	return cmd
+}
+
+func addLabelCmds(parent *cobra.Command, cfg *config.Config, db *sql.DB, kind labelKind) {
+	papersRoot := filepath.Join(cfg.ResearchRoot, "papers")
+
+	parent.AddCommand(&cobra.Command{
+		Use:   "add <" + kind.noun + "> <id...>",
+		Short: "Add papers to a " + kind.noun,
+		Args:  cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			label, err := kind.validate(args[0])
+			if err != nil {
+				return err
+			}
+			changed, err := relabelPapers(ctx, db, papersRoot, kind, args[1:], func(labels []string) ([]string, bool) {
+				return addLabel(labels, label)
+			})
+			fmt.Printf("Added %s %q to %d paper(s).\n", kind.noun, label, changed)
+			return err
+		},
+	})
+
+	parent.AddCommand(&cobra.Command{
+		Use:   "remove <" + kind.noun + "> <id...>",
+		Short: "Remove papers from a " + kind.noun,
+		Args:  cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			label := strings.TrimSpace(args[0])
+			changed, err := relabelPapers(ctx, db, papersRoot, kind, args[1:], func(labels []string) ([]string, bool) {
+				return removeLabel(labels, label)
+			})
+			fmt.Printf("Removed %s %q from %d paper(s).\n", kind.noun, label, changed)
+			return err
+		},
+	})
+
+	parent.AddCommand(&cobra.Command{
+		Use:   "rename <old> <new>",
+		Short: "Rename a " + kind.noun + " on every paper",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			oldLabel := strings.TrimSpace(args[0])
+			newLabel, err := kind.validate(args[1])
+			if err != nil {
+				return err
+			}
+
+			entries, _, err := scanLibrary(papersRoot)
+			if err != nil {
+				return err
+			}
+			changed := 0
+			for _, e := range entries {
+				labels := kind.field(e.Meta)
+				updated, ok := renameLabel(*labels, oldLabel, newLabel)
+				if !ok {
+					continue
+				}
+				*labels = updated
+				if err := writeMeta(filepath.Join(e.Dir, "meta.yaml"), e.Meta); err != nil {
+					return fmt.Errorf("write meta for %s: %w", e.Meta.ArxivID, err)
+				}
+				syncIndex(ctx, db, papersRoot, e.Meta, e.Dir)
+				changed++
+			}
+			if changed == 0 {
+				return fmt.Errorf("no papers have %s %q", kind.noun, oldLabel)
+			}
+			fmt.Printf("Renamed %s %q to %q on %d paper(s).\n", kind.noun, oldLabel, newLabel, changed)
+			return nil
+		},
+	})
+
+	var out output.OutputOptions
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List " + kind.noun + "s with paper counts",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := out.Resolve(); err != nil {
+				return err
+			}
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			ix, err := openIndex(ctx, cfg, db)
+			if err != nil {
+				return err
+			}
+			stats, err := ix.Stats(ctx, index.Filter{})
+			if err != nil {
+				return err
+			}
+			counts := kind.counts(stats)
+
+			if out.Is(output.OutputJSON) {
+				return output.JSON(counts)
+			}
+			if len(counts) == 0 {
+				fmt.Printf("No %ss yet.\n", kind.noun)
+				return nil
+			}
+
+			names := make([]string, 0, len(counts))
+			for name := range counts {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			table := output.NewTable(strings.ToUpper(kind.noun[:1])+kind.noun[1:], "Papers")
+			for _, name := range names {
+				table.AddRow(name, fmt.Sprintf("%d", counts[name]))
+			}
+			table.Render()
+			return nil
+		},
+	}
+	out.AddOutputFlags(list, output.OutputTable)
+	parent.AddCommand(list)
+}
+
+// relabelPapers applies change to the labels of each paper in args. It
+// returns how many papers changed; see editPapers.
+func relabelPapers(ctx context.Context, db *sql.DB, papersRoot string, kind labelKind, args []string, change func([]string) ([]string, bool)) (int, error) {
+	return editPapers(ctx, db, papersRoot, args, func(meta *arxiv.ArxivMeta) bool {
+		labels := kind.field(meta)
+		updated, ok := change(*labels)
+		if ok {
+			*labels = updated
+		}
+		return ok
+	})
+}
+
+// addLabel appends label unless an equal label (ignoring case) is present.
+func addLabel(labels []string, label string) ([]string, bool) {
+	for _, l := range labels {
+		if strings.EqualFold(l, label) {
+			return labels, false
+		}
+	}
+	return append(labels, label), true
+}
+
+// removeLabel drops every label equal to label, ignoring case.
+func removeLabel(labels []string, label string) ([]string, bool) {
+	kept := make([]string, 0, len(labels))
+	for _, l := range labels {
+		if !strings.EqualFold(l, label) {
+			kept = append(kept, l)
+		}
+	}
+	if len(kept) == len(labels) {
+		return labels, false
+	}
+	if len(kept) == 0 {
+		kept = nil
+	}
+	return kept, true
+}
+
+// renameLabel replaces oldLabel with newLabel, ignoring case, without
+// introducing duplicates.
+func renameLabel(labels []string, oldLabel, newLabel string) ([]string, bool) {
+	kept, ok := removeLabel(labels, oldLabel)
+	if !ok {
+		return labels, false
+	}
+	kept, _ = addLabel(kept, newLabel)
+	return kept, true
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/mtreilly/arc-arxiv/internal/arxiv"
+)
+
+func TestLabelHelpers(t *testing.T) {
+	labels, ok := addLabel([]string{"ml"}, "ML")
+	if ok || !slices.Equal(labels, []string{"ml"}) {
+		t.Errorf("addLabel duplicate = %v, %v", labels, ok)
+	}
+	labels, ok = addLabel(labels, "physics")
+	if !ok || !slices.Equal(labels, []string{"ml", "physics"}) {
+		t.Errorf("addLabel = %v, %v", labels, ok)
+	}
+
+	labels, ok = removeLabel(labels, "ML")
+	if !ok || !slices.Equal(labels, []string{"physics"}) {
+		t.Errorf("removeLabel = %v, %v", labels, ok)
+	}
+	if _, ok := removeLabel(labels, "absent"); ok {
+		t.Error("removeLabel of absent label reported a change")
+	}
+
+	labels, ok = renameLabel([]string{"a", "b"}, "A", "b")
+	if !ok || !slices.Equal(labels, []string{"b"}) {
+		t.Errorf("renameLabel onto existing label = %v, %v", labels, ok)
+	}
+}
+
+func TestLabelKindValidate(t *testing.T) {
+	if _, err := tagKind.validate("two words"); err == nil {
+		t.Error("tags with spaces should be rejected")
+	}
+	if got, err := collectionKind.validate("  Reading Group "); err != nil || got != "Reading Group" {
+		t.Errorf("collection validate = %q, %v", got, err)
+	}
+	for _, bad := range []string{"", "  ", "a,b"} {
+		if _, err := collectionKind.validate(bad); err == nil {
+			t.Errorf("validate(%q) should fail", bad)
+		}
+	}
+}
+
+func TestRelabelPapers(t *testing.T) {
+	root := t.TempDir()
+	writeTestPaper(t, root, "2304.00067", "2304.00067", validPDF)
+	writeTestPaper(t, root, "hep-th-9901001", "hep-th/9901001", validPDF)
+
+	add := func(labels []string) ([]string, bool) { return addLabel(labels, "thesis") }
+	changed, err := relabelPapers(context.Background(), nil, root, collectionKind, []string{"2304.00067", "hep-th/9901001", "2101.99999"}, add)
+	if err == nil {
+		t.Error("expected error for missing paper")
+	}
+	if changed != 2 {
+		t.Errorf("changed = %d, want 2", changed)
+	}
+
+	meta, err := readMeta(filepath.Join(root, "hep-th-9901001", "meta.yaml"))
+	if err != nil {
+		t.Fatalf("readMeta: %v", err)
+	}
+	if !slices.Equal(meta.Collections, []string{"thesis"}) {
+		t.Errorf("Collections = %v", meta.Collections)
+	}
+
+	changed, _ = relabelPapers(context.Background(), nil, root, collectionKind, []string{"2304.00067"}, add)
+	if changed != 0 {
+		t.Errorf("re-adding an existing label changed %d paper(s)", changed)
+	}
+}
+
+func TestCarryUserFields(t *testing.T) {
+	prev := &arxiv.ArxivMeta{
+		Tags:        []string{"ml"},
+		Collections: []string{"thesis"},
+		Status:      "reading",
+		Priority:    "high",
+		Rating:      3,
+	}
+	meta := &arxiv.ArxivMeta{ArxivID: "2304.00067"}
+	carryUserFields(meta, prev)
+	if !slices.Equal(meta.Tags, prev.Tags) || !slices.Equal(meta.Collections, prev.Collections) {
+		t.Errorf("user fields not carried: %+v", meta)
+	}
+	if meta.Status != "reading" || meta.Priority != "high" || meta.Rating != 3 {
+		t.Errorf("reading state not carried: %+v", meta)
+	}
+	carryUserFields(meta, nil)
+	if len(meta.Tags) != 1 {
+		t.Error("nil prev should leave meta alone")
+	}
+}
@@ -5,18 +5,21 @@ package cmd
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
-	"os"
 	"path/filepath"
+	"slices"
 
 	"github.com/spf13/cobra"
 	"github.com/mtreilly/arc-arxiv/internal/arxiv"
+	"github.com/mtreilly/arc-arxiv/internal/index"
 	"github.com/yourorg/arc-sdk/config"
 )
 
-func newUpdateCmd(cfg *config.Config) *cobra.Command {
+func newUpdateCmd(cfg *config.Config, db *sql.DB) *cobra.Command {
 	var all bool
 	var checkOnly bool
+	var download bool
 
 	cmd := &cobra.Command{
 		Use:   "update [id...]",
@@ -27,46 +30,45 @@ Examples:
   arc-arxiv update 2301.12345    # Update one paper
   arc-arxiv update --all         # Update all papers
   arc-arxiv update --check       # Check for new versions only
+  arc-arxiv update --all --download  # Also download new versions' PDFs
 
 This will re-fetch metadata from arXiv and update the local meta.yaml file.
-Use --check to see if newer versions are available without updating.`,
+Use --check to see if newer versions are available without updating.
+Papers pinned to a version (fetched as <id>vN) are checked but never updated.
+
+With --download, the PDF of the current version is downloaded whenever it
+is not held yet. It is saved as paper.vN.pdf next to the versions already
+held, and paper.pdf is switched to it.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 			if ctx == nil {
 				ctx = context.Background()
 			}
+			// Cached metadata could hide a version announced since.
+			ctx = arxiv.SkipCache(ctx)
 
 			papersRoot := filepath.Join(cfg.ResearchRoot, "papers")
 
 			var ids []string
 
 			if all {
-				entries, err := os.ReadDir(papersRoot)
+				ix, err := openIndex(ctx, cfg, db)
 				if err != nil {
-					if os.IsNotExist(err) {
-						return fmt.Errorf("no papers found")
-					}
 					return err
 				}
-
-				for _, entry := range entries {
-					if !entry.IsDir() {
-						continue
-					}
-					metaPath := filepath.Join(papersRoot, entry.Name(), "meta.yaml")
-					if _, err := os.Stat(metaPath); err == nil {
-						ids = append(ids, entry.Name())
-					}
+				entries, err := ix.List(ctx, index.Filter{})
+				if err != nil {
+					return err
+				}
+				for _, e := range entries {
+					ids = append(ids, e.Meta.ArxivID)
 				}
 			} else {
 				if len(args) == 0 {
 					return fmt.Errorf("specify paper IDs or use --all to update all papers")
 				}
 				for _, arg := range args {
-					id, err := arxiv.NormalizeArxivID(arg)
-					if err != nil {
-						id = arg
-					}
+					id, _ := libraryID(arg)
 					ids = append(ids, id)
 				}
 			}
@@ -74,17 +76,21 @@ Use --check to see if newer versions are available without updating.`,
 			if len(ids) == 0 {
 				return fmt.Errorf("no papers to update")
 			}
+			if download && checkOnly {
+				return fmt.Errorf("--download cannot be combined with --check")
+			}
 
-			client, err := arxiv.NewClient()
+			client, err := newClient(cmd, cfg)
 			if err != nil {
-				return fmt.Errorf("create arxiv client: %w", err)
+				return err
 			}
 
 			updatedCount := 0
 			newVersionCount := 0
+			downloadedCount := 0
 
 			for _, id := range ids {
-				paperDir := filepath.Join(papersRoot, id)
+				paperDir := libraryDir(papersRoot, id)
 				metaPath := filepath.Join(paperDir, "meta.yaml")
 
 				// Read current metadata
@@ -103,7 +109,8 @@ Use --check to see if newer versions are available without updating.`,
 				}
 
 				// Check for version changes
-				if newMeta.Version > currentMeta.Version {
+				newVersion := newMeta.Version > currentMeta.Version
+				if newVersion {
 					newVersionCount++
 					fmt.Printf("  %s: new version available (v%d -> v%d)\n", id, currentMeta.Version, newMeta.Version)
 				}
@@ -112,8 +119,33 @@ Use --check to see if newer versions are available without updating.`,
 					continue
 				}
 
-				// Preserve fetched_at from original
+				if currentMeta.PinnedVersion > 0 {
+					fmt.Printf("  %s: pinned to v%d, not updated (fetch %s --force to follow the latest version)\n", id, currentMeta.PinnedVersion, id)
+					continue
+				}
+
+				// Name a PDF from before per-version storage after the version
+				// meta.yaml records, before meta.yaml moves on.
+				if err := adoptLegacyPDF(paperDir, currentMeta.Version); err != nil {
+					fmt.Printf("  %s: failed to keep v%d PDF: %v\n", id, currentMeta.Version, err)
+					continue
+				}
+
+				if download && newMeta.Version > 0 && !slices.Contains(localVersions(paperDir), newMeta.Version) {
+					fmt.Printf("  %s: downloading v%d PDF\n", id, newMeta.Version)
+					err := downloadVersion(ctx, client, newMeta, paperDir, printProgress())
+					fmt.Println()
+					if err != nil {
+						// Leave meta.yaml as it was so the next run retries.
+						fmt.Printf("  %s: failed to download v%d: %v\n", id, newMeta.Version, err)
+						continue
+					}
+					downloadedCount++
+				}
+
+				// Preserve fetched_at and the user's own fields
 				newMeta.FetchedAt = currentMeta.FetchedAt
+				carryUserFields(newMeta, currentMeta)
 
 				// Write updated metadata
 				if err := writeMeta(metaPath, newMeta); err != nil {
@@ -121,6 +153,8 @@ Use --check to see if newer versions are available without updating.`,
 					continue
 				}
 
+				syncIndex(ctx, db, papersRoot, newMeta, paperDir)
+
 				updatedCount++
 				fmt.Printf("  %s: updated\n", id)
 			}
@@ -134,7 +168,9 @@ Use --check to see if newer versions are available without updating.`,
 				}
 			} else {
 				fmt.Printf("Updated %d paper(s).\n", updatedCount)
-				if newVersionCount > 0 {
+				if download {
+					fmt.Printf("Downloaded %d new version(s).\n", downloadedCount)
+				} else if newVersionCount > 0 {
 					fmt.Printf("%d paper(s) have newer versions available.\n", newVersionCount)
 				}
 			}
@@ -145,6 +181,7 @@ Use --check to see if newer versions are available without updating.`,
 
 	cmd.Flags().BoolVar(&all, "all", false, "Update all downloaded papers")
 	cmd.Flags().BoolVar(&checkOnly, "check", false, "Check for new versions without updating")
+	cmd.Flags().BoolVar(&download, "download", false, "Download the PDF of any new version")
 
 	return cmd
 }
@@ -0,0 +1,131 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mtreilly/arc-arxiv/internal/arxiv"
+	"github.com/mtreilly/arc-arxiv/internal/fsutil"
+)
+
+// Every downloaded version of a paper is kept as paper.vN.pdf. paper.pdf is
+// the pinned version or else the newest of those, so tools that only know
+// about paper.pdf keep working.
+var versionedPDFPattern = regexp.MustCompile(`^paper\.v(\d+)\.pdf$`)
+
+// versionedPDFName returns the file name used for version v of a paper.
+func versionedPDFName(v int) string {
+	return fmt.Sprintf("paper.v%d.pdf", v)
+}
+
+// localVersions returns the versions held in dir as paper.vN.pdf, in
+// ascending order.
+func localVersions(dir string) []int {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var versions []int
+	for _, e := range entries {
+		m := versionedPDFPattern.FindStringSubmatch(e.Name())
+		if m == nil || e.IsDir() {
+			continue
+		}
+		if v, err := strconv.Atoi(m[1]); err == nil && v > 0 {
+			versions = append(versions, v)
+		}
+	}
+	sort.Ints(versions)
+	return versions
+}
+
+// formatVersions renders versions as "v1, v2".
+func formatVersions(versions []int) string {
+	parts := make([]string, 0, len(versions))
+	for _, v := range versions {
+		parts = append(parts, fmt.Sprintf("v%d", v))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// adoptLegacyPDF gives a paper.pdf downloaded before per-version storage its
+// versioned name, so that fetching a newer version does not discard it.
+// version is the version recorded in meta.yaml for that download.
+func adoptLegacyPDF(dir string, version int) error {
+	if version <= 0 || len(localVersions(dir)) > 0 {
+		return nil
+	}
+	pdfPath := filepath.Join(dir, "paper.pdf")
+	if _, err := os.Stat(pdfPath); err != nil {
+		return nil
+	}
+	return linkOrCopy(pdfPath, filepath.Join(dir, versionedPDFName(version)))
+}
+
+// downloadVersion downloads the version of a paper that meta describes into
+// dir as paper.vN.pdf and refreshes paper.pdf. When meta carries no version,
+// the latest PDF is saved straight to paper.pdf.
+func downloadVersion(ctx context.Context, client *arxiv.Client, meta *arxiv.ArxivMeta, dir string, progress arxiv.DownloadProgress) error {
+	if meta.Version <= 0 {
+		return client.DownloadPDF(ctx, meta.ArxivID, filepath.Join(dir, "paper.pdf"), progress)
+	}
+	if err := client.DownloadPDFVersion(ctx, meta.ArxivID, meta.Version, filepath.Join(dir, versionedPDFName(meta.Version)), progress); err != nil {
+		return err
+	}
+	return promotePDF(dir, meta.PinnedVersion)
+}
+
+// promotePDF points paper.pdf at the pinned version when it is held, and at
+// the newest paper.vN.pdf in dir otherwise.
+func promotePDF(dir string, pinned int) error {
+	versions := localVersions(dir)
+	if len(versions) == 0 {
+		return nil
+	}
+	v := versions[len(versions)-1]
+	if pinned > 0 && slices.Contains(versions, pinned) {
+		v = pinned
+	}
+	return linkOrCopy(filepath.Join(dir, versionedPDFName(v)), filepath.Join(dir, "paper.pdf"))
+}
+
+// linkOrCopy replaces dst with src, as a hard link where the filesystem
+// allows it and as an atomic copy otherwise.
+func linkOrCopy(src, dst string) error {
+	tmp := filepath.Join(filepath.Dir(dst), "."+filepath.Base(dst)+".link.tmp")
+	_ = os.Remove(tmp)
+	if err := os.Link(src, tmp); err == nil {
+		if err := os.Rename(tmp, dst); err != nil {
+			_ = os.Remove(tmp)
+			return err
+		}
+		return nil
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+
+	out, err := fsutil.Create(dst, 0o644)
+	if err != nil {
+		return err
+	}
+	defer out.Abort()
+	if _, err := io.Copy(out, in); err != nil {
+		return err
+	}
+	return out.Commit()
+}
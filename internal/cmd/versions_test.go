@@ -0,0 +1,118 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	return string(data)
+}
+
+func TestLocalVersions(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"paper.v10.pdf", "paper.v2.pdf", "paper.pdf", "paper.v0.pdf", "paper.vx.pdf", "notes.md"} {
+		writeFile(t, filepath.Join(dir, name), "x")
+	}
+
+	got := localVersions(dir)
+	if want := []int{2, 10}; !slices.Equal(got, want) {
+		t.Errorf("localVersions = %v, want %v", got, want)
+	}
+	if s := formatVersions(got); s != "v2, v10" {
+		t.Errorf("formatVersions = %q, want %q", s, "v2, v10")
+	}
+	if localVersions(filepath.Join(dir, "missing")) != nil {
+		t.Error("expected nil for missing directory")
+	}
+}
+
+func TestAdoptLegacyPDF(t *testing.T) {
+	t.Run("names unversioned paper.pdf", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, filepath.Join(dir, "paper.pdf"), "v1 content")
+
+		if err := adoptLegacyPDF(dir, 1); err != nil {
+			t.Fatalf("adoptLegacyPDF: %v", err)
+		}
+		if got := readFile(t, filepath.Join(dir, "paper.v1.pdf")); got != "v1 content" {
+			t.Errorf("paper.v1.pdf = %q", got)
+		}
+		if got := readFile(t, filepath.Join(dir, "paper.pdf")); got != "v1 content" {
+			t.Errorf("paper.pdf changed to %q", got)
+		}
+	})
+
+	t.Run("leaves versioned library alone", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, filepath.Join(dir, "paper.pdf"), "v2 content")
+		writeFile(t, filepath.Join(dir, "paper.v2.pdf"), "v2 content")
+
+		if err := adoptLegacyPDF(dir, 1); err != nil {
+			t.Fatalf("adoptLegacyPDF: %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "paper.v1.pdf")); !os.IsNotExist(err) {
+			t.Error("paper.v1.pdf should not be created")
+		}
+	})
+
+	t.Run("unknown version", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, filepath.Join(dir, "paper.pdf"), "x")
+
+		if err := adoptLegacyPDF(dir, 0); err != nil {
+			t.Fatalf("adoptLegacyPDF: %v", err)
+		}
+		if len(localVersions(dir)) != 0 {
+			t.Error("no version should be recorded for an unknown version")
+		}
+	})
+}
+
+func TestPromotePDF(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "paper.v1.pdf"), "one")
+	writeFile(t, filepath.Join(dir, "paper.pdf"), "one")
+	writeFile(t, filepath.Join(dir, "paper.v3.pdf"), "three")
+
+	if err := promotePDF(dir, 0); err != nil {
+		t.Fatalf("promotePDF: %v", err)
+	}
+	if got := readFile(t, filepath.Join(dir, "paper.pdf")); got != "three" {
+		t.Errorf("paper.pdf = %q, want newest version", got)
+	}
+	if got := readFile(t, filepath.Join(dir, "paper.v1.pdf")); got != "one" {
+		t.Errorf("paper.v1.pdf = %q, should be untouched", got)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	for _, e := range entries {
+		if filepath.Ext(e.Name()) == ".tmp" {
+			t.Errorf("leftover temp file %s", e.Name())
+		}
+	}
+
+	if err := promotePDF(dir, 1); err != nil {
+		t.Fatalf("promotePDF pinned: %v", err)
+	}
+	if got := readFile(t, filepath.Join(dir, "paper.pdf")); got != "one" {
+		t.Errorf("paper.pdf = %q, want pinned version", got)
+	}
+}
@@ -0,0 +1,349 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mtreilly/arc-arxiv/internal/arxiv"
+	"github.com/mtreilly/arc-arxiv/internal/watch"
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-sdk/config"
+	"github.com/yourorg/arc-sdk/output"
+	"github.com/yourorg/arc-sdk/utils"
+)
+
+// watchHit is a paper reported by one or more saved searches in a run.
+type watchHit struct {
+	ID        string   `json:"id"`
+	Title     string   `json:"title"`
+	Authors   []string `json:"authors"`
+	Published string   `json:"published"`
+	Category  string   `json:"primary_category"`
+	Searches  []string `json:"searches"`
+	fetch     bool
+}
+
+// searchFunc runs an arXiv search; (*arxiv.Client).Search satisfies it.
+type searchFunc func(ctx context.Context, query string, opts *arxiv.SearchOptions) ([]*arxiv.ArxivMeta, int, error)
+
+func newWatchCmd(cfg *config.Config, db *sql.DB) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Saved searches that report new papers",
+		Long: `Save arXiv searches under a name and re-run them to see only the papers
+that are new since the previous run.
+
+Saved searches and the IDs each one has already reported are kept in
+watches.yaml in the research root, so a paper is never reported twice by
+the same search. The first run of a search reports everything it finds.
+
+Examples:
+  arc-arxiv watch add attention "sparse attention" --category cs.LG
+  arc-arxiv watch add hinton --author "Hinton" --fetch
+  arc-arxiv watch list
+  arc-arxiv watch run
+  arc-arxiv watch run attention --fetch
+  arc-arxiv watch remove hinton`,
+	}
+
+	cmd.AddCommand(newWatchAddCmd(cfg))
+	cmd.AddCommand(newWatchListCmd(cfg))
+	cmd.AddCommand(newWatchRemoveCmd(cfg))
+	cmd.AddCommand(newWatchRunCmd(cfg, db))
+	return cmd
+}
+
+func newWatchAddCmd(cfg *config.Config) *cobra.Command {
+	s := &watch.Search{}
+
+	cmd := &cobra.Command{
+		Use:   "add <name> [query]",
+		Short: "Save a search",
+		Long: `Save a search under a name. The query and filters are the same as for the
+search command; results are always taken newest first, up to --max.
+
+--fetch makes every run of this search download its new papers.`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			st, err := watch.Load(watch.Path(cfg.ResearchRoot))
+			if err != nil {
+				return err
+			}
+			s.Name = args[0]
+			if len(args) > 1 {
+				s.Query = args[1]
+			}
+			s.CreatedAt = time.Now().UTC().Format(time.RFC3339)
+			if err := st.Add(s); err != nil {
+				return err
+			}
+			if err := st.Save(); err != nil {
+				return fmt.Errorf("save searches: %w", err)
+			}
+			fmt.Printf("Saved search %q: %s\n", s.Name, describeSearch(s))
+			fmt.Printf("Run it with: arc-arxiv watch run %s\n", s.Name)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&s.Author, "author", "a", "", "Filter by author name")
+	cmd.Flags().StringVarP(&s.Title, "title", "t", "", "Filter by title")
+	cmd.Flags().StringVar(&s.Abstract, "abstract", "", "Filter by abstract content")
+	cmd.Flags().StringVarP(&s.Category, "category", "c", "", "Filter by category (e.g., cs.LG, physics.hep-th)")
+	cmd.Flags().IntVarP(&s.MaxResults, "max", "m", watch.DefaultMaxResults, "Number of newest results to check on each run")
+	cmd.Flags().BoolVar(&s.Fetch, "fetch", false, "Fetch new papers whenever the search runs")
+
+	return cmd
+}
+
+func newWatchListCmd(cfg *config.Config) *cobra.Command {
+	var out output.OutputOptions
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List saved searches",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := out.Resolve(); err != nil {
+				return err
+			}
+			st, err := watch.Load(watch.Path(cfg.ResearchRoot))
+			if err != nil {
+				return err
+			}
+			if out.Is(output.OutputJSON) {
+				return output.JSON(st.Searches)
+			}
+			if len(st.Searches) == 0 {
+				fmt.Println("No saved searches. Add one with: arc-arxiv watch add <name> <query>")
+				return nil
+			}
+
+			table := output.NewTable("Name", "Search", "Max", "Fetch", "Seen", "Last run")
+			for _, s := range st.Searches {
+				fetch, lastRun := "", "never"
+				if s.Fetch {
+					fetch = "yes"
+				}
+				if s.LastRun != "" {
+					lastRun = utils.HumanizeTime(parseTime(s.LastRun))
+				}
+				table.AddRow(s.Name, truncate(describeSearch(s), 50), fmt.Sprintf("%d", s.Options().MaxResults),
+					fetch, fmt.Sprintf("%d", len(s.Seen)), lastRun)
+			}
+			table.Render()
+			return nil
+		},
+	}
+
+	out.AddOutputFlags(cmd, output.OutputTable)
+	return cmd
+}
+
+func newWatchRemoveCmd(cfg *config.Config) *cobra.Command {
+	return &cobra.Command{
+		Use:   "remove <name...>",
+		Short: "Delete saved searches and their state",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			st, err := watch.Load(watch.Path(cfg.ResearchRoot))
+			if err != nil {
+				return err
+			}
+			for _, name := range args {
+				if err := st.Remove(name); err != nil {
+					return err
+				}
+			}
+			if err := st.Save(); err != nil {
+				return fmt.Errorf("save searches: %w", err)
+			}
+			fmt.Printf("Removed %d saved search(es).\n", len(args))
+			return nil
+		},
+	}
+}
+
+func newWatchRunCmd(cfg *config.Config, db *sql.DB) *cobra.Command {
+	var out output.OutputOptions
+	var fetch bool
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "run [name...]",
+		Short: "Run saved searches and show new papers",
+		Long: `Run every saved search, or only the named ones, and list the papers that
+none of them has reported before, newest submission first. A paper found by
+several searches is listed once with all their names.
+
+The new papers are then recorded as seen. --dry-run shows them without
+recording anything. --fetch downloads all new papers; searches saved with
+--fetch always download theirs.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := out.Resolve(); err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			st, err := watch.Load(watch.Path(cfg.ResearchRoot))
+			if err != nil {
+				return err
+			}
+			searches := st.Searches
+			if len(args) > 0 {
+				searches = nil
+				for _, name := range args {
+					s := st.Get(name)
+					if s == nil {
+						return fmt.Errorf("no saved search named %q", name)
+					}
+					searches = append(searches, s)
+				}
+			}
+			if len(searches) == 0 {
+				fmt.Println("No saved searches. Add one with: arc-arxiv watch add <name> <query>")
+				return nil
+			}
+
+			client, err := newClient(cmd, cfg)
+			if err != nil {
+				return err
+			}
+
+			hits, failed := runWatches(ctx, client.Search, searches, time.Now(), !out.Is(output.OutputJSON))
+			if len(failed) == len(searches) {
+				return fmt.Errorf("all saved searches failed: %w", failed[0])
+			}
+			if !dryRun {
+				if err := st.Save(); err != nil {
+					return fmt.Errorf("save searches: %w", err)
+				}
+			}
+
+			if out.Is(output.OutputJSON) {
+				if hits == nil {
+					hits = []*watchHit{}
+				}
+				if err := output.JSON(hits); err != nil {
+					return err
+				}
+			} else if len(hits) == 0 {
+				fmt.Println("\nNo new papers.")
+			} else {
+				fmt.Printf("\n%d new paper(s):\n", len(hits))
+				table := output.NewTable("ID", "Submitted", "Title", "Authors", "Search")
+				for _, h := range hits {
+					authors := h.Authors
+					if len(authors) > 3 {
+						authors = append(authors[:3:3], "...")
+					}
+					table.AddRow(h.ID, shortDate(h.Published), truncate(h.Title, 50),
+						truncate(strings.Join(authors, ", "), 30), strings.Join(h.Searches, ", "))
+				}
+				table.Render()
+			}
+
+			var ids []string
+			for _, h := range hits {
+				if fetch || h.fetch {
+					ids = append(ids, h.ID)
+				}
+			}
+			if len(ids) == 0 || dryRun {
+				return nil
+			}
+			fmt.Printf("\nFetching %d new paper(s)...\n", len(ids))
+			fetchCmd := newFetchCmd(cfg, db)
+			fetchCmd.SetContext(ctx)
+			return fetchCmd.RunE(fetchCmd, ids)
+		},
+	}
+
+	out.AddOutputFlags(cmd, output.OutputTable)
+	cmd.Flags().BoolVar(&fetch, "fetch", false, "Fetch all new papers")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show new papers without marking them as seen")
+
+	return cmd
+}
+
+// runWatches runs each search and records its results as seen. It returns
+// the papers new to at least one search, newest submission first, and the
+// errors of the searches that failed; a failed search keeps its state.
+func runWatches(ctx context.Context, search searchFunc, searches []*watch.Search, now time.Time, verbose bool) ([]*watchHit, []error) {
+	var hits []*watchHit
+	byID := make(map[string]*watchHit)
+	var failed []error
+
+	for _, s := range searches {
+		results, _, err := search(ctx, s.Query, s.Options())
+		if err != nil {
+			err = fmt.Errorf("search %s: %w", s.Name, err)
+			failed = append(failed, err)
+			if verbose {
+				fmt.Printf("Warning: %v\n", err)
+			}
+			continue
+		}
+		fresh := s.Record(results, now)
+		if verbose {
+			fmt.Printf("%-20s %d result(s), %d new\n", s.Name, len(results), len(fresh))
+		}
+
+		for _, m := range fresh {
+			h := byID[m.ArxivID]
+			if h == nil {
+				h = &watchHit{ID: m.ArxivID, Title: oneline(m.Title), Published: m.Published, Category: m.PrimaryCategory}
+				for _, a := range m.Authors {
+					h.Authors = append(h.Authors, a.Name)
+				}
+				byID[m.ArxivID] = h
+				hits = append(hits, h)
+			}
+			h.Searches = append(h.Searches, s.Name)
+			h.fetch = h.fetch || s.Fetch
+		}
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Published != hits[j].Published {
+			return hits[i].Published > hits[j].Published
+		}
+		return hits[i].ID > hits[j].ID
+	})
+	return hits, failed
+}
+
+// describeSearch renders the criteria of a saved search on one line.
+func describeSearch(s *watch.Search) string {
+	var parts []string
+	if s.Query != "" {
+		parts = append(parts, fmt.Sprintf("%q", s.Query))
+	}
+	for _, f := range []struct{ name, value string }{
+		{"author", s.Author}, {"title", s.Title}, {"abstract", s.Abstract}, {"category", s.Category},
+	} {
+		if f.value != "" {
+			parts = append(parts, f.name+":"+f.value)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// shortDate returns the date part of an RFC 3339 timestamp.
+func shortDate(ts string) string {
+	if len(ts) >= 10 {
+		return ts[:10]
+	}
+	return ts
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mtreilly/arc-arxiv/internal/arxiv"
+	"github.com/mtreilly/arc-arxiv/internal/watch"
+)
+
+func TestRunWatches(t *testing.T) {
+	results := map[string][]*arxiv.ArxivMeta{
+		"attention": {
+			{ArxivID: "2503.00002", Title: "B", Published: "2025-03-02T00:00:00Z"},
+			{ArxivID: "2503.00001", Title: "A", Published: "2025-03-01T00:00:00Z"},
+		},
+		"hinton": {
+			{ArxivID: "2503.00003", Title: "C", Published: "2025-03-03T00:00:00Z", Authors: []arxiv.Author{{Name: "G. Hinton"}}},
+			{ArxivID: "2503.00001", Title: "A", Published: "2025-03-01T00:00:00Z"},
+		},
+	}
+	search := func(ctx context.Context, query string, opts *arxiv.SearchOptions) ([]*arxiv.ArxivMeta, int, error) {
+		if query == "broken" {
+			return nil, 0, errors.New("HTTP 503")
+		}
+		return results[query], len(results[query]), nil
+	}
+
+	searches := []*watch.Search{
+		{Name: "attention", Query: "attention"},
+		{Name: "hinton", Query: "hinton", Fetch: true, Seen: []string{"2503.00001"}},
+		{Name: "broken", Query: "broken"},
+	}
+	hits, failed := runWatches(context.Background(), search, searches, time.Now(), false)
+	if len(failed) != 1 || searches[2].LastRun != "" {
+		t.Errorf("failed = %v, broken search state %+v", failed, searches[2])
+	}
+
+	var got []string
+	for _, h := range hits {
+		got = append(got, h.ID)
+	}
+	if len(got) != 3 || got[0] != "2503.00003" || got[2] != "2503.00001" {
+		t.Fatalf("hits = %v", got)
+	}
+	if !hits[0].fetch || hits[1].fetch || hits[0].Authors[0] != "G. Hinton" {
+		t.Errorf("hit details = %+v", hits[0])
+	}
+	if s := hits[2].Searches; len(s) != 1 || s[0] != "attention" {
+		t.Errorf("2503.00001 reported by %v, want only the search that had not seen it", s)
+	}
+
+	// A second run reports nothing.
+	hits, _ = runWatches(context.Background(), search, searches[:2], time.Now(), false)
+	if len(hits) != 0 {
+		t.Errorf("second run hits = %+v", hits)
+	}
+}
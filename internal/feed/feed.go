@@ -0,0 +1,240 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+// Package feed writes lists of papers as Atom 1.0 or RSS 2.0 feeds.
+package feed
+
+import (
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"time"
+)
+
+// Feed formats.
+const (
+	FormatAtom = "atom"
+	FormatRSS  = "rss"
+)
+
+// Formats lists the formats Write accepts.
+var Formats = []string{FormatAtom, FormatRSS}
+
+// Feed is a list of papers. ID identifies the feed permanently (Atom
+// requires one); Link is where the feed or the page it describes lives and
+// may be empty.
+type Feed struct {
+	ID      string
+	Title   string
+	Link    string
+	Updated time.Time
+	Entries []Entry
+}
+
+// Entry is one paper. Updated is when the paper entered the feed, for
+// example when it was fetched, so readers order entries by that.
+type Entry struct {
+	ID         string
+	Title      string
+	Authors    []string
+	Summary    string
+	Link       string
+	PDFLink    string
+	Categories []string
+	Published  time.Time
+	Updated    time.Time
+}
+
+// Write writes f to w in the given format.
+func (f *Feed) Write(w io.Writer, format string) error {
+	switch format {
+	case FormatAtom:
+		return f.WriteAtom(w)
+	case FormatRSS:
+		return f.WriteRSS(w)
+	}
+	return fmt.Errorf("unknown feed format %q (use %s)", format, strings.Join(Formats, ", "))
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Gen     string      `xml:"generator"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href  string `xml:"href,attr"`
+	Rel   string `xml:"rel,attr,omitempty"`
+	Type  string `xml:"type,attr,omitempty"`
+	Title string `xml:"title,attr,omitempty"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Updated    string         `xml:"updated"`
+	Published  string         `xml:"published,omitempty"`
+	Authors    []atomPerson   `xml:"author"`
+	Summary    string         `xml:"summary"`
+	Links      []atomLink     `xml:"link"`
+	Categories []atomCategory `xml:"category"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// WriteAtom writes f as an Atom 1.0 feed.
+func (f *Feed) WriteAtom(w io.Writer) error {
+	doc := atomFeed{
+		ID:      f.ID,
+		Title:   f.Title,
+		Updated: atomTime(f.updated()),
+		Gen:     "arc-arxiv",
+	}
+	if f.Link != "" {
+		doc.Links = append(doc.Links, atomLink{Href: f.Link, Rel: "self"})
+	}
+	for _, e := range f.Entries {
+		ae := atomEntry{
+			ID:      e.ID,
+			Title:   e.Title,
+			Updated: atomTime(e.updated()),
+			Summary: e.Summary,
+		}
+		if !e.Published.IsZero() {
+			ae.Published = atomTime(e.Published)
+		}
+		for _, a := range e.Authors {
+			ae.Authors = append(ae.Authors, atomPerson{Name: a})
+		}
+		if e.Link != "" {
+			ae.Links = append(ae.Links, atomLink{Href: e.Link, Rel: "alternate", Type: "text/html"})
+		}
+		if e.PDFLink != "" {
+			ae.Links = append(ae.Links, atomLink{Href: e.PDFLink, Rel: "related", Type: "application/pdf", Title: "pdf"})
+		}
+		for _, c := range e.Categories {
+			ae.Categories = append(ae.Categories, atomCategory{Term: c})
+		}
+		doc.Entries = append(doc.Entries, ae)
+	}
+	return encode(w, doc)
+}
+
+type rssDoc struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	DC      string     `xml:"xmlns:dc,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Generator     string    `xml:"generator"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link,omitempty"`
+	GUID        rssGUID  `xml:"guid"`
+	PubDate     string   `xml:"pubDate"`
+	Creators    []string `xml:"dc:creator"`
+	Categories  []string `xml:"category"`
+	Description string   `xml:"description"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// WriteRSS writes f as an RSS 2.0 feed. Authors are given as Dublin Core
+// creators, since RSS authors must be e-mail addresses, and the abstract and
+// PDF link make up the HTML description.
+func (f *Feed) WriteRSS(w io.Writer) error {
+	doc := rssDoc{
+		Version: "2.0",
+		DC:      "http://purl.org/dc/elements/1.1/",
+		Channel: rssChannel{
+			Title:         f.Title,
+			Link:          f.Link,
+			Description:   f.Title,
+			LastBuildDate: f.updated().Format(time.RFC1123Z),
+			Generator:     "arc-arxiv",
+		},
+	}
+	for _, e := range f.Entries {
+		item := rssItem{
+			Title:      e.Title,
+			Link:       e.Link,
+			GUID:       rssGUID{IsPermaLink: e.ID == e.Link, Value: e.ID},
+			PubDate:    e.updated().Format(time.RFC1123Z),
+			Creators:   e.Authors,
+			Categories: e.Categories,
+		}
+		desc := "<p>" + html.EscapeString(e.Summary) + "</p>"
+		if e.PDFLink != "" {
+			desc += `<p><a href="` + html.EscapeString(e.PDFLink) + `">PDF</a></p>`
+		}
+		item.Description = desc
+		doc.Channel.Items = append(doc.Channel.Items, item)
+	}
+	return encode(w, doc)
+}
+
+func encode(w io.Writer, doc any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// updated returns the feed's Updated time, defaulting to its newest entry
+// and, for an empty feed, to now.
+func (f *Feed) updated() time.Time {
+	if !f.Updated.IsZero() {
+		return f.Updated
+	}
+	var t time.Time
+	for _, e := range f.Entries {
+		if u := e.updated(); u.After(t) {
+			t = u
+		}
+	}
+	if t.IsZero() {
+		t = time.Now()
+	}
+	return t
+}
+
+func (e Entry) updated() time.Time {
+	if e.Updated.IsZero() {
+		return e.Published
+	}
+	return e.Updated
+}
+
+func atomTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package feed
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testFeed() *Feed {
+	return &Feed{
+		ID:    "urn:arc-arxiv:tag:attention",
+		Title: "Papers tagged attention",
+		Link:  "https://example.org/attention.atom",
+		Entries: []Entry{
+			{
+				ID:         "http://arxiv.org/abs/1706.03762v7",
+				Title:      "Attention Is All You Need",
+				Authors:    []string{"Ashish Vaswani", "Noam Shazeer"},
+				Summary:    "The dominant sequence transduction models <are> based on RNNs & CNNs.",
+				Link:       "http://arxiv.org/abs/1706.03762v7",
+				PDFLink:    "http://arxiv.org/pdf/1706.03762v7",
+				Categories: []string{"cs.CL", "cs.LG"},
+				Published:  time.Date(2017, 6, 12, 17, 57, 34, 0, time.UTC),
+				Updated:    time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC),
+			},
+			{
+				ID:        "https://arxiv.org/abs/2304.00067",
+				Title:     "Second",
+				Published: time.Date(2023, 3, 31, 0, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+}
+
+func TestWriteAtom(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testFeed().Write(&buf, FormatAtom); err != nil {
+		t.Fatal(err)
+	}
+
+	var doc struct {
+		XMLName xml.Name
+		Updated string `xml:"updated"`
+		Entries []struct {
+			ID      string `xml:"id"`
+			Updated string `xml:"updated"`
+			Summary string `xml:"summary"`
+			Authors []struct {
+				Name string `xml:"name"`
+			} `xml:"author"`
+			Links []struct {
+				Href string `xml:"href,attr"`
+				Rel  string `xml:"rel,attr"`
+			} `xml:"link"`
+		} `xml:"entry"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("Atom does not parse: %v\n%s", err, buf.String())
+	}
+	if doc.XMLName.Space != "http://www.w3.org/2005/Atom" || doc.XMLName.Local != "feed" {
+		t.Errorf("root = %v", doc.XMLName)
+	}
+	if doc.Updated != "2025-03-01T09:00:00Z" || len(doc.Entries) != 2 {
+		t.Fatalf("feed updated %s with %d entries", doc.Updated, len(doc.Entries))
+	}
+	e := doc.Entries[0]
+	if len(e.Authors) != 2 || e.Authors[1].Name != "Noam Shazeer" || !strings.Contains(e.Summary, "<are>") {
+		t.Errorf("entry = %+v", e)
+	}
+	if len(e.Links) != 2 || e.Links[1].Rel != "related" || e.Links[1].Href != "http://arxiv.org/pdf/1706.03762v7" {
+		t.Errorf("links = %+v", e.Links)
+	}
+	if doc.Entries[1].Updated != "2023-03-31T00:00:00Z" {
+		t.Errorf("entry without Updated uses %s", doc.Entries[1].Updated)
+	}
+}
+
+func TestWriteRSS(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testFeed().Write(&buf, FormatRSS); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		`<rss version="2.0" xmlns:dc="http://purl.org/dc/elements/1.1/">`,
+		`<dc:creator>Ashish Vaswani</dc:creator>`,
+		`<guid isPermaLink="true">http://arxiv.org/abs/1706.03762v7</guid>`,
+		`<pubDate>Sat, 01 Mar 2025 09:00:00 +0000</pubDate>`,
+		`<category>cs.CL</category>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("RSS missing %q:\n%s", want, out)
+		}
+	}
+
+	var doc struct {
+		Items []struct {
+			Description string `xml:"description"`
+		} `xml:"channel>item"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("RSS does not parse: %v", err)
+	}
+	want := `<p>The dominant sequence transduction models &lt;are&gt; based on RNNs &amp; CNNs.</p><p><a href="http://arxiv.org/pdf/1706.03762v7">PDF</a></p>`
+	if len(doc.Items) != 2 || doc.Items[0].Description != want {
+		t.Errorf("description = %q", doc.Items[0].Description)
+	}
+}
+
+func TestWriteUnknownFormat(t *testing.T) {
+	if err := testFeed().Write(&bytes.Buffer{}, "json"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
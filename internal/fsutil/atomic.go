@@ -0,0 +1,104 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+// Package fsutil provides crash-safe file writes.
+//
+// Files are written to a temporary sibling, fsynced, and renamed over the
+// destination, so readers only ever see the old or the complete new content.
+// Temporary files are named ".<name>.<random>.tmp" and are removed on failure.
+package fsutil
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// File is a pending atomic write. Write to it like an *os.File, then call
+// Commit to publish it at its destination or Abort to discard it.
+type File struct {
+	*os.File
+	dest string
+	perm os.FileMode
+	done bool
+}
+
+// Create starts an atomic write of dest. The temporary file is created in
+// the same directory so the final rename cannot cross filesystems.
+func Create(dest string, perm os.FileMode) (*File, error) {
+	dir, base := filepath.Split(dest)
+	if dir == "" {
+		dir = "."
+	}
+	f, err := os.CreateTemp(dir, "."+base+".*.tmp")
+	if err != nil {
+		return nil, err
+	}
+	return &File{File: f, dest: dest, perm: perm}, nil
+}
+
+// Commit flushes the temporary file to stable storage and renames it over
+// the destination. The File must not be used afterwards.
+func (f *File) Commit() error {
+	if f.done {
+		return fmt.Errorf("atomic write of %s already finished", f.dest)
+	}
+	f.done = true
+	tmp := f.Name()
+
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := os.Chmod(tmp, f.perm); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, f.dest); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return syncDir(filepath.Dir(f.dest))
+}
+
+// Abort discards the temporary file. It is safe to call after Commit, which
+// makes `defer f.Abort()` the idiomatic cleanup.
+func (f *File) Abort() {
+	if f.done {
+		return
+	}
+	f.done = true
+	_ = f.Close()
+	_ = os.Remove(f.Name())
+}
+
+// WriteFile atomically replaces path with data.
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	f, err := Create(path, perm)
+	if err != nil {
+		return err
+	}
+	defer f.Abort()
+
+	if _, err := f.Write(data); err != nil {
+		return err
+	}
+	return f.Commit()
+}
+
+// syncDir fsyncs a directory so a completed rename survives a crash.
+// Filesystems that do not support syncing directories are ignored.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return nil
+	}
+	defer func() { _ = d.Close() }()
+	_ = d.Sync()
+	return nil
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package fsutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func listDir(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	return names
+}
+
+func TestWriteFile(t *testing.T) {
+	t.Run("creates new file", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "meta.yaml")
+
+		if err := WriteFile(path, []byte("title: x\n"), 0o644); err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("ReadFile: %v", err)
+		}
+		if string(data) != "title: x\n" {
+			t.Errorf("content = %q", data)
+		}
+		info, _ := os.Stat(path)
+		if info.Mode().Perm() != 0o644 {
+			t.Errorf("perm = %v, want 0644", info.Mode().Perm())
+		}
+		if names := listDir(t, dir); len(names) != 1 {
+			t.Errorf("temporary files left behind: %v", names)
+		}
+	})
+
+	t.Run("replaces existing file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "notes.md")
+		if err := os.WriteFile(path, []byte("old"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := WriteFile(path, []byte("new"), 0o644); err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+		data, _ := os.ReadFile(path)
+		if string(data) != "new" {
+			t.Errorf("content = %q, want %q", data, "new")
+		}
+	})
+
+	t.Run("fails for missing directory", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "missing", "meta.yaml")
+		if err := WriteFile(path, []byte("x"), 0o644); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
+
+func TestFile_AbortLeavesDestinationUntouched(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "paper.pdf")
+	if err := os.WriteFile(path, []byte("original"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := Create(path, 0o644)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, err := f.Write([]byte("partial")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	f.Abort()
+
+	data, _ := os.ReadFile(path)
+	if string(data) != "original" {
+		t.Errorf("destination changed to %q", data)
+	}
+	if names := listDir(t, dir); len(names) != 1 {
+		t.Errorf("temporary files left behind: %v", names)
+	}
+}
+
+func TestFile_CommitTwice(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "x")
+	f, err := Create(path, 0o644)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := f.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	if err := f.Commit(); err == nil {
+		t.Error("second Commit should fail")
+	}
+	f.Abort() // no-op after Commit
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("committed file missing: %v", err)
+	}
+}
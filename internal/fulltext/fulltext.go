@@ -0,0 +1,301 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+// Package fulltext ranks local documents against a free-text query.
+//
+// Documents are made of named, weighted fields (title, abstract, notes, ...).
+// Scoring is BM25 computed per field and combined by field weight, so a hit
+// in a title counts for more than the same hit deep in an extracted body.
+package fulltext
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// BM25 tuning constants.
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// Field is one searchable part of a document.
+type Field struct {
+	Name   string
+	Text   string
+	Weight float64
+}
+
+// Doc is a searchable document.
+type Doc struct {
+	ID     string
+	Fields []Field
+}
+
+// Match describes the hits of a query within one field.
+type Match struct {
+	Field   string `json:"field"`
+	Hits    int    `json:"hits"`
+	Snippet string `json:"snippet"`
+}
+
+// Result is a ranked document.
+type Result struct {
+	ID      string  `json:"id"`
+	Score   float64 `json:"score"`
+	Matches []Match `json:"matches"`
+}
+
+// Options controls Search.
+type Options struct {
+	// Limit caps the number of results; zero means no limit.
+	Limit int
+	// SnippetWidth is the approximate number of characters shown around
+	// the first hit in each field. Defaults to 160.
+	SnippetWidth int
+	// Highlight wraps each matched word in a snippet. Nil leaves snippets plain.
+	Highlight func(string) string
+}
+
+// token is a word in a text with its byte offsets.
+type token struct {
+	word       string
+	start, end int
+}
+
+func tokenize(text string) []token {
+	var tokens []token
+	start := -1
+	for i, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			tokens = append(tokens, token{word: strings.ToLower(text[start:i]), start: start, end: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, token{word: strings.ToLower(text[start:]), start: start, end: len(text)})
+	}
+	return tokens
+}
+
+// Terms splits a query into lowercase search terms.
+func Terms(query string) []string {
+	seen := make(map[string]bool)
+	var terms []string
+	for _, t := range tokenize(query) {
+		if !seen[t.word] {
+			seen[t.word] = true
+			terms = append(terms, t.word)
+		}
+	}
+	return terms
+}
+
+// Words splits text into lowercase words, in order and with repeats.
+func Words(text string) []string {
+	tokens := tokenize(text)
+	words := make([]string, len(tokens))
+	for i, t := range tokens {
+		words[i] = t.word
+	}
+	return words
+}
+
+// matches reports whether a word satisfies a query term. Prefix matching
+// lets "transformer" find "transformers" without a stemmer.
+func matches(word, term string) bool {
+	return strings.HasPrefix(word, term)
+}
+
+type fieldStats struct {
+	tokens []token
+	tf     []int // hits per term
+}
+
+// Search ranks docs against query. A document must contain every query
+// term in at least one of its fields to be returned.
+func Search(docs []Doc, query string, opts Options) []Result {
+	terms := Terms(query)
+	if len(terms) == 0 {
+		return nil
+	}
+	if opts.SnippetWidth <= 0 {
+		opts.SnippetWidth = 160
+	}
+
+	// Tokenize every field once and gather corpus statistics per field name.
+	stats := make([][]fieldStats, len(docs))
+	totalLen := make(map[string]int)
+	fieldCount := make(map[string]int)
+	docFreq := make(map[string][]int)
+
+	for i, doc := range docs {
+		stats[i] = make([]fieldStats, len(doc.Fields))
+		for j, f := range doc.Fields {
+			toks := tokenize(f.Text)
+			tf := make([]int, len(terms))
+			for _, tok := range toks {
+				for k, term := range terms {
+					if matches(tok.word, term) {
+						tf[k]++
+					}
+				}
+			}
+			stats[i][j] = fieldStats{tokens: toks, tf: tf}
+
+			totalLen[f.Name] += len(toks)
+			fieldCount[f.Name]++
+			if docFreq[f.Name] == nil {
+				docFreq[f.Name] = make([]int, len(terms))
+			}
+			for k := range terms {
+				if tf[k] > 0 {
+					docFreq[f.Name][k]++
+				}
+			}
+		}
+	}
+
+	var results []Result
+	for i, doc := range docs {
+		found := make([]bool, len(terms))
+		var score float64
+		var fieldMatches []Match
+
+		for j, f := range doc.Fields {
+			fs := stats[i][j]
+			n := fieldCount[f.Name]
+			avgLen := float64(totalLen[f.Name]) / float64(n)
+			if avgLen == 0 {
+				avgLen = 1
+			}
+
+			hits := 0
+			var fieldScore float64
+			for k := range terms {
+				tf := float64(fs.tf[k])
+				if tf == 0 {
+					continue
+				}
+				found[k] = true
+				hits += fs.tf[k]
+
+				df := float64(docFreq[f.Name][k])
+				idf := math.Log(1 + (float64(n)-df+0.5)/(df+0.5))
+				norm := bm25K1 * (1 - bm25B + bm25B*float64(len(fs.tokens))/avgLen)
+				fieldScore += idf * tf * (bm25K1 + 1) / (tf + norm)
+			}
+			if hits == 0 {
+				continue
+			}
+
+			weight := f.Weight
+			if weight == 0 {
+				weight = 1
+			}
+			score += weight * fieldScore
+			fieldMatches = append(fieldMatches, Match{
+				Field:   f.Name,
+				Hits:    hits,
+				Snippet: snippet(f.Text, fs.tokens, terms, opts.SnippetWidth, opts.Highlight),
+			})
+		}
+
+		all := true
+		for _, ok := range found {
+			all = all && ok
+		}
+		if !all {
+			continue
+		}
+
+		results = append(results, Result{ID: doc.ID, Score: score, Matches: fieldMatches})
+	}
+
+	sort.SliceStable(results, func(a, b int) bool {
+		return results[a].Score > results[b].Score
+	})
+	if opts.Limit > 0 && len(results) > opts.Limit {
+		results = results[:opts.Limit]
+	}
+	return results
+}
+
+// snippet returns a single-line excerpt of text around its first matching
+// token, with matching words passed through highlight.
+func snippet(text string, tokens []token, terms []string, width int, highlight func(string) string) string {
+	first := -1
+	for i, tok := range tokens {
+		if matchesAny(tok.word, terms) {
+			first = i
+			break
+		}
+	}
+	if first < 0 {
+		return ""
+	}
+
+	start := tokens[first].start - width/3
+	if start < 0 {
+		start = 0
+	}
+	end := start + width
+	if end > len(text) {
+		end = len(text)
+	}
+	// Snap to token boundaries so words and UTF-8 sequences are not cut.
+	for start > 0 && !isBoundary(text, start) {
+		start--
+	}
+	for end < len(text) && !isBoundary(text, end) {
+		end++
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("...")
+	}
+	pos := start
+	for _, tok := range tokens {
+		if tok.start < start || tok.end > end {
+			continue
+		}
+		if highlight == nil || !matchesAny(tok.word, terms) {
+			continue
+		}
+		b.WriteString(text[pos:tok.start])
+		b.WriteString(highlight(text[tok.start:tok.end]))
+		pos = tok.end
+	}
+	b.WriteString(text[pos:end])
+	if end < len(text) {
+		b.WriteString("...")
+	}
+
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+func matchesAny(word string, terms []string) bool {
+	for _, term := range terms {
+		if matches(word, term) {
+			return true
+		}
+	}
+	return false
+}
+
+func isBoundary(text string, i int) bool {
+	r := rune(text[i])
+	if r >= 0x80 {
+		return false
+	}
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package fulltext
+
+import (
+	"strings"
+	"testing"
+)
+
+func testDocs() []Doc {
+	return []Doc{
+		{ID: "a", Fields: []Field{
+			{Name: "title", Text: "Sparse Attention for Long Sequences", Weight: 3},
+			{Name: "abstract", Text: "We study transformers with sparse patterns.", Weight: 1},
+		}},
+		{ID: "b", Fields: []Field{
+			{Name: "title", Text: "Convolutional Networks", Weight: 3},
+			{Name: "abstract", Text: "Attention is briefly mentioned; sparse kernels are the focus.", Weight: 1},
+		}},
+		{ID: "c", Fields: []Field{
+			{Name: "title", Text: "Quantum Gravity", Weight: 3},
+			{Name: "abstract", Text: "Nothing relevant here.", Weight: 1},
+		}},
+	}
+}
+
+func TestTerms(t *testing.T) {
+	got := Terms("  Sparse, ATTENTION sparse-attention! ")
+	want := []string{"sparse", "attention"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Terms = %v, want %v", got, want)
+	}
+	if len(Terms("!!! ---")) != 0 {
+		t.Error("expected no terms for punctuation-only query")
+	}
+}
+
+func TestSearch_RanksTitleHitsFirst(t *testing.T) {
+	results := Search(testDocs(), "sparse attention", Options{})
+	if len(results) != 2 {
+		t.Fatalf("len(results) = %d, want 2", len(results))
+	}
+	if results[0].ID != "a" {
+		t.Errorf("top result = %q, want %q", results[0].ID, "a")
+	}
+	if results[0].Score <= results[1].Score {
+		t.Errorf("scores not descending: %v", results)
+	}
+}
+
+func TestSearch_RequiresAllTerms(t *testing.T) {
+	results := Search(testDocs(), "sparse gravity", Options{})
+	if len(results) != 0 {
+		t.Errorf("expected no results, got %v", results)
+	}
+}
+
+func TestSearch_PrefixMatch(t *testing.T) {
+	results := Search(testDocs(), "transformer", Options{})
+	if len(results) != 1 || results[0].ID != "a" {
+		t.Fatalf("results = %v, want only a", results)
+	}
+	if results[0].Matches[0].Field != "abstract" {
+		t.Errorf("matched field = %q, want abstract", results[0].Matches[0].Field)
+	}
+}
+
+func TestSearch_Limit(t *testing.T) {
+	results := Search(testDocs(), "sparse", Options{Limit: 1})
+	if len(results) != 1 {
+		t.Errorf("len(results) = %d, want 1", len(results))
+	}
+}
+
+func TestSearch_EmptyQuery(t *testing.T) {
+	if results := Search(testDocs(), "   ", Options{}); results != nil {
+		t.Errorf("expected nil results, got %v", results)
+	}
+}
+
+func TestSnippet_Highlight(t *testing.T) {
+	results := Search(testDocs(), "kernels", Options{
+		Highlight: func(s string) string { return "[" + s + "]" },
+	})
+	if len(results) != 1 {
+		t.Fatalf("len(results) = %d, want 1", len(results))
+	}
+	snip := results[0].Matches[0].Snippet
+	if !strings.Contains(snip, "[kernels]") {
+		t.Errorf("snippet %q should highlight match", snip)
+	}
+}
+
+func TestSnippet_TrimsLongText(t *testing.T) {
+	text := strings.Repeat("filler words here ", 50) + "needle " + strings.Repeat("more filler ", 50)
+	docs := []Doc{{ID: "x", Fields: []Field{{Name: "body", Text: text}}}}
+
+	results := Search(docs, "needle", Options{SnippetWidth: 60})
+	if len(results) != 1 {
+		t.Fatalf("len(results) = %d, want 1", len(results))
+	}
+	snip := results[0].Matches[0].Snippet
+	if !strings.HasPrefix(snip, "...") || !strings.HasSuffix(snip, "...") {
+		t.Errorf("snippet %q should be elided on both sides", snip)
+	}
+	if !strings.Contains(snip, "needle") {
+		t.Errorf("snippet %q should contain the match", snip)
+	}
+	if len(snip) > 120 {
+		t.Errorf("snippet too long: %d chars", len(snip))
+	}
+}
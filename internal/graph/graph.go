@@ -0,0 +1,211 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+// Package graph builds the citation graph between the papers of the local
+// library from their parsed reference lists, and writes it as DOT, GraphML
+// or JSON.
+//
+// Nodes are library papers and an edge runs from a paper to each library
+// paper it cites. References that point outside the library are collected
+// as Missing, with the papers citing them, so that works the library leans
+// on but does not hold can be found.
+package graph
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/mtreilly/arc-arxiv/internal/refs"
+)
+
+// Paper is a library paper with its reference list. HasReferences is false
+// when the references have never been parsed, which is different from a
+// paper that cites nothing.
+type Paper struct {
+	ID            string
+	Title         string
+	Year          string
+	Categories    []string
+	Tags          []string
+	References    []refs.Reference
+	HasReferences bool
+}
+
+// Node is a paper in the graph. Missing nodes stand for cited papers that
+// are not in the library; they are only present after AddMissing.
+type Node struct {
+	ID            string   `json:"id"`
+	Title         string   `json:"title"`
+	Year          string   `json:"year,omitempty"`
+	Categories    []string `json:"categories,omitempty"`
+	Tags          []string `json:"tags,omitempty"`
+	Cites         int      `json:"cites"`
+	CitedBy       int      `json:"cited_by"`
+	HasReferences bool     `json:"has_references"`
+	Missing       bool     `json:"missing,omitempty"`
+}
+
+// Edge is a citation: From cites To.
+type Edge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// External is a cited work that is not in the library, identified by its
+// arXiv ID, else its DOI, else its title.
+type External struct {
+	ArxivID string   `json:"arxiv_id,omitempty"`
+	DOI     string   `json:"doi,omitempty"`
+	Title   string   `json:"title,omitempty"`
+	CitedBy []string `json:"cited_by"`
+}
+
+// Key returns the identifier used for the work as a node ID.
+func (e External) Key() string {
+	switch {
+	case e.ArxivID != "":
+		return e.ArxivID
+	case e.DOI != "":
+		return "doi:" + e.DOI
+	}
+	return "title:" + refs.NormalizeTitle(e.Title)
+}
+
+// Graph is the citation graph of a set of papers. Nodes are sorted by ID,
+// edges by source and target, and Missing by how often each work is cited.
+type Graph struct {
+	Nodes   []Node     `json:"nodes"`
+	Edges   []Edge     `json:"edges"`
+	Missing []External `json:"missing"`
+}
+
+// minMissingTitle is the shortest normalized title used to identify a
+// cited work that has neither an arXiv ID nor a DOI.
+const minMissingTitle = 20
+
+// Build links papers through their references. A reference counts as an
+// edge when its Library field names another paper in the set; references
+// linked to library papers outside the set are ignored, and unlinked ones
+// are gathered into Missing.
+func Build(papers []Paper) *Graph {
+	g := &Graph{}
+	index := make(map[string]int, len(papers))
+	for _, p := range papers {
+		index[p.ID] = len(g.Nodes)
+		g.Nodes = append(g.Nodes, Node{
+			ID:            p.ID,
+			Title:         p.Title,
+			Year:          p.Year,
+			Categories:    p.Categories,
+			Tags:          p.Tags,
+			HasReferences: p.HasReferences,
+		})
+	}
+
+	missing := make(map[string]*External)
+	for _, p := range papers {
+		seen := make(map[string]bool)
+		for _, r := range p.References {
+			if r.Library != "" {
+				to, ok := index[r.Library]
+				if !ok || r.Library == p.ID || seen[r.Library] {
+					continue
+				}
+				seen[r.Library] = true
+				g.Edges = append(g.Edges, Edge{From: p.ID, To: r.Library})
+				g.Nodes[index[p.ID]].Cites++
+				g.Nodes[to].CitedBy++
+				continue
+			}
+
+			ext := External{ArxivID: r.ArxivID, DOI: strings.ToLower(r.DOI), Title: r.Title}
+			if ext.ArxivID == "" && ext.DOI == "" && len(refs.NormalizeTitle(ext.Title)) < minMissingTitle {
+				continue
+			}
+			key := ext.Key()
+			if seen[key] || ext.ArxivID == p.ID {
+				continue
+			}
+			seen[key] = true
+			if m, ok := missing[key]; ok {
+				m.CitedBy = append(m.CitedBy, p.ID)
+				if m.Title == "" {
+					m.Title = ext.Title
+				}
+				continue
+			}
+			ext.CitedBy = []string{p.ID}
+			missing[key] = &ext
+		}
+	}
+
+	sort.Slice(g.Nodes, func(i, j int) bool { return g.Nodes[i].ID < g.Nodes[j].ID })
+	sort.Slice(g.Edges, func(i, j int) bool {
+		if g.Edges[i].From != g.Edges[j].From {
+			return g.Edges[i].From < g.Edges[j].From
+		}
+		return g.Edges[i].To < g.Edges[j].To
+	})
+	for _, m := range missing {
+		g.Missing = append(g.Missing, *m)
+	}
+	sort.Slice(g.Missing, func(i, j int) bool {
+		a, b := g.Missing[i], g.Missing[j]
+		if len(a.CitedBy) != len(b.CitedBy) {
+			return len(a.CitedBy) > len(b.CitedBy)
+		}
+		return a.Key() < b.Key()
+	})
+	return g
+}
+
+// MostCited returns up to n library papers cited by at least one other,
+// most cited first.
+func (g *Graph) MostCited(n int) []Node {
+	var nodes []Node
+	for _, node := range g.Nodes {
+		if !node.Missing && node.CitedBy > 0 {
+			nodes = append(nodes, node)
+		}
+	}
+	sort.SliceStable(nodes, func(i, j int) bool { return nodes[i].CitedBy > nodes[j].CitedBy })
+	if n > 0 && len(nodes) > n {
+		nodes = nodes[:n]
+	}
+	return nodes
+}
+
+// Isolated returns the library papers that neither cite nor are cited by
+// any other paper in the graph.
+func (g *Graph) Isolated() []Node {
+	var nodes []Node
+	for _, node := range g.Nodes {
+		if !node.Missing && node.Cites == 0 && node.CitedBy == 0 {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+// HeavilyCited returns the missing works cited by at least min papers.
+func (g *Graph) HeavilyCited(min int) []External {
+	var out []External
+	for _, m := range g.Missing {
+		if len(m.CitedBy) >= min {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// AddMissing adds the missing works cited by at least min papers as nodes,
+// with their citations as edges, so that exports show them.
+func (g *Graph) AddMissing(min int) {
+	for _, m := range g.HeavilyCited(min) {
+		key := m.Key()
+		g.Nodes = append(g.Nodes, Node{ID: key, Title: m.Title, CitedBy: len(m.CitedBy), Missing: true})
+		for _, from := range m.CitedBy {
+			g.Edges = append(g.Edges, Edge{From: from, To: key})
+		}
+	}
+}
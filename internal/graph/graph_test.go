@@ -0,0 +1,120 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package graph
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mtreilly/arc-arxiv/internal/refs"
+)
+
+func testPapers() []Paper {
+	return []Paper{
+		{ID: "a", Title: "Paper A", HasReferences: true, References: []refs.Reference{
+			{Library: "b"},
+			{Library: "b"}, // cited twice, counted once
+			{Library: "c"},
+			{Library: "outside"}, // in the library but filtered out
+			{ArxivID: "9999.00001", Title: "Popular"},
+			{DOI: "10.1000/XYZ"},
+			{Title: "Short"}, // too little to identify
+		}},
+		{ID: "b", Title: `Paper "B"`, HasReferences: true, References: []refs.Reference{
+			{Library: "c"},
+			{ArxivID: "9999.00001"},
+			{DOI: "10.1000/xyz"},
+		}},
+		{ID: "c", Title: "Paper C", HasReferences: true},
+		{ID: "d", Title: "Paper D"},
+	}
+}
+
+func TestBuild(t *testing.T) {
+	g := Build(testPapers())
+
+	wantEdges := []Edge{{"a", "b"}, {"a", "c"}, {"b", "c"}}
+	if !reflect.DeepEqual(g.Edges, wantEdges) {
+		t.Errorf("edges = %v, want %v", g.Edges, wantEdges)
+	}
+
+	var cited []string
+	for _, n := range g.MostCited(0) {
+		cited = append(cited, n.ID)
+	}
+	if !reflect.DeepEqual(cited, []string{"c", "b"}) {
+		t.Errorf("most cited = %v", cited)
+	}
+
+	if iso := g.Isolated(); len(iso) != 1 || iso[0].ID != "d" || iso[0].HasReferences {
+		t.Errorf("isolated = %+v", iso)
+	}
+
+	heavy := g.HeavilyCited(2)
+	if len(heavy) != 2 || heavy[0].Key() != "9999.00001" || heavy[0].Title != "Popular" || heavy[1].Key() != "doi:10.1000/xyz" {
+		t.Errorf("heavily cited = %+v", heavy)
+	}
+	if len(g.Missing) != 2 {
+		t.Errorf("missing = %+v", g.Missing)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	g := Build(testPapers())
+	g.AddMissing(2)
+
+	var dot bytes.Buffer
+	if err := g.Write(&dot, FormatDOT); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`digraph citations {`,
+		`"b" [label="Paper \"B\"\nb"];`,
+		`"a" -> "c";`,
+		`"9999.00001" [label="Popular\n9999.00001", style=dashed];`,
+		`"b" -> "doi:10.1000/xyz";`,
+	} {
+		if !strings.Contains(dot.String(), want) {
+			t.Errorf("DOT output missing %q:\n%s", want, dot.String())
+		}
+	}
+
+	var gml bytes.Buffer
+	if err := g.Write(&gml, FormatGraphML); err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Graph struct {
+			Nodes []struct {
+				ID string `xml:"id,attr"`
+			} `xml:"node"`
+			Edges []struct {
+				Source string `xml:"source,attr"`
+			} `xml:"edge"`
+		} `xml:"graph"`
+	}
+	if err := xml.Unmarshal(gml.Bytes(), &doc); err != nil {
+		t.Fatalf("GraphML does not parse: %v", err)
+	}
+	if len(doc.Graph.Nodes) != 6 || len(doc.Graph.Edges) != 7 {
+		t.Errorf("GraphML has %d nodes and %d edges", len(doc.Graph.Nodes), len(doc.Graph.Edges))
+	}
+
+	var js bytes.Buffer
+	if err := g.Write(&js, FormatJSON); err != nil {
+		t.Fatal(err)
+	}
+	var back Graph
+	if err := json.Unmarshal(js.Bytes(), &back); err != nil || len(back.Nodes) != 6 {
+		t.Errorf("JSON round trip: %v, %d nodes", err, len(back.Nodes))
+	}
+
+	if err := g.Write(&js, "svg"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package graph
+
+import (
+	"bufio"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Export formats.
+const (
+	FormatDOT     = "dot"
+	FormatGraphML = "graphml"
+	FormatJSON    = "json"
+)
+
+// Formats lists the export formats Write accepts.
+var Formats = []string{FormatDOT, FormatGraphML, FormatJSON}
+
+// Write writes the graph to w in the given format.
+func (g *Graph) Write(w io.Writer, format string) error {
+	switch format {
+	case FormatDOT:
+		return g.WriteDOT(w)
+	case FormatGraphML:
+		return g.WriteGraphML(w)
+	case FormatJSON:
+		return g.WriteJSON(w)
+	}
+	return fmt.Errorf("unknown graph format %q (use %s)", format, strings.Join(Formats, ", "))
+}
+
+// WriteDOT writes the graph in Graphviz DOT. Papers are labelled with their
+// title and ID; missing works are drawn dashed.
+func (g *Graph) WriteDOT(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph citations {")
+	fmt.Fprintln(bw, "  rankdir=LR;")
+	fmt.Fprintln(bw, `  node [shape=box, fontsize=10];`)
+	for _, n := range g.Nodes {
+		label := n.ID
+		if n.Title != "" {
+			label = wrapLabel(n.Title, 40) + "\n" + n.ID
+		}
+		attrs := "label=" + dotQuote(label)
+		if n.Missing {
+			attrs += ", style=dashed"
+		}
+		fmt.Fprintf(bw, "  %s [%s];\n", dotQuote(n.ID), attrs)
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(bw, "  %s -> %s;\n", dotQuote(e.From), dotQuote(e.To))
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+// dotQuote quotes s as a DOT string; newlines become DOT's "\n" escape.
+func dotQuote(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+	return `"` + s + `"`
+}
+
+// wrapLabel breaks a long title into lines of about width characters.
+func wrapLabel(s string, width int) string {
+	var b strings.Builder
+	line := 0
+	for _, word := range strings.Fields(s) {
+		if line > 0 && line+1+len(word) > width {
+			b.WriteByte('\n')
+			line = 0
+		} else if line > 0 {
+			b.WriteByte(' ')
+			line++
+		}
+		b.WriteString(word)
+		line += len(word)
+	}
+	return b.String()
+}
+
+// WriteGraphML writes the graph as GraphML, with the paper metadata as node
+// attributes.
+func (g *Graph) WriteGraphML(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, `<?xml version="1.0" encoding="UTF-8"?>`)
+	fmt.Fprintln(bw, `<graphml xmlns="http://graphml.graphdrawing.org/xmlns">`)
+	keys := []struct{ id, typ string }{
+		{"title", "string"}, {"year", "string"}, {"categories", "string"}, {"tags", "string"},
+		{"cites", "int"}, {"cited_by", "int"}, {"has_references", "boolean"}, {"missing", "boolean"},
+	}
+	for _, k := range keys {
+		fmt.Fprintf(bw, `  <key id="%s" for="node" attr.name="%s" attr.type="%s"/>`+"\n", k.id, k.id, k.typ)
+	}
+	fmt.Fprintln(bw, `  <graph id="citations" edgedefault="directed">`)
+	for _, n := range g.Nodes {
+		fmt.Fprintf(bw, "    <node id=\"%s\">\n", xmlEscape(n.ID))
+		data := [][2]string{
+			{"title", n.Title},
+			{"year", n.Year},
+			{"categories", strings.Join(n.Categories, " ")},
+			{"tags", strings.Join(n.Tags, " ")},
+			{"cites", strconv.Itoa(n.Cites)},
+			{"cited_by", strconv.Itoa(n.CitedBy)},
+			{"has_references", strconv.FormatBool(n.HasReferences)},
+			{"missing", strconv.FormatBool(n.Missing)},
+		}
+		for _, d := range data {
+			if d[1] != "" {
+				fmt.Fprintf(bw, "      <data key=\"%s\">%s</data>\n", d[0], xmlEscape(d[1]))
+			}
+		}
+		fmt.Fprintln(bw, "    </node>")
+	}
+	for i, e := range g.Edges {
+		fmt.Fprintf(bw, "    <edge id=\"e%d\" source=\"%s\" target=\"%s\"/>\n", i, xmlEscape(e.From), xmlEscape(e.To))
+	}
+	fmt.Fprintln(bw, "  </graph>")
+	fmt.Fprintln(bw, "</graphml>")
+	return bw.Flush()
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// WriteJSON writes the graph as indented JSON.
+func (g *Graph) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(g)
+}
@@ -0,0 +1,532 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+// Package index maintains a SQLite index of the local paper library.
+//
+// The papers/<id>/meta.yaml files remain the source of truth; the index is a
+// derived cache that lets read commands answer queries without walking and
+// parsing every paper directory. It can always be rebuilt from disk.
+package index
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mtreilly/arc-arxiv/internal/arxiv"
+)
+
+// ErrNotFound is returned when a paper is not present in the index.
+var ErrNotFound = errors.New("paper not in index")
+
+var schema = []string{
+	`CREATE TABLE IF NOT EXISTS arxiv_papers (
+		arxiv_id         TEXT PRIMARY KEY,
+		dir              TEXT NOT NULL,
+		title            TEXT NOT NULL DEFAULT '',
+		abstract         TEXT NOT NULL DEFAULT '',
+		primary_category TEXT NOT NULL DEFAULT '',
+		published        TEXT NOT NULL DEFAULT '',
+		updated          TEXT NOT NULL DEFAULT '',
+		version          INTEGER NOT NULL DEFAULT 0,
+		fetched_at       TEXT NOT NULL DEFAULT '',
+		fetched_unix     INTEGER NOT NULL DEFAULT 0,
+		meta             TEXT NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS arxiv_authors (
+		arxiv_id    TEXT NOT NULL REFERENCES arxiv_papers(arxiv_id) ON DELETE CASCADE,
+		position    INTEGER NOT NULL,
+		name        TEXT NOT NULL,
+		affiliation TEXT NOT NULL DEFAULT '',
+		name_fold   TEXT NOT NULL DEFAULT '',
+		PRIMARY KEY (arxiv_id, position)
+	)`,
+	`CREATE INDEX IF NOT EXISTS arxiv_authors_name ON arxiv_authors(name)`,
+	`CREATE TABLE IF NOT EXISTS arxiv_categories (
+		arxiv_id      TEXT NOT NULL REFERENCES arxiv_papers(arxiv_id) ON DELETE CASCADE,
+		category      TEXT NOT NULL,
+		category_fold TEXT NOT NULL DEFAULT '',
+		PRIMARY KEY (arxiv_id, category)
+	)`,
+	`CREATE INDEX IF NOT EXISTS arxiv_categories_category ON arxiv_categories(category)`,
+	`CREATE TABLE IF NOT EXISTS arxiv_tags (
+		arxiv_id TEXT NOT NULL REFERENCES arxiv_papers(arxiv_id) ON DELETE CASCADE,
+		tag      TEXT NOT NULL,
+		tag_fold TEXT NOT NULL DEFAULT '',
+		PRIMARY KEY (arxiv_id, tag)
+	)`,
+	`CREATE INDEX IF NOT EXISTS arxiv_tags_tag ON arxiv_tags(tag)`,
+	`CREATE TABLE IF NOT EXISTS arxiv_collections (
+		arxiv_id        TEXT NOT NULL REFERENCES arxiv_papers(arxiv_id) ON DELETE CASCADE,
+		collection      TEXT NOT NULL,
+		collection_fold TEXT NOT NULL DEFAULT '',
+		PRIMARY KEY (arxiv_id, collection)
+	)`,
+	`CREATE INDEX IF NOT EXISTS arxiv_collections_collection ON arxiv_collections(collection)`,
+	`CREATE TABLE IF NOT EXISTS arxiv_reading (
+		arxiv_id TEXT PRIMARY KEY REFERENCES arxiv_papers(arxiv_id) ON DELETE CASCADE,
+		status   TEXT NOT NULL DEFAULT 'unread',
+		priority TEXT NOT NULL DEFAULT '',
+		rating   INTEGER NOT NULL DEFAULT 0
+	)`,
+}
+
+// foldColumns hold the case-folded names, categories, tags and collections
+// that filters compare against; SQLite's own lower() folds only ASCII.
+// Indexes written before they existed lack them and are emptied when
+// opened, so that they are rebuilt from disk.
+var foldColumns = []struct{ table, column string }{
+	{"arxiv_authors", "name_fold"},
+	{"arxiv_categories", "category_fold"},
+	{"arxiv_tags", "tag_fold"},
+	{"arxiv_collections", "collection_fold"},
+}
+
+// fold returns s in the form filters compare case-insensitively.
+func fold(s string) string {
+	return strings.ToLower(s)
+}
+
+// DefaultStatus is the reading status of a paper that has none recorded.
+const DefaultStatus = "unread"
+
+// childTables hold per-paper rows keyed on arxiv_id, deleted before the
+// paper itself.
+var childTables = []string{"arxiv_authors", "arxiv_categories", "arxiv_tags", "arxiv_collections", "arxiv_reading"}
+
+// Entry is a paper as stored on disk: its metadata and the directory it lives in.
+type Entry struct {
+	Meta *arxiv.ArxivMeta
+	Dir  string
+}
+
+// Index is a SQLite-backed index of downloaded papers.
+type Index struct {
+	db *sql.DB
+}
+
+// Open prepares the index tables in db and returns an Index using them.
+func Open(ctx context.Context, db *sql.DB) (*Index, error) {
+	if db == nil {
+		return nil, fmt.Errorf("open index: no database")
+	}
+	for _, stmt := range schema {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			return nil, fmt.Errorf("create index schema: %w", err)
+		}
+	}
+	ix := &Index{db: db}
+	if err := ix.addFoldColumns(ctx); err != nil {
+		return nil, err
+	}
+	return ix, nil
+}
+
+// addFoldColumns adds the fold columns an older index lacks and, if any
+// were missing, empties the index.
+func (ix *Index) addFoldColumns(ctx context.Context) error {
+	added := false
+	for _, c := range foldColumns {
+		if _, err := ix.db.ExecContext(ctx, "SELECT "+c.column+" FROM "+c.table+" LIMIT 0"); err == nil {
+			continue
+		}
+		if _, err := ix.db.ExecContext(ctx, "ALTER TABLE "+c.table+" ADD COLUMN "+c.column+" TEXT NOT NULL DEFAULT ''"); err != nil {
+			return fmt.Errorf("upgrade index schema: %w", err)
+		}
+		added = true
+	}
+	if !added {
+		return nil
+	}
+	return ix.Replace(ctx, nil)
+}
+
+// Count returns the number of indexed papers.
+func (ix *Index) Count(ctx context.Context) (int, error) {
+	var n int
+	if err := ix.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM arxiv_papers`).Scan(&n); err != nil {
+		return 0, fmt.Errorf("count papers: %w", err)
+	}
+	return n, nil
+}
+
+// Upsert inserts or replaces a single paper.
+func (ix *Index) Upsert(ctx context.Context, e Entry) error {
+	tx, err := ix.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := upsert(ctx, tx, e); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Delete removes a paper from the index. Deleting an unknown paper is not an error.
+func (ix *Index) Delete(ctx context.Context, id string) error {
+	tx, err := ix.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := deletePaper(ctx, tx, id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Replace discards the current index contents and indexes entries instead.
+func (ix *Index) Replace(ctx context.Context, entries []Entry) error {
+	tx, err := ix.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, table := range append(childTables, "arxiv_papers") {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM "+table); err != nil {
+			return fmt.Errorf("clear %s: %w", table, err)
+		}
+	}
+	for _, e := range entries {
+		if err := upsert(ctx, tx, e); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Get returns the indexed entry for id.
+func (ix *Index) Get(ctx context.Context, id string) (*Entry, error) {
+	var dir, raw string
+	err := ix.db.QueryRowContext(ctx, `SELECT dir, meta FROM arxiv_papers WHERE arxiv_id = ?`, id).Scan(&dir, &raw)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get %s: %w", id, err)
+	}
+	meta, err := decodeMeta(raw)
+	if err != nil {
+		return nil, fmt.Errorf("decode %s: %w", id, err)
+	}
+	return &Entry{Meta: meta, Dir: dir}, nil
+}
+
+// Filter restricts which papers List and Stats consider.
+type Filter struct {
+	// Category matches a category exactly or by prefix, case-insensitively.
+	// Like Author, Tag and Collection, it is compared after folding case
+	// with Unicode rules.
+	Category string
+	// Author matches any author name containing the substring, case-insensitively.
+	Author string
+	// Since keeps only papers fetched at or after this time.
+	Since time.Time
+	// Tag keeps only papers carrying this tag, case-insensitively.
+	Tag string
+	// Collection keeps only papers in this collection, case-insensitively.
+	Collection string
+	// Status keeps only papers with this reading status. Papers with no
+	// recorded status count as DefaultStatus.
+	Status string
+	// Priority keeps only papers with this priority.
+	Priority string
+	// MinRating keeps only papers rated at least this highly.
+	MinRating int
+}
+
+func (f Filter) where() (string, []any) {
+	var clauses []string
+	var args []any
+
+	if f.Category != "" {
+		clauses = append(clauses, `EXISTS (SELECT 1 FROM arxiv_categories c
+			WHERE c.arxiv_id = p.arxiv_id
+			AND (c.category_fold = ? OR substr(c.category_fold, 1, length(?)) = ?))`)
+		args = append(args, fold(f.Category), fold(f.Category), fold(f.Category))
+	}
+	if f.Author != "" {
+		clauses = append(clauses, `EXISTS (SELECT 1 FROM arxiv_authors a
+			WHERE a.arxiv_id = p.arxiv_id AND instr(a.name_fold, ?) > 0)`)
+		args = append(args, fold(f.Author))
+	}
+	if f.Tag != "" {
+		clauses = append(clauses, `EXISTS (SELECT 1 FROM arxiv_tags t
+			WHERE t.arxiv_id = p.arxiv_id AND t.tag_fold = ?)`)
+		args = append(args, fold(f.Tag))
+	}
+	if f.Collection != "" {
+		clauses = append(clauses, `EXISTS (SELECT 1 FROM arxiv_collections k
+			WHERE k.arxiv_id = p.arxiv_id AND k.collection_fold = ?)`)
+		args = append(args, fold(f.Collection))
+	}
+	if f.Status != "" {
+		clauses = append(clauses, `COALESCE((SELECT r.status FROM arxiv_reading r WHERE r.arxiv_id = p.arxiv_id), ?) = lower(?)`)
+		args = append(args, DefaultStatus, f.Status)
+	}
+	if f.Priority != "" {
+		clauses = append(clauses, `EXISTS (SELECT 1 FROM arxiv_reading r
+			WHERE r.arxiv_id = p.arxiv_id AND r.priority = lower(?))`)
+		args = append(args, f.Priority)
+	}
+	if f.MinRating > 0 {
+		clauses = append(clauses, `EXISTS (SELECT 1 FROM arxiv_reading r
+			WHERE r.arxiv_id = p.arxiv_id AND r.rating >= ?)`)
+		args = append(args, f.MinRating)
+	}
+	if !f.Since.IsZero() {
+		clauses = append(clauses, `p.fetched_unix >= ?`)
+		args = append(args, f.Since.Unix())
+	}
+
+	if len(clauses) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(clauses, " AND "), args
+}
+
+// List returns all papers matching f, ordered by arXiv ID.
+func (ix *Index) List(ctx context.Context, f Filter) ([]Entry, error) {
+	where, args := f.where()
+	rows, err := ix.db.QueryContext(ctx, `SELECT p.dir, p.meta FROM arxiv_papers p`+where+` ORDER BY p.arxiv_id`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list papers: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var entries []Entry
+	for rows.Next() {
+		var dir, raw string
+		if err := rows.Scan(&dir, &raw); err != nil {
+			return nil, fmt.Errorf("scan paper: %w", err)
+		}
+		meta, err := decodeMeta(raw)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, Entry{Meta: meta, Dir: dir})
+	}
+	return entries, rows.Err()
+}
+
+// Stats holds aggregate counts over the indexed papers.
+type Stats struct {
+	TotalPapers   int
+	Categories    map[string]int
+	Authors       map[string]int
+	Years         map[int]int
+	FetchedMonths map[string]int
+	Tags          map[string]int
+	Collections   map[string]int
+	// Statuses counts papers per reading status, including DefaultStatus.
+	Statuses map[string]int
+	// Priorities counts papers that have a priority set.
+	Priorities map[string]int
+	// Ratings counts rated papers per rating.
+	Ratings map[int]int
+}
+
+// Stats aggregates counts over all papers matching f.
+func (ix *Index) Stats(ctx context.Context, f Filter) (*Stats, error) {
+	where, args := f.where()
+	stats := &Stats{
+		Categories:    make(map[string]int),
+		Authors:       make(map[string]int),
+		Years:         make(map[int]int),
+		FetchedMonths: make(map[string]int),
+		Tags:          make(map[string]int),
+		Collections:   make(map[string]int),
+		Statuses:      make(map[string]int),
+		Priorities:    make(map[string]int),
+		Ratings:       make(map[int]int),
+	}
+
+	if err := ix.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM arxiv_papers p`+where, args...).Scan(&stats.TotalPapers); err != nil {
+		return nil, fmt.Errorf("count papers: %w", err)
+	}
+
+	if err := ix.countInto(ctx, stats.Categories,
+		`SELECT c.category, COUNT(*) FROM arxiv_categories c JOIN arxiv_papers p ON p.arxiv_id = c.arxiv_id`+where+` GROUP BY c.category`,
+		args); err != nil {
+		return nil, err
+	}
+	if err := ix.countInto(ctx, stats.Authors,
+		`SELECT a.name, COUNT(*) FROM arxiv_authors a JOIN arxiv_papers p ON p.arxiv_id = a.arxiv_id`+where+` GROUP BY a.name`,
+		args); err != nil {
+		return nil, err
+	}
+	if err := ix.countInto(ctx, stats.Tags,
+		`SELECT t.tag, COUNT(*) FROM arxiv_tags t JOIN arxiv_papers p ON p.arxiv_id = t.arxiv_id`+where+` GROUP BY t.tag`,
+		args); err != nil {
+		return nil, err
+	}
+	if err := ix.countInto(ctx, stats.Collections,
+		`SELECT k.collection, COUNT(*) FROM arxiv_collections k JOIN arxiv_papers p ON p.arxiv_id = k.arxiv_id`+where+` GROUP BY k.collection`,
+		args); err != nil {
+		return nil, err
+	}
+	if err := ix.countInto(ctx, stats.FetchedMonths,
+		`SELECT substr(p.fetched_at, 1, 7), COUNT(*) FROM arxiv_papers p`+where+andNonEmpty(where, "p.fetched_at")+` GROUP BY substr(p.fetched_at, 1, 7)`,
+		args); err != nil {
+		return nil, err
+	}
+
+	if err := ix.countInto(ctx, stats.Statuses,
+		`SELECT COALESCE(r.status, '`+DefaultStatus+`'), COUNT(*) FROM arxiv_papers p LEFT JOIN arxiv_reading r ON r.arxiv_id = p.arxiv_id`+where+` GROUP BY COALESCE(r.status, '`+DefaultStatus+`')`,
+		args); err != nil {
+		return nil, err
+	}
+	if err := ix.countInto(ctx, stats.Priorities,
+		`SELECT r.priority, COUNT(*) FROM arxiv_reading r JOIN arxiv_papers p ON p.arxiv_id = r.arxiv_id`+where+andNonEmpty(where, "r.priority")+` GROUP BY r.priority`,
+		args); err != nil {
+		return nil, err
+	}
+	ratings := make(map[string]int)
+	if err := ix.countInto(ctx, ratings,
+		`SELECT CAST(r.rating AS TEXT), COUNT(*) FROM arxiv_reading r JOIN arxiv_papers p ON p.arxiv_id = r.arxiv_id`+where+andPositive(where, "r.rating")+` GROUP BY r.rating`,
+		args); err != nil {
+		return nil, err
+	}
+	for r, n := range ratings {
+		var rating int
+		if _, err := fmt.Sscanf(r, "%d", &rating); err == nil {
+			stats.Ratings[rating] = n
+		}
+	}
+
+	years := make(map[string]int)
+	if err := ix.countInto(ctx, years,
+		`SELECT substr(p.published, 1, 4), COUNT(*) FROM arxiv_papers p`+where+andNonEmpty(where, "p.published")+` GROUP BY substr(p.published, 1, 4)`,
+		args); err != nil {
+		return nil, err
+	}
+	for y, n := range years {
+		var year int
+		if _, err := fmt.Sscanf(y, "%d", &year); err == nil && year > 0 {
+			stats.Years[year] += n
+		}
+	}
+
+	return stats, nil
+}
+
+func andNonEmpty(where, column string) string {
+	if where == "" {
+		return " WHERE " + column + " != ''"
+	}
+	return " AND " + column + " != ''"
+}
+
+func andPositive(where, column string) string {
+	if where == "" {
+		return " WHERE " + column + " > 0"
+	}
+	return " AND " + column + " > 0"
+}
+
+func (ix *Index) countInto(ctx context.Context, m map[string]int, query string, args []any) error {
+	rows, err := ix.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("aggregate: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var key string
+		var n int
+		if err := rows.Scan(&key, &n); err != nil {
+			return fmt.Errorf("aggregate: %w", err)
+		}
+		m[key] = n
+	}
+	return rows.Err()
+}
+
+func upsert(ctx context.Context, tx *sql.Tx, e Entry) error {
+	meta := e.Meta
+	if meta == nil || meta.ArxivID == "" {
+		return fmt.Errorf("index entry has no arxiv id")
+	}
+	raw, err := json.Marshal(meta)
+	if err != nil {
+		return fmt.Errorf("encode %s: %w", meta.ArxivID, err)
+	}
+
+	if err := deletePaper(ctx, tx, meta.ArxivID); err != nil {
+		return err
+	}
+
+	var fetchedUnix int64
+	if t, err := time.Parse(time.RFC3339, meta.FetchedAt); err == nil {
+		fetchedUnix = t.Unix()
+	}
+
+	if _, err := tx.ExecContext(ctx, `INSERT INTO arxiv_papers
+		(arxiv_id, dir, title, abstract, primary_category, published, updated, version, fetched_at, fetched_unix, meta)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		meta.ArxivID, e.Dir, meta.Title, meta.Abstract, meta.PrimaryCategory,
+		meta.Published, meta.Updated, meta.Version, meta.FetchedAt, fetchedUnix, string(raw),
+	); err != nil {
+		return fmt.Errorf("index %s: %w", meta.ArxivID, err)
+	}
+
+	for i, a := range meta.Authors {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO arxiv_authors (arxiv_id, position, name, affiliation, name_fold) VALUES (?, ?, ?, ?, ?)`,
+			meta.ArxivID, i, a.Name, a.Affiliation, fold(a.Name)); err != nil {
+			return fmt.Errorf("index authors for %s: %w", meta.ArxivID, err)
+		}
+	}
+	for _, c := range meta.Categories {
+		if _, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO arxiv_categories (arxiv_id, category, category_fold) VALUES (?, ?, ?)`,
+			meta.ArxivID, c, fold(c)); err != nil {
+			return fmt.Errorf("index categories for %s: %w", meta.ArxivID, err)
+		}
+	}
+	for _, tag := range meta.Tags {
+		if _, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO arxiv_tags (arxiv_id, tag, tag_fold) VALUES (?, ?, ?)`,
+			meta.ArxivID, tag, fold(tag)); err != nil {
+			return fmt.Errorf("index tags for %s: %w", meta.ArxivID, err)
+		}
+	}
+	for _, c := range meta.Collections {
+		if _, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO arxiv_collections (arxiv_id, collection, collection_fold) VALUES (?, ?, ?)`,
+			meta.ArxivID, c, fold(c)); err != nil {
+			return fmt.Errorf("index collections for %s: %w", meta.ArxivID, err)
+		}
+	}
+	status := meta.Status
+	if status == "" {
+		status = DefaultStatus
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO arxiv_reading (arxiv_id, status, priority, rating) VALUES (?, ?, ?, ?)`,
+		meta.ArxivID, status, meta.Priority, meta.Rating); err != nil {
+		return fmt.Errorf("index reading state for %s: %w", meta.ArxivID, err)
+	}
+	return nil
+}
+
+func deletePaper(ctx context.Context, tx *sql.Tx, id string) error {
+	// Delete children explicitly: foreign key enforcement is off by default in SQLite.
+	for _, table := range append(childTables, "arxiv_papers") {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM "+table+" WHERE arxiv_id = ?", id); err != nil {
+			return fmt.Errorf("delete %s from %s: %w", id, table, err)
+		}
+	}
+	return nil
+}
+
+func decodeMeta(raw string) (*arxiv.ArxivMeta, error) {
+	var meta arxiv.ArxivMeta
+	if err := json.Unmarshal([]byte(raw), &meta); err != nil {
+		return nil, err
+	}
+	return &meta, nil
+}
@@ -0,0 +1,305 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package index
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mtreilly/arc-arxiv/internal/arxiv"
+	"github.com/yourorg/arc-sdk/db"
+)
+
+func openTestIndex(t *testing.T) *Index {
+	t.Helper()
+	database, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("db.Open: %v", err)
+	}
+	t.Cleanup(func() { _ = database.Close() })
+
+	ix, err := Open(context.Background(), database)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	return ix
+}
+
+func testEntries() []Entry {
+	return []Entry{
+		{Dir: "/lib/papers/2304.00067", Meta: &arxiv.ArxivMeta{
+			ArxivID:     "2304.00067",
+			Title:       "Attention Variants",
+			Authors:     []arxiv.Author{{Name: "Alice Smith"}, {Name: "Bob Jones"}},
+			Categories:  []string{"cs.LG", "cs.AI"},
+			Published:   "2023-04-01T00:00:00Z",
+			FetchedAt:   "2024-01-15T10:00:00Z",
+			Tags:        []string{"attention", "to-read"},
+			Collections: []string{"Thesis"},
+			Status:      "read",
+			Priority:    "high",
+			Rating:      4,
+		}},
+		{Dir: "/lib/papers/2101.00001", Meta: &arxiv.ArxivMeta{
+			ArxivID:    "2101.00001",
+			Title:      "String Dualities",
+			Authors:    []arxiv.Author{{Name: "Carol White"}},
+			Categories: []string{"hep-th"},
+			Published:  "2021-01-01T00:00:00Z",
+			FetchedAt:  "2023-06-01T10:00:00Z",
+			Tags:       []string{"to-read"},
+		}},
+	}
+}
+
+func TestReplaceAndList(t *testing.T) {
+	ctx := context.Background()
+	ix := openTestIndex(t)
+
+	if err := ix.Replace(ctx, testEntries()); err != nil {
+		t.Fatalf("Replace: %v", err)
+	}
+
+	n, err := ix.Count(ctx)
+	if err != nil {
+		t.Fatalf("Count: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("Count = %d, want 2", n)
+	}
+
+	entries, err := ix.List(ctx, Filter{})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("len(entries) = %d, want 2", len(entries))
+	}
+	if entries[0].Meta.ArxivID != "2101.00001" {
+		t.Errorf("entries[0] = %q, want ordering by arxiv id", entries[0].Meta.ArxivID)
+	}
+	if entries[1].Dir != "/lib/papers/2304.00067" {
+		t.Errorf("Dir = %q", entries[1].Dir)
+	}
+	if len(entries[1].Meta.Authors) != 2 {
+		t.Errorf("Authors not round-tripped: %+v", entries[1].Meta.Authors)
+	}
+}
+
+func TestListFilters(t *testing.T) {
+	ctx := context.Background()
+	ix := openTestIndex(t)
+	if err := ix.Replace(ctx, testEntries()); err != nil {
+		t.Fatalf("Replace: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []string
+	}{
+		{"category exact", Filter{Category: "hep-th"}, []string{"2101.00001"}},
+		{"category prefix", Filter{Category: "cs"}, []string{"2304.00067"}},
+		{"category case-insensitive", Filter{Category: "CS.lg"}, []string{"2304.00067"}},
+		{"author substring", Filter{Author: "jones"}, []string{"2304.00067"}},
+		{"since", Filter{Since: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}, []string{"2304.00067"}},
+		{"tag", Filter{Tag: "attention"}, []string{"2304.00067"}},
+		{"tag shared", Filter{Tag: "TO-READ"}, []string{"2101.00001", "2304.00067"}},
+		{"collection case-insensitive", Filter{Collection: "thesis"}, []string{"2304.00067"}},
+		{"tag and collection", Filter{Tag: "to-read", Collection: "Thesis"}, []string{"2304.00067"}},
+		{"status", Filter{Status: "read"}, []string{"2304.00067"}},
+		{"default status", Filter{Status: "unread"}, []string{"2101.00001"}},
+		{"priority", Filter{Priority: "high"}, []string{"2304.00067"}},
+		{"min rating", Filter{MinRating: 4}, []string{"2304.00067"}},
+		{"min rating too high", Filter{MinRating: 5}, nil},
+		{"no match", Filter{Author: "nobody"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := ix.List(ctx, tt.filter)
+			if err != nil {
+				t.Fatalf("List: %v", err)
+			}
+			var got []string
+			for _, e := range entries {
+				got = append(got, e.Meta.ArxivID)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("got %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestListFiltersFoldUnicode(t *testing.T) {
+	ctx := context.Background()
+	ix := openTestIndex(t)
+	if err := ix.Replace(ctx, []Entry{{Dir: "/lib/papers/2401.00001", Meta: &arxiv.ArxivMeta{
+		ArxivID:     "2401.00001",
+		Authors:     []arxiv.Author{{Name: "Åsa Ørsted"}, {Name: "Çağrı Şahin"}},
+		Tags:        []string{"Übersicht"},
+		Collections: []string{"Ἀρχή"},
+	}}}); err != nil {
+		t.Fatalf("Replace: %v", err)
+	}
+
+	for _, f := range []Filter{
+		{Author: "ørsted"},
+		{Author: "ÅSA"},
+		{Author: "ŞAHIN"},
+		{Tag: "übersicht"},
+		{Collection: "ἀΡΧΉ"},
+	} {
+		entries, err := ix.List(ctx, f)
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		if len(entries) != 1 {
+			t.Errorf("List(%+v) found %d papers, want 1", f, len(entries))
+		}
+	}
+}
+
+func TestOpenUpgradesOldSchema(t *testing.T) {
+	ctx := context.Background()
+	database, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("db.Open: %v", err)
+	}
+	t.Cleanup(func() { _ = database.Close() })
+
+	// An index written before the fold columns, holding one paper.
+	for _, stmt := range []string{
+		schema[0],
+		`CREATE TABLE arxiv_authors (arxiv_id TEXT NOT NULL, position INTEGER NOT NULL, name TEXT NOT NULL,
+			affiliation TEXT NOT NULL DEFAULT '', PRIMARY KEY (arxiv_id, position))`,
+		`INSERT INTO arxiv_papers (arxiv_id, dir, meta) VALUES ('2304.00067', '/lib', '{}')`,
+		`INSERT INTO arxiv_authors (arxiv_id, position, name) VALUES ('2304.00067', 0, 'Ørsted')`,
+	} {
+		if _, err := database.ExecContext(ctx, stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ix, err := Open(ctx, database)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if n, err := ix.Count(ctx); err != nil || n != 0 {
+		t.Errorf("Count = %d, %v; want the old index emptied for a rebuild", n, err)
+	}
+	entries := []Entry{{Dir: "/lib", Meta: &arxiv.ArxivMeta{ArxivID: "2304.00067", Authors: []arxiv.Author{{Name: "Ørsted"}}}}}
+	if err := ix.Replace(ctx, entries); err != nil {
+		t.Fatalf("Replace: %v", err)
+	}
+	if list, err := ix.List(ctx, Filter{Author: "ørsted"}); err != nil || len(list) != 1 {
+		t.Errorf("List after upgrade = %d papers, %v", len(list), err)
+	}
+}
+
+func TestUpsertAndDelete(t *testing.T) {
+	ctx := context.Background()
+	ix := openTestIndex(t)
+	entries := testEntries()
+
+	if err := ix.Upsert(ctx, entries[0]); err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+
+	updated := *entries[0].Meta
+	updated.Title = "Renamed"
+	updated.Authors = []arxiv.Author{{Name: "Alice Smith"}}
+	if err := ix.Upsert(ctx, Entry{Meta: &updated, Dir: entries[0].Dir}); err != nil {
+		t.Fatalf("Upsert again: %v", err)
+	}
+
+	got, err := ix.Get(ctx, "2304.00067")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Meta.Title != "Renamed" {
+		t.Errorf("Title = %q, want %q", got.Meta.Title, "Renamed")
+	}
+
+	if list, _ := ix.List(ctx, Filter{Author: "Bob"}); len(list) != 0 {
+		t.Errorf("stale author rows survived upsert: %d matches", len(list))
+	}
+
+	if err := ix.Delete(ctx, "2304.00067"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := ix.Get(ctx, "2304.00067"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after delete: err = %v, want ErrNotFound", err)
+	}
+	if err := ix.Delete(ctx, "2304.00067"); err != nil {
+		t.Errorf("Delete of missing paper: %v", err)
+	}
+}
+
+func TestUpsertRequiresID(t *testing.T) {
+	ix := openTestIndex(t)
+	if err := ix.Upsert(context.Background(), Entry{Meta: &arxiv.ArxivMeta{}}); err == nil {
+		t.Error("expected error for entry without arxiv id")
+	}
+}
+
+func TestStats(t *testing.T) {
+	ctx := context.Background()
+	ix := openTestIndex(t)
+	if err := ix.Replace(ctx, testEntries()); err != nil {
+		t.Fatalf("Replace: %v", err)
+	}
+
+	stats, err := ix.Stats(ctx, Filter{})
+	if err != nil {
+		t.Fatalf("Stats: %v", err)
+	}
+	if stats.TotalPapers != 2 {
+		t.Errorf("TotalPapers = %d, want 2", stats.TotalPapers)
+	}
+	if stats.Categories["cs.LG"] != 1 || stats.Categories["hep-th"] != 1 {
+		t.Errorf("Categories = %v", stats.Categories)
+	}
+	if stats.Authors["Carol White"] != 1 {
+		t.Errorf("Authors = %v", stats.Authors)
+	}
+	if stats.Years[2023] != 1 || stats.Years[2021] != 1 {
+		t.Errorf("Years = %v", stats.Years)
+	}
+	if stats.FetchedMonths["2024-01"] != 1 {
+		t.Errorf("FetchedMonths = %v", stats.FetchedMonths)
+	}
+	if stats.Tags["to-read"] != 2 || stats.Tags["attention"] != 1 {
+		t.Errorf("Tags = %v", stats.Tags)
+	}
+	if stats.Collections["Thesis"] != 1 {
+		t.Errorf("Collections = %v", stats.Collections)
+	}
+	if stats.Statuses["read"] != 1 || stats.Statuses["unread"] != 1 {
+		t.Errorf("Statuses = %v", stats.Statuses)
+	}
+	if stats.Priorities["high"] != 1 || len(stats.Priorities) != 1 {
+		t.Errorf("Priorities = %v", stats.Priorities)
+	}
+	if stats.Ratings[4] != 1 || len(stats.Ratings) != 1 {
+		t.Errorf("Ratings = %v", stats.Ratings)
+	}
+
+	filtered, err := ix.Stats(ctx, Filter{Category: "hep-th"})
+	if err != nil {
+		t.Fatalf("Stats filtered: %v", err)
+	}
+	if filtered.TotalPapers != 1 || filtered.Authors["Alice Smith"] != 0 {
+		t.Errorf("filtered stats = %+v", filtered)
+	}
+}
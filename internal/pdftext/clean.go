@@ -0,0 +1,251 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package pdftext
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Page is the cleaned text of one page.
+type Page struct {
+	Number int    `json:"page"`
+	Text   string `json:"text"`
+}
+
+// hyphens are the characters that can end a line in a broken word: ASCII
+// hyphen-minus, soft hyphen and Unicode hyphen.
+const hyphens = "-\u00ad\u2010"
+
+// removed marks a line dropped during cleaning, as opposed to a blank line
+// that separates paragraphs.
+const removed = "\x00"
+
+// marginLines is how many lines at the top and bottom of each page are
+// considered as running headers or footers.
+const marginLines = 2
+
+var ligatures = strings.NewReplacer(
+	"ﬀ", "ff", "ﬁ", "fi", "ﬂ", "fl", "ﬃ", "ffi",
+	"ﬄ", "ffl", "ﬅ", "st", "ﬆ", "st",
+	"\u00a0", " ", "\r\n", "\n", "\r", "\n",
+)
+
+// Clean turns the raw text of each page, as produced by Extract or by
+// pdftotext, into readable pages: ligatures are expanded, running headers
+// and footers (including page numbers) are dropped, and words hyphenated
+// across lines are joined.
+func Clean(raw []string) []Page {
+	pages := make([][]string, len(raw))
+	for i, text := range raw {
+		text = ligatures.Replace(text)
+		var lines []string
+		for _, l := range strings.Split(text, "\n") {
+			lines = append(lines, strings.Join(strings.Fields(l), " "))
+		}
+		pages[i] = lines
+	}
+
+	dropRunningLines(pages)
+	dehyphenate(pages)
+
+	out := make([]Page, len(pages))
+	for i, lines := range pages {
+		out[i] = Page{Number: i + 1, Text: joinLines(lines)}
+	}
+	return out
+}
+
+// marginKey normalizes a header or footer line so that the same line on
+// different pages compares equal: case and digits (page numbers) are
+// ignored.
+func marginKey(l string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return '#'
+		}
+		return unicode.ToLower(r)
+	}, l)
+}
+
+// isPageNumber reports whether a line is only a page number, such as "3",
+// "- 3 -", "Page 3" or "3 of 12".
+func isPageNumber(l string) bool {
+	l = strings.ToLower(strings.Trim(l, "-–— "))
+	l = strings.TrimPrefix(l, "page ")
+	if before, after, ok := strings.Cut(l, " of "); ok {
+		return isDigits(before) && isDigits(after)
+	}
+	return isDigits(l)
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// marginIndexes returns the indexes of the first and last few non-blank
+// lines of a page.
+func marginIndexes(lines []string) (top, bottom []int) {
+	for i := 0; i < len(lines) && len(top) < marginLines; i++ {
+		if lines[i] != "" && lines[i] != removed {
+			top = append(top, i)
+		}
+	}
+	for i := len(lines) - 1; i >= 0 && len(bottom) < marginLines; i-- {
+		if lines[i] != "" && lines[i] != removed {
+			bottom = append(bottom, i)
+		}
+	}
+	return top, bottom
+}
+
+// dropRunningLines removes lines that repeat at the top or bottom of many
+// pages, and bare page numbers there. Headers often alternate between odd
+// and even pages, so a line counts as running if it appears on at least
+// two pages and on 40% of them.
+func dropRunningLines(pages [][]string) {
+	counts := map[string]int{}
+	for _, lines := range pages {
+		top, bottom := marginIndexes(lines)
+		seen := map[string]bool{}
+		for _, i := range append(top, bottom...) {
+			key := marginKey(lines[i])
+			if !seen[key] {
+				seen[key] = true
+				counts[key]++
+			}
+		}
+	}
+
+	running := func(l string) bool {
+		if isPageNumber(l) {
+			return true
+		}
+		n := counts[marginKey(l)]
+		return len(pages) >= 3 && n >= 2 && n*10 >= len(pages)*4
+	}
+	for _, lines := range pages {
+		top, bottom := marginIndexes(lines)
+		for _, i := range append(top, bottom...) {
+			if running(lines[i]) {
+				lines[i] = removed
+			}
+		}
+	}
+}
+
+// dehyphenate joins words split with a hyphen at the end of a line, within
+// a paragraph and across page breaks. The hyphen is kept for compounds that
+// already contain one ("state-of-the-" + "art").
+func dehyphenate(pages [][]string) {
+	// next finds the line that continues the one at (pg, i): the next line
+	// of the same paragraph, or the first line of the following page.
+	next := func(pg, i int) (int, int, bool) {
+		for p, j, samePage := pg, i+1, true; p < len(pages); p, j, samePage = p+1, 0, false {
+			for ; j < len(pages[p]); j++ {
+				switch pages[p][j] {
+				case removed:
+				case "":
+					// A blank line ends the paragraph, unless it only
+					// trails the page or leads the next one.
+					if samePage && hasText(pages[p][j+1:]) {
+						return 0, 0, false
+					}
+				default:
+					return p, j, true
+				}
+			}
+		}
+		return 0, 0, false
+	}
+
+	for pg := range pages {
+		for i := range pages[pg] {
+			cur := pages[pg][i]
+			word, ok := hyphenatedTail(cur)
+			if !ok {
+				continue
+			}
+			npg, ni, ok := next(pg, i)
+			if !ok {
+				continue
+			}
+			following := pages[npg][ni]
+			first, _ := utf8.DecodeRuneInString(following)
+			if !unicode.IsLower(first) {
+				continue
+			}
+			head, rest, _ := strings.Cut(following, " ")
+			joined := strings.TrimRight(cur, hyphens)
+			if strings.ContainsAny(word, hyphens) {
+				joined += "-"
+			}
+			if rest == "" {
+				rest = removed
+			}
+			pages[pg][i] = joined + head
+			pages[npg][ni] = rest
+		}
+	}
+}
+
+func hasText(lines []string) bool {
+	for _, l := range lines {
+		if l != "" && l != removed {
+			return true
+		}
+	}
+	return false
+}
+
+// hyphenatedTail reports whether a line ends in a word broken with a
+// single hyphen and returns that word without the final hyphen.
+func hyphenatedTail(l string) (string, bool) {
+	trimmed := strings.TrimRight(l, hyphens)
+	if trimmed == l || utf8.RuneCountInString(l)-utf8.RuneCountInString(trimmed) != 1 {
+		return "", false
+	}
+	word := trimmed
+	if i := strings.LastIndexByte(trimmed, ' '); i >= 0 {
+		word = trimmed[i+1:]
+	}
+	last, _ := utf8.DecodeLastRuneInString(word)
+	if !unicode.IsLetter(last) {
+		return "", false
+	}
+	return word, true
+}
+
+// joinLines rebuilds page text, collapsing runs of blank lines.
+func joinLines(lines []string) string {
+	var b strings.Builder
+	blank := false
+	for _, l := range lines {
+		if l == removed {
+			continue
+		}
+		if l == "" {
+			blank = b.Len() > 0
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteByte('\n')
+			if blank {
+				b.WriteByte('\n')
+			}
+		}
+		b.WriteString(l)
+		blank = false
+	}
+	return b.String()
+}
@@ -0,0 +1,270 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package pdftext
+
+import (
+	"bytes"
+	"math"
+)
+
+// matrix is a PDF transformation matrix [a b c d e f].
+type matrix [6]float64
+
+var identity = matrix{1, 0, 0, 1, 0, 0}
+
+// mul returns m × n.
+func (m matrix) mul(n matrix) matrix {
+	return matrix{
+		m[0]*n[0] + m[1]*n[2],
+		m[0]*n[1] + m[1]*n[3],
+		m[2]*n[0] + m[3]*n[2],
+		m[2]*n[1] + m[3]*n[3],
+		m[4]*n[0] + m[5]*n[2] + n[4],
+		m[4]*n[1] + m[5]*n[3] + n[5],
+	}
+}
+
+func (m matrix) apply(x, y float64) (float64, float64) {
+	return x*m[0] + y*m[2] + m[4], x*m[1] + y*m[3] + m[5]
+}
+
+func translate(x, y float64) matrix {
+	return matrix{1, 0, 0, 1, x, y}
+}
+
+// placed is a glyph positioned in page space.
+type placed struct {
+	text      string
+	x, y      float64 // start of the glyph's baseline
+	endX      float64 // end of its advance
+	size      float64 // effective font size
+	estimated bool
+}
+
+type gstate struct {
+	ctm matrix
+	// Text state parameters persist across BT/ET and are saved by q/Q.
+	font      *font
+	fontSize  float64
+	charSpace float64
+	wordSpace float64
+	hscale    float64
+	leading   float64
+	rise      float64
+}
+
+// interpreter runs page content streams and collects positioned glyphs.
+type interpreter struct {
+	doc    *document
+	glyphs []placed
+}
+
+const maxFormDepth = 8
+
+func (in *interpreter) run(content []byte, resources dict, gs gstate, depth int) {
+	fontCache := map[name]*font{}
+	fontDicts := in.doc.dict(resources["Font"])
+	xobjects := in.doc.dict(resources["XObject"])
+
+	lookupFont := func(n name) *font {
+		if f, ok := fontCache[n]; ok {
+			return f
+		}
+		var f *font
+		if d := in.doc.dict(fontDicts[n]); d != nil {
+			f = in.doc.loadFont(d)
+		}
+		fontCache[n] = f
+		return f
+	}
+
+	var stack []gstate
+	var tm, tlm matrix
+	l := &lexer{data: content}
+	var operands []any
+
+	num := func(i int) float64 {
+		if i >= len(operands) {
+			return 0
+		}
+		switch v := operands[i].(type) {
+		case int:
+			return float64(v)
+		case float64:
+			return v
+		}
+		return 0
+	}
+	td := func(x, y float64) {
+		tlm = translate(x, y).mul(tlm)
+		tm = tlm
+	}
+	show := func(s pdfString) {
+		if gs.font == nil {
+			return
+		}
+		for _, g := range gs.font.decode(s) {
+			trm := tm.mul(gs.ctm)
+			x, y := trm.apply(0, gs.rise)
+			size := gs.fontSize * math.Hypot(trm[2], trm[3])
+			adv := (g.width/1000*gs.fontSize + gs.charSpace) * gs.hscale
+			if g.space {
+				adv += gs.wordSpace * gs.hscale
+			}
+			tm = translate(adv, 0).mul(tm)
+			endX, _ := tm.mul(gs.ctm).apply(0, gs.rise)
+			if g.text != "" {
+				in.glyphs = append(in.glyphs, placed{text: g.text, x: x, y: y, endX: endX, size: size, estimated: gs.font.estimated})
+			}
+		}
+	}
+
+	for {
+		tok, err := l.object()
+		if err != nil {
+			return
+		}
+		op, ok := tok.(keyword)
+		if !ok {
+			operands = append(operands, tok)
+			continue
+		}
+
+		switch op {
+		case "q":
+			stack = append(stack, gs)
+		case "Q":
+			if n := len(stack); n > 0 {
+				gs = stack[n-1]
+				stack = stack[:n-1]
+			}
+		case "cm":
+			if len(operands) >= 6 {
+				gs.ctm = matrix{num(0), num(1), num(2), num(3), num(4), num(5)}.mul(gs.ctm)
+			}
+		case "BT":
+			tm, tlm = identity, identity
+		case "Tf":
+			if len(operands) >= 2 {
+				if n, ok := operands[0].(name); ok {
+					gs.font = lookupFont(n)
+				}
+				gs.fontSize = num(1)
+			}
+		case "Tc":
+			gs.charSpace = num(0)
+		case "Tw":
+			gs.wordSpace = num(0)
+		case "Tz":
+			gs.hscale = num(0) / 100
+		case "TL":
+			gs.leading = num(0)
+		case "Ts":
+			gs.rise = num(0)
+		case "Td":
+			td(num(0), num(1))
+		case "TD":
+			gs.leading = -num(1)
+			td(num(0), num(1))
+		case "Tm":
+			if len(operands) >= 6 {
+				tlm = matrix{num(0), num(1), num(2), num(3), num(4), num(5)}
+				tm = tlm
+			}
+		case "T*":
+			td(0, -gs.leading)
+		case "Tj":
+			if len(operands) > 0 {
+				if s, ok := operands[len(operands)-1].(pdfString); ok {
+					show(s)
+				}
+			}
+		case "'":
+			td(0, -gs.leading)
+			if len(operands) > 0 {
+				if s, ok := operands[len(operands)-1].(pdfString); ok {
+					show(s)
+				}
+			}
+		case "\"":
+			if len(operands) >= 3 {
+				gs.wordSpace = num(0)
+				gs.charSpace = num(1)
+				td(0, -gs.leading)
+				if s, ok := operands[2].(pdfString); ok {
+					show(s)
+				}
+			}
+		case "TJ":
+			if len(operands) == 0 {
+				break
+			}
+			arr, _ := operands[len(operands)-1].(array)
+			for _, v := range arr {
+				switch v := v.(type) {
+				case pdfString:
+					show(v)
+				default:
+					// Numbers move the next glyph left by thousandths of an em.
+					if n, ok := in.doc.number(v); ok {
+						tm = translate(-n/1000*gs.fontSize*gs.hscale, 0).mul(tm)
+					}
+				}
+			}
+		case "Do":
+			if depth >= maxFormDepth || len(operands) == 0 {
+				break
+			}
+			n, _ := operands[0].(name)
+			xs, ok := in.doc.resolve(xobjects[n]).(*stream)
+			if !ok || xs.hdr["Subtype"] != name("Form") {
+				break
+			}
+			data, err := in.doc.decode(xs)
+			if err != nil {
+				break
+			}
+			inner := gs
+			if m := in.doc.array(xs.hdr["Matrix"]); len(m) == 6 {
+				var fm matrix
+				for i := range fm {
+					fm[i], _ = in.doc.number(m[i])
+				}
+				inner.ctm = fm.mul(gs.ctm)
+			}
+			res := in.doc.dict(xs.hdr["Resources"])
+			if res == nil {
+				res = resources
+			}
+			in.run(data, res, inner, depth+1)
+		case "ID":
+			// Inline image data runs up to "EI"; skip it.
+			if i := inlineImageEnd(content, l.pos); i > 0 {
+				l.pos = i
+			} else {
+				return
+			}
+		}
+		operands = operands[:0]
+	}
+}
+
+// inlineImageEnd finds the end of inline image data starting at pos: an
+// "EI" delimited by whitespace.
+func inlineImageEnd(data []byte, pos int) int {
+	for i := pos + 1; i+2 <= len(data); {
+		j := bytes.Index(data[i:], []byte("EI"))
+		if j < 0 {
+			return -1
+		}
+		k := i + j
+		before := isSpace(data[k-1])
+		after := k+2 == len(data) || isSpace(data[k+2])
+		if before && after {
+			return k + 2
+		}
+		i = k + 2
+	}
+	return -1
+}
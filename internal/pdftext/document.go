@@ -0,0 +1,361 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package pdftext
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/ascii85"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+)
+
+// ErrEncrypted is returned for encrypted PDFs, which are not supported.
+var ErrEncrypted = errors.New("PDF is encrypted")
+
+// document holds every object of a PDF, keyed by object number.
+//
+// Rather than trusting the cross-reference table, which is often stale or
+// stored as a compressed stream, the file is scanned for "n g obj"
+// definitions. Later definitions win, which matches incremental updates.
+type document struct {
+	objs    map[int]any
+	trailer dict
+}
+
+var objHeader = regexp.MustCompile(`(\d+)\s+(\d+)\s+obj\b`)
+
+func parseDocument(data []byte) (*document, error) {
+	doc := &document{objs: map[int]any{}, trailer: dict{}}
+	var objStreams []*stream
+
+	pos := 0
+	for pos < len(data) {
+		loc := objHeader.FindSubmatchIndex(data[pos:])
+		if loc == nil {
+			break
+		}
+		start := pos + loc[0]
+		if start > 0 && !isSpace(data[start-1]) && !isDelim(data[start-1]) {
+			pos += loc[1]
+			continue
+		}
+		num := atoi(data[pos+loc[2] : pos+loc[3]])
+		l := &lexer{data: data, pos: pos + loc[1]}
+		obj, err := l.streamObject()
+		pos = l.pos
+		if err != nil && obj == nil {
+			continue
+		}
+		doc.objs[num] = obj
+		if s, ok := obj.(*stream); ok {
+			switch s.hdr["Type"] {
+			case name("ObjStm"):
+				objStreams = append(objStreams, s)
+			case name("XRef"):
+				doc.mergeTrailer(s.hdr)
+			}
+		}
+	}
+
+	// Classic trailers.
+	for i := 0; ; {
+		j := bytes.Index(data[i:], []byte("trailer"))
+		if j < 0 {
+			break
+		}
+		l := &lexer{data: data, pos: i + j + len("trailer")}
+		if t, err := l.object(); err == nil {
+			if d, ok := t.(dict); ok {
+				doc.mergeTrailer(d)
+			}
+		}
+		i += j + len("trailer")
+	}
+
+	// Objects packed in object streams; direct definitions take precedence.
+	for _, s := range objStreams {
+		doc.loadObjStream(s)
+	}
+
+	if _, ok := doc.trailer["Encrypt"]; ok {
+		return nil, ErrEncrypted
+	}
+	if len(doc.objs) == 0 {
+		return nil, fmt.Errorf("no PDF objects found")
+	}
+	return doc, nil
+}
+
+func atoi(b []byte) int {
+	n := 0
+	for _, c := range b {
+		n = n*10 + int(c-'0')
+	}
+	return n
+}
+
+func (doc *document) mergeTrailer(d dict) {
+	for k, v := range d {
+		doc.trailer[k] = v
+	}
+}
+
+// streamObject reads an indirect object body, including stream data.
+func (l *lexer) streamObject() (any, error) {
+	obj, err := l.object()
+	if err != nil {
+		return obj, err
+	}
+	hdr, ok := obj.(dict)
+	if !ok {
+		return obj, nil
+	}
+	save := l.pos
+	if tok, err := l.next(); err != nil || tok != keyword("stream") {
+		l.pos = save
+		return obj, nil
+	}
+	// The keyword is followed by CRLF or LF.
+	if l.pos < len(l.data) && l.data[l.pos] == '\r' {
+		l.pos++
+	}
+	if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+		l.pos++
+	}
+	start := l.pos
+
+	// Trust a direct /Length when "endstream" follows it; otherwise search.
+	if n, ok := hdr["Length"].(int); ok && n >= 0 && start+n <= len(l.data) {
+		rest := &lexer{data: l.data, pos: start + n}
+		if tok, err := rest.next(); err == nil && tok == keyword("endstream") {
+			l.pos = rest.pos
+			return &stream{hdr: hdr, data: l.data[start : start+n]}, nil
+		}
+	}
+	end := bytes.Index(l.data[start:], []byte("endstream"))
+	if end < 0 {
+		l.pos = len(l.data)
+		return &stream{hdr: hdr, data: l.data[start:]}, nil
+	}
+	data := l.data[start : start+end]
+	data = bytes.TrimSuffix(data, []byte("\n"))
+	data = bytes.TrimSuffix(data, []byte("\r"))
+	l.pos = start + end + len("endstream")
+	return &stream{hdr: hdr, data: data}, nil
+}
+
+func (doc *document) loadObjStream(s *stream) {
+	data, err := doc.decode(s)
+	if err != nil {
+		return
+	}
+	n, _ := doc.resolve(s.hdr["N"]).(int)
+	first, _ := doc.resolve(s.hdr["First"]).(int)
+	if first > len(data) {
+		return
+	}
+	l := &lexer{data: data}
+	type entry struct{ num, off int }
+	entries := make([]entry, 0, n)
+	for i := 0; i < n; i++ {
+		num, err1 := l.next()
+		off, err2 := l.next()
+		ni, ok1 := num.(int)
+		oi, ok2 := off.(int)
+		if err1 != nil || err2 != nil || !ok1 || !ok2 {
+			break
+		}
+		entries = append(entries, entry{ni, oi})
+	}
+	for _, e := range entries {
+		if _, ok := doc.objs[e.num]; ok {
+			continue
+		}
+		if first+e.off >= len(data) {
+			continue
+		}
+		ol := &lexer{data: data, pos: first + e.off}
+		if obj, err := ol.object(); err == nil {
+			doc.objs[e.num] = obj
+		}
+	}
+}
+
+// resolve follows indirect references.
+func (doc *document) resolve(obj any) any {
+	for i := 0; i < 32; i++ {
+		r, ok := obj.(ref)
+		if !ok {
+			return obj
+		}
+		obj = doc.objs[r.num]
+	}
+	return nil
+}
+
+func (doc *document) dict(obj any) dict {
+	d, _ := doc.resolve(obj).(dict)
+	if d == nil {
+		if s, ok := doc.resolve(obj).(*stream); ok {
+			return s.hdr
+		}
+	}
+	return d
+}
+
+func (doc *document) array(obj any) array {
+	a, _ := doc.resolve(obj).(array)
+	return a
+}
+
+func (doc *document) number(obj any) (float64, bool) {
+	switch v := doc.resolve(obj).(type) {
+	case int:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+// decode applies a stream's filters.
+func (doc *document) decode(s *stream) ([]byte, error) {
+	data := s.data
+	var filters []name
+	switch f := doc.resolve(s.hdr["Filter"]).(type) {
+	case name:
+		filters = []name{f}
+	case array:
+		for _, v := range f {
+			if n, ok := doc.resolve(v).(name); ok {
+				filters = append(filters, n)
+			}
+		}
+	}
+	for _, f := range filters {
+		var err error
+		switch f {
+		case "FlateDecode", "Fl":
+			data, err = inflate(data)
+		case "ASCIIHexDecode", "AHx":
+			data = []byte((&lexer{data: data}).hexString())
+		case "ASCII85Decode", "A85":
+			data, err = decodeASCII85(data)
+		default:
+			return nil, fmt.Errorf("unsupported filter %s", f)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// inflate decompresses zlib data, keeping whatever could be read from a
+// truncated or slightly corrupt stream.
+func inflate(data []byte) ([]byte, error) {
+	r, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	out, err := io.ReadAll(r)
+	if err != nil && len(out) == 0 {
+		return nil, err
+	}
+	return out, nil
+}
+
+func decodeASCII85(data []byte) ([]byte, error) {
+	data = bytes.TrimPrefix(bytes.TrimSpace(data), []byte("<~"))
+	if i := bytes.Index(data, []byte("~>")); i >= 0 {
+		data = data[:i]
+	}
+	out := make([]byte, 4*len(data)/5+4)
+	n, _, err := ascii85.Decode(out, data, true)
+	return out[:n], err
+}
+
+// pages returns the page dictionaries in order, with inheritable
+// attributes (Resources) copied down from the page tree.
+func (doc *document) pages() []dict {
+	root := doc.dict(doc.trailer["Root"])
+	if root == nil {
+		for _, obj := range doc.objs {
+			if d, ok := obj.(dict); ok && d["Type"] == name("Catalog") {
+				root = d
+				break
+			}
+		}
+	}
+	if root == nil {
+		return nil
+	}
+
+	var pages []dict
+	seen := map[any]bool{}
+	var walk func(node any, inherited any, depth int)
+	walk = func(node any, inherited any, depth int) {
+		if depth > 64 {
+			return
+		}
+		if r, ok := node.(ref); ok {
+			if seen[r] {
+				return
+			}
+			seen[r] = true
+		}
+		d := doc.dict(node)
+		if d == nil {
+			return
+		}
+		if res, ok := d["Resources"]; ok {
+			inherited = res
+		}
+		kids, hasKids := d["Kids"]
+		if d["Type"] == name("Page") || (!hasKids && d["Type"] != name("Pages")) {
+			page := dict{}
+			for k, v := range d {
+				page[k] = v
+			}
+			if _, ok := page["Resources"]; !ok && inherited != nil {
+				page["Resources"] = inherited
+			}
+			pages = append(pages, page)
+			return
+		}
+		for _, kid := range doc.array(kids) {
+			walk(kid, inherited, depth+1)
+		}
+	}
+	walk(root["Pages"], nil, 0)
+	return pages
+}
+
+// contents returns a page's decoded content streams, concatenated.
+func (doc *document) contents(page dict) []byte {
+	var parts []any
+	switch c := doc.resolve(page["Contents"]).(type) {
+	case *stream:
+		parts = []any{c}
+	case array:
+		parts = c
+	}
+	var buf bytes.Buffer
+	for _, p := range parts {
+		s, ok := doc.resolve(p).(*stream)
+		if !ok {
+			continue
+		}
+		data, err := doc.decode(s)
+		if err != nil {
+			continue
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package pdftext
+
+import "strings"
+
+// Base encodings of simple fonts, as code-to-text tables.
+var (
+	standardEncoding [256]string
+	winAnsiEncoding  [256]string
+	macRomanEncoding [256]string
+)
+
+// winAnsiHigh holds the WinAnsi characters in 0x80-0x9F; codes 0xA0-0xFF
+// are Latin-1.
+const winAnsiHigh = "€\x00‚ƒ„…†‡ˆ‰Š‹Œ\x00Ž\x00\x00‘’“”•–—˜™š›œ\x00žŸ"
+
+// macRomanHigh holds the Mac OS Roman characters in 0x80-0xFF.
+const macRomanHigh = "ÄÅÇÉÑÖÜáàâäãåçéèêëíìîïñóòôöõúùûü†°¢£§•¶ß®©™´¨≠ÆØ∞±≤≥¥µ∂∑∏π∫ªºΩæø¿¡¬√ƒ≈∆«»… ÀÃÕŒœ–—“”‘’÷◊ÿŸ⁄€‹›ﬁﬂ‡·‚„‰ÂÊÁËÈÍÎÏÌÓÔÒÚÛÙıˆ˜¯˘˙˚¸˝˛ˇ"
+
+// standardHigh lists the StandardEncoding codes above 0x7E that show up in
+// running text.
+var standardHigh = map[int]string{
+	0xa1: "¡", 0xa2: "¢", 0xa3: "£", 0xa5: "¥", 0xa7: "§", 0xa9: "'", 0xaa: "“",
+	0xab: "«", 0xae: "fi", 0xaf: "fl", 0xb1: "–", 0xb2: "†", 0xb3: "‡",
+	0xb4: "·", 0xb6: "¶", 0xb7: "•", 0xb8: "‚", 0xb9: "„", 0xba: "”",
+	0xbb: "»", 0xbc: "…", 0xbd: "‰", 0xbf: "¿", 0xd0: "—", 0xe1: "Æ",
+	0xe8: "Ł", 0xe9: "Ø", 0xea: "Œ", 0xf1: "æ", 0xf5: "ı", 0xf8: "ł",
+	0xf9: "ø", 0xfa: "œ", 0xfb: "ß",
+}
+
+// latin1Names are the glyph names of U+00C0 to U+00FF.
+var latin1Names = strings.Fields(`Agrave Aacute Acircumflex Atilde Adieresis Aring AE Ccedilla
+	Egrave Eacute Ecircumflex Edieresis Igrave Iacute Icircumflex Idieresis
+	Eth Ntilde Ograve Oacute Ocircumflex Otilde Odieresis multiply
+	Oslash Ugrave Uacute Ucircumflex Udieresis Yacute Thorn germandbls
+	agrave aacute acircumflex atilde adieresis aring ae ccedilla
+	egrave eacute ecircumflex edieresis igrave iacute icircumflex idieresis
+	eth ntilde ograve oacute ocircumflex otilde odieresis divide
+	oslash ugrave uacute ucircumflex udieresis yacute thorn ydieresis`)
+
+// glyphNames maps glyph names that are not a single character to text.
+// Ligatures expand to their letters so that extracted words stay searchable.
+var glyphNames = map[string]string{
+	"space": " ", "exclam": "!", "quotedbl": "\"", "numbersign": "#",
+	"dollar": "$", "percent": "%", "ampersand": "&", "quotesingle": "'",
+	"quoteright": "’", "quoteleft": "‘", "parenleft": "(", "parenright": ")",
+	"asterisk": "*", "plus": "+", "comma": ",", "hyphen": "-", "period": ".",
+	"slash": "/", "colon": ":", "semicolon": ";", "less": "<", "equal": "=",
+	"greater": ">", "question": "?", "at": "@", "bracketleft": "[",
+	"backslash": "\\", "bracketright": "]", "asciicircum": "^",
+	"underscore": "_", "grave": "`", "braceleft": "{", "bar": "|",
+	"braceright": "}", "asciitilde": "~",
+	"zero": "0", "one": "1", "two": "2", "three": "3", "four": "4",
+	"five": "5", "six": "6", "seven": "7", "eight": "8", "nine": "9",
+
+	"fi": "fi", "fl": "fl", "ff": "ff", "ffi": "ffi", "ffl": "ffl",
+	"endash": "–", "emdash": "—", "bullet": "•", "ellipsis": "…",
+	"quotedblleft": "“", "quotedblright": "”", "quotesinglbase": "‚",
+	"quotedblbase": "„", "guillemotleft": "«", "guillemotright": "»",
+	"guilsinglleft": "‹", "guilsinglright": "›", "dagger": "†",
+	"daggerdbl": "‡", "section": "§", "paragraph": "¶", "periodcentered": "·",
+	"copyright": "©", "registered": "®", "trademark": "™", "degree": "°",
+	"dotlessi": "ı", "dotlessj": "ȷ", "lslash": "ł", "Lslash": "Ł",
+	"oe": "œ", "OE": "Œ", "exclamdown": "¡", "questiondown": "¿",
+	"sterling": "£", "yen": "¥", "cent": "¢", "Euro": "€", "perthousand": "‰",
+	"acute": "´", "dieresis": "¨", "circumflex": "ˆ", "tilde": "˜",
+	"macron": "¯", "breve": "˘", "dotaccent": "˙", "ring": "˚",
+	"cedilla": "¸", "hungarumlaut": "˝", "ogonek": "˛", "caron": "ˇ",
+	"nbspace": " ", "sfthyphen": "-", "minus": "−", "plusminus": "±",
+	"logicalnot": "¬", "mu": "µ", "ordfeminine": "ª", "ordmasculine": "º",
+	"onehalf": "½", "onequarter": "¼", "threequarters": "¾",
+	"twosuperior": "²", "threesuperior": "³", "onesuperior": "¹",
+
+	"alpha": "α", "beta": "β", "gamma": "γ", "delta": "δ", "epsilon": "ε",
+	"zeta": "ζ", "eta": "η", "theta": "θ", "iota": "ι", "kappa": "κ",
+	"lambda": "λ", "nu": "ν", "xi": "ξ", "omicron": "ο", "pi": "π",
+	"rho": "ρ", "sigma": "σ", "tau": "τ", "upsilon": "υ", "phi": "φ",
+	"chi": "χ", "psi": "ψ", "omega": "ω", "Gamma": "Γ", "Delta": "Δ",
+	"Theta": "Θ", "Lambda": "Λ", "Xi": "Ξ", "Pi": "Π", "Sigma": "Σ",
+	"Upsilon": "Υ", "Phi": "Φ", "Psi": "Ψ", "Omega": "Ω",
+
+	"infinity": "∞", "partialdiff": "∂", "summation": "∑", "product": "∏",
+	"integral": "∫", "radical": "√", "approxequal": "≈", "notequal": "≠",
+	"lessequal": "≤", "greaterequal": "≥", "element": "∈", "arrowright": "→",
+	"arrowleft": "←", "arrowboth": "↔", "arrowdblright": "⇒", "nabla": "∇",
+	"proportional": "∝", "equivalence": "≡", "union": "∪", "intersection": "∩",
+	"angleleft": "⟨", "angleright": "⟩", "universal": "∀", "existential": "∃",
+}
+
+func init() {
+	for c := 32; c < 127; c++ {
+		standardEncoding[c] = string(rune(c))
+		winAnsiEncoding[c] = string(rune(c))
+		macRomanEncoding[c] = string(rune(c))
+	}
+	standardEncoding[0x27] = "’"
+	standardEncoding[0x60] = "‘"
+	for c, t := range standardHigh {
+		standardEncoding[c] = t
+	}
+
+	for i, r := range []rune(winAnsiHigh) {
+		if r != 0 {
+			winAnsiEncoding[0x80+i] = string(r)
+		}
+	}
+	for c := 0xa0; c < 0x100; c++ {
+		winAnsiEncoding[c] = string(rune(c))
+	}
+	winAnsiEncoding[0xa0] = " "
+	winAnsiEncoding[0xad] = "-"
+
+	for i, r := range []rune(macRomanHigh) {
+		macRomanEncoding[0x80+i] = string(r)
+	}
+
+	for i, n := range latin1Names {
+		if _, ok := glyphNames[n]; !ok {
+			glyphNames[n] = string(rune(0xc0 + i))
+		}
+	}
+}
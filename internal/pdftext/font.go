@@ -0,0 +1,313 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package pdftext
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// font maps the character codes of a PDF font to text and glyph widths.
+type font struct {
+	// codeLen is the number of bytes per character code: 1 for simple
+	// fonts, usually 2 for composite (Type0) fonts.
+	codeLen int
+	// toUnicode, when present, takes precedence over enc.
+	toUnicode map[int]string
+	enc       [256]string
+	widths    map[int]float64
+	// defaultWidth is used for codes missing from widths, in glyph space
+	// (1/1000 of the font size).
+	defaultWidth float64
+	// estimated is set when the font carries no widths at all, so glyph
+	// positions are approximate.
+	estimated bool
+}
+
+// glyph is one decoded character code.
+type glyph struct {
+	text  string
+	width float64 // in glyph space
+	space bool    // single-byte code 32, which word spacing applies to
+}
+
+func (f *font) decode(s pdfString) []glyph {
+	var out []glyph
+	for i := 0; i < len(s); {
+		n := f.codeLen
+		if i+n > len(s) {
+			n = len(s) - i
+		}
+		code := 0
+		for _, b := range []byte(s[i : i+n]) {
+			code = code<<8 | int(b)
+		}
+		i += n
+
+		g := glyph{width: f.defaultWidth, space: n == 1 && code == 32}
+		if w, ok := f.widths[code]; ok {
+			g.width = w
+		}
+		if t, ok := f.toUnicode[code]; ok {
+			g.text = t
+		} else if n == 1 {
+			g.text = f.enc[code]
+		}
+		out = append(out, g)
+	}
+	return out
+}
+
+// loadFont builds a font from its dictionary.
+func (doc *document) loadFont(d dict) *font {
+	f := &font{codeLen: 1, defaultWidth: 500}
+	subtype, _ := doc.resolve(d["Subtype"]).(name)
+
+	if subtype == "Type0" {
+		f.codeLen = 2
+		f.defaultWidth = 1000
+		if desc := doc.array(d["DescendantFonts"]); len(desc) > 0 {
+			dd := doc.dict(desc[0])
+			if dw, ok := doc.number(dd["DW"]); ok {
+				f.defaultWidth = dw
+			}
+			f.widths = doc.cidWidths(doc.array(dd["W"]))
+		}
+		if len(f.widths) == 0 {
+			f.estimated = true
+		}
+	} else {
+		f.enc = doc.simpleEncoding(d, subtype)
+		first, _ := doc.number(d["FirstChar"])
+		widths := doc.array(d["Widths"])
+		if len(widths) == 0 {
+			f.estimated = true
+		}
+		f.widths = make(map[int]float64, len(widths))
+		for i, w := range widths {
+			if v, ok := doc.number(w); ok {
+				f.widths[int(first)+i] = v
+			}
+		}
+		if subtype == "Type3" {
+			// Type3 widths are in the font's own glyph space.
+			if m := doc.array(d["FontMatrix"]); len(m) == 6 {
+				if scale, ok := doc.number(m[0]); ok && scale != 0 {
+					for c, w := range f.widths {
+						f.widths[c] = w * scale * 1000
+					}
+				}
+			}
+		}
+	}
+
+	if s, ok := doc.resolve(d["ToUnicode"]).(*stream); ok {
+		if data, err := doc.decode(s); err == nil {
+			cm, codeLen := parseCMap(data)
+			if len(cm) > 0 {
+				f.toUnicode = cm
+				if codeLen > 0 {
+					f.codeLen = codeLen
+				}
+			}
+		}
+	}
+	return f
+}
+
+// cidWidths parses a CIDFont /W array: "c [w1 w2 ...]" or "cFirst cLast w".
+func (doc *document) cidWidths(w array) map[int]float64 {
+	widths := map[int]float64{}
+	for i := 0; i < len(w); {
+		start, ok := doc.number(w[i])
+		if !ok || i+1 >= len(w) {
+			break
+		}
+		if list := doc.array(w[i+1]); list != nil {
+			for j, v := range list {
+				if n, ok := doc.number(v); ok {
+					widths[int(start)+j] = n
+				}
+			}
+			i += 2
+			continue
+		}
+		if i+2 >= len(w) {
+			break
+		}
+		end, _ := doc.number(w[i+1])
+		n, _ := doc.number(w[i+2])
+		for c := int(start); c <= int(end) && c-int(start) < 65536; c++ {
+			widths[c] = n
+		}
+		i += 3
+	}
+	return widths
+}
+
+// simpleEncoding returns the code-to-text table of a simple font: a base
+// encoding adjusted by /Differences.
+func (doc *document) simpleEncoding(d dict, subtype name) [256]string {
+	base := standardEncoding
+	if subtype == "TrueType" {
+		base = winAnsiEncoding
+	}
+	var diffs array
+	switch e := doc.resolve(d["Encoding"]).(type) {
+	case name:
+		base = namedEncoding(e, base)
+	case dict:
+		if b, ok := doc.resolve(e["BaseEncoding"]).(name); ok {
+			base = namedEncoding(b, base)
+		}
+		diffs = doc.array(e["Differences"])
+	}
+
+	enc := base
+	code := 0
+	for _, v := range diffs {
+		switch v := doc.resolve(v).(type) {
+		case int:
+			code = v
+		case name:
+			if code >= 0 && code < 256 {
+				enc[code] = glyphText(string(v))
+			}
+			code++
+		}
+	}
+	return enc
+}
+
+func namedEncoding(n name, fallback [256]string) [256]string {
+	switch n {
+	case "WinAnsiEncoding":
+		return winAnsiEncoding
+	case "MacRomanEncoding":
+		return macRomanEncoding
+	case "StandardEncoding":
+		return standardEncoding
+	}
+	return fallback
+}
+
+// parseCMap reads the bfchar and bfrange mappings of a ToUnicode CMap and
+// returns them with the code length taken from the codespace range.
+func parseCMap(data []byte) (map[int]string, int) {
+	m := map[int]string{}
+	codeLen := 0
+	l := &lexer{data: data}
+	var operands []any
+	for {
+		tok, err := l.object()
+		if err != nil {
+			break
+		}
+		kw, ok := tok.(keyword)
+		if !ok {
+			operands = append(operands, tok)
+			continue
+		}
+		switch kw {
+		case "endcodespacerange":
+			if len(operands) > 0 && codeLen == 0 {
+				if s, ok := operands[0].(pdfString); ok {
+					codeLen = len(s)
+				}
+			}
+		case "endbfchar":
+			for i := 0; i+1 < len(operands); i += 2 {
+				src, ok1 := operands[i].(pdfString)
+				dst, ok2 := operands[i+1].(pdfString)
+				if ok1 && ok2 {
+					m[codeOf(src)] = utf16Text(dst)
+				} else if n, ok := operands[i+1].(name); ok1 && ok {
+					m[codeOf(src)] = glyphText(string(n))
+				}
+			}
+		case "endbfrange":
+			for i := 0; i+2 < len(operands); i += 3 {
+				lo, ok1 := operands[i].(pdfString)
+				hi, ok2 := operands[i+1].(pdfString)
+				if !ok1 || !ok2 {
+					continue
+				}
+				start, end := codeOf(lo), codeOf(hi)
+				if end < start || end-start > 65535 {
+					continue
+				}
+				switch dst := operands[i+2].(type) {
+				case pdfString:
+					units := utf16Units(dst)
+					for c := start; c <= end; c++ {
+						if len(units) == 0 {
+							break
+						}
+						u := append([]uint16(nil), units...)
+						u[len(u)-1] += uint16(c - start)
+						m[c] = string(utf16.Decode(u))
+					}
+				case array:
+					for j, v := range dst {
+						if s, ok := v.(pdfString); ok && start+j <= end {
+							m[start+j] = utf16Text(s)
+						}
+					}
+				}
+			}
+		}
+		operands = operands[:0]
+	}
+	return m, codeLen
+}
+
+func codeOf(s pdfString) int {
+	code := 0
+	for _, b := range []byte(s) {
+		code = code<<8 | int(b)
+	}
+	return code
+}
+
+func utf16Units(s pdfString) []uint16 {
+	units := make([]uint16, 0, len(s)/2)
+	for i := 0; i+1 < len(s); i += 2 {
+		units = append(units, uint16(s[i])<<8|uint16(s[i+1]))
+	}
+	return units
+}
+
+func utf16Text(s pdfString) string {
+	if len(s) == 1 {
+		return string(rune(s[0]))
+	}
+	return string(utf16.Decode(utf16Units(s)))
+}
+
+// glyphText maps a PostScript glyph name to text.
+func glyphText(n string) string {
+	if t, ok := glyphNames[n]; ok {
+		return t
+	}
+	if len(n) == 1 {
+		return n
+	}
+	// uniXXXX and uXXXX[XX] names carry the code point.
+	if hex, ok := strings.CutPrefix(n, "uni"); ok && len(hex) >= 4 {
+		if v, err := strconv.ParseUint(hex[:4], 16, 32); err == nil {
+			return string(rune(v))
+		}
+	}
+	if hex, ok := strings.CutPrefix(n, "u"); ok && len(hex) >= 4 && len(hex) <= 6 {
+		if v, err := strconv.ParseUint(hex, 16, 32); err == nil {
+			return string(rune(v))
+		}
+	}
+	// Suffixed variants such as "a.sc" or "one.oldstyle".
+	if base, _, ok := strings.Cut(n, "."); ok && base != "" {
+		return glyphText(base)
+	}
+	return ""
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package pdftext
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// line is a run of glyphs sharing a baseline.
+type line struct {
+	text string
+	x, y float64
+	size float64
+}
+
+// Layout thresholds, as fractions of the font size.
+const (
+	// baselineTolerance is how far a glyph may sit above or below the
+	// line (superscripts, subscripts) and still belong to it.
+	baselineTolerance = 0.6
+	// wordGap is the horizontal gap that separates two words.
+	wordGap = 0.15
+	// estimatedWordGap is used when glyph widths are guessed.
+	estimatedWordGap = 0.45
+	// paragraphGap is the vertical gap, relative to the typical line
+	// spacing, that starts a new paragraph.
+	paragraphGap = 1.6
+)
+
+// buildLines groups glyphs into lines in content-stream order, which for
+// typeset papers is reading order, column by column.
+func buildLines(glyphs []placed) []line {
+	var lines []line
+	var b strings.Builder
+	var cur line
+	var lastEnd float64
+	flush := func() {
+		if text := strings.TrimSpace(b.String()); text != "" {
+			cur.text = text
+			lines = append(lines, cur)
+		}
+		b.Reset()
+	}
+
+	for i, g := range glyphs {
+		size := math.Max(g.size, 1)
+		if i > 0 {
+			ref := math.Max(size, cur.size)
+			if math.Abs(g.y-cur.y) > baselineTolerance*ref || g.x < lastEnd-2*ref {
+				flush()
+			} else {
+				gap := wordGap
+				if g.estimated {
+					gap = estimatedWordGap
+				}
+				text := b.String()
+				if g.x-lastEnd > gap*ref && !strings.HasSuffix(text, " ") && !strings.HasPrefix(g.text, " ") {
+					b.WriteByte(' ')
+				}
+			}
+		}
+		if b.Len() == 0 {
+			cur = line{x: g.x, y: g.y, size: size}
+		}
+		b.WriteString(g.text)
+		lastEnd = g.endX
+	}
+	flush()
+	return lines
+}
+
+// pageText joins lines into text, leaving a blank line between paragraphs.
+// A paragraph starts after an unusually large vertical gap or at an
+// indented line.
+func pageText(lines []line) string {
+	var gaps []float64
+	for i := 1; i < len(lines); i++ {
+		if gap := lines[i-1].y - lines[i].y; gap > 0 {
+			gaps = append(gaps, gap)
+		}
+	}
+	spacing := median(gaps)
+
+	var b strings.Builder
+	for i, l := range lines {
+		if i > 0 {
+			prev := lines[i-1]
+			gap := prev.y - l.y
+			indent := l.x - prev.x
+			b.WriteByte('\n')
+			if spacing > 0 && gap > paragraphGap*spacing || gap > 0 && indent > l.size && indent < 4*l.size {
+				b.WriteByte('\n')
+			}
+		}
+		b.WriteString(l.text)
+	}
+	return b.String()
+}
+
+func median(v []float64) float64 {
+	if len(v) == 0 {
+		return 0
+	}
+	s := append([]float64(nil), v...)
+	sort.Float64s(s)
+	return s[len(s)/2]
+}
@@ -0,0 +1,350 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package pdftext
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+)
+
+// PDF objects are represented with plain Go values:
+//
+//	null     nil
+//	boolean  bool
+//	integer  int
+//	real     float64
+//	string   pdfString
+//	name     name
+//	array    array
+//	dict     dict
+//	stream   *stream
+//	R        ref
+//
+// Content streams also produce operator keywords.
+type (
+	pdfString string
+	name      string
+	array     []any
+	dict      map[name]any
+	keyword   string
+	ref       struct{ num, gen int }
+)
+
+// stream is a dictionary followed by (still encoded) data.
+type stream struct {
+	hdr  dict
+	data []byte
+}
+
+// errEOF marks the end of input in the lexer.
+var errEOF = fmt.Errorf("unexpected end of PDF data")
+
+// lexer reads PDF objects from a byte slice.
+type lexer struct {
+	data []byte
+	pos  int
+}
+
+func isSpace(c byte) bool {
+	switch c {
+	case 0, '\t', '\n', '\f', '\r', ' ':
+		return true
+	}
+	return false
+}
+
+func isDelim(c byte) bool {
+	switch c {
+	case '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+		return true
+	}
+	return false
+}
+
+// skipSpace skips whitespace and comments.
+func (l *lexer) skipSpace() {
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		if isSpace(c) {
+			l.pos++
+			continue
+		}
+		if c == '%' {
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+			continue
+		}
+		return
+	}
+}
+
+// regular reads a run of regular (non-space, non-delimiter) characters.
+func (l *lexer) regular() []byte {
+	start := l.pos
+	for l.pos < len(l.data) && !isSpace(l.data[l.pos]) && !isDelim(l.data[l.pos]) {
+		l.pos++
+	}
+	return l.data[start:l.pos]
+}
+
+// next reads one object or keyword. Array and dictionary delimiters are
+// returned as keywords so callers can assemble containers.
+func (l *lexer) next() (any, error) {
+	l.skipSpace()
+	if l.pos >= len(l.data) {
+		return nil, errEOF
+	}
+	c := l.data[l.pos]
+	switch c {
+	case '/':
+		l.pos++
+		return l.name(), nil
+	case '(':
+		l.pos++
+		return l.literalString(), nil
+	case '<':
+		if l.pos+1 < len(l.data) && l.data[l.pos+1] == '<' {
+			l.pos += 2
+			return keyword("<<"), nil
+		}
+		l.pos++
+		return l.hexString(), nil
+	case '>':
+		if l.pos+1 < len(l.data) && l.data[l.pos+1] == '>' {
+			l.pos += 2
+			return keyword(">>"), nil
+		}
+		l.pos++
+		return keyword(">"), nil
+	case '[', ']', '{', '}':
+		l.pos++
+		return keyword(c), nil
+	case ')':
+		l.pos++
+		return keyword(")"), nil
+	}
+
+	tok := l.regular()
+	if len(tok) == 0 {
+		// A stray delimiter; skip it rather than loop forever.
+		l.pos++
+		return keyword(c), nil
+	}
+	if n, ok := parseNumber(tok); ok {
+		return n, nil
+	}
+	switch string(tok) {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+	return keyword(tok), nil
+}
+
+func parseNumber(tok []byte) (any, bool) {
+	c := tok[0]
+	if !(c >= '0' && c <= '9') && c != '-' && c != '+' && c != '.' {
+		return nil, false
+	}
+	if i, err := strconv.Atoi(string(tok)); err == nil {
+		return i, true
+	}
+	if f, err := strconv.ParseFloat(string(tok), 64); err == nil {
+		return f, true
+	}
+	// Some producers write "--5" or "5-"; treat them as zero.
+	if bytes.IndexFunc(tok, func(r rune) bool { return !(r >= '0' && r <= '9' || r == '-' || r == '+' || r == '.') }) < 0 {
+		return 0, true
+	}
+	return nil, false
+}
+
+func (l *lexer) name() name {
+	raw := l.regular()
+	if bytes.IndexByte(raw, '#') < 0 {
+		return name(raw)
+	}
+	var b []byte
+	for i := 0; i < len(raw); i++ {
+		if raw[i] == '#' && i+2 < len(raw) {
+			if v, err := strconv.ParseUint(string(raw[i+1:i+3]), 16, 8); err == nil {
+				b = append(b, byte(v))
+				i += 2
+				continue
+			}
+		}
+		b = append(b, raw[i])
+	}
+	return name(b)
+}
+
+func (l *lexer) literalString() pdfString {
+	var b []byte
+	depth := 1
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return pdfString(b)
+			}
+		case '\\':
+			if l.pos >= len(l.data) {
+				return pdfString(b)
+			}
+			e := l.data[l.pos]
+			l.pos++
+			switch e {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r':
+				// Line continuation.
+				if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+					l.pos++
+				}
+				continue
+			case '\n':
+				continue
+			default:
+				if e >= '0' && e <= '7' {
+					v := int(e - '0')
+					for k := 0; k < 2 && l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '7'; k++ {
+						v = v*8 + int(l.data[l.pos]-'0')
+						l.pos++
+					}
+					c = byte(v)
+				} else {
+					c = e
+				}
+			}
+		}
+		b = append(b, c)
+	}
+	return pdfString(b)
+}
+
+func (l *lexer) hexString() pdfString {
+	var b []byte
+	hi, half := byte(0), false
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		if c == '>' {
+			break
+		}
+		v, ok := unhex(c)
+		if !ok {
+			continue
+		}
+		if half {
+			b = append(b, hi<<4|v)
+		} else {
+			hi = v
+		}
+		half = !half
+	}
+	if half {
+		b = append(b, hi<<4)
+	}
+	return pdfString(b)
+}
+
+func unhex(c byte) (byte, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0', true
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10, true
+	case c >= 'A' && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
+
+// object reads a complete object, assembling arrays, dictionaries and
+// indirect references. Streams are not handled here; see parser.object.
+func (l *lexer) object() (any, error) {
+	tok, err := l.next()
+	if err != nil {
+		return nil, err
+	}
+	return l.complete(tok)
+}
+
+func (l *lexer) complete(tok any) (any, error) {
+	switch t := tok.(type) {
+	case keyword:
+		switch t {
+		case "[":
+			var arr array
+			for {
+				tok, err := l.next()
+				if err != nil {
+					return arr, err
+				}
+				if tok == keyword("]") {
+					return arr, nil
+				}
+				obj, err := l.complete(tok)
+				if err != nil {
+					return arr, err
+				}
+				arr = append(arr, obj)
+			}
+		case "<<":
+			d := dict{}
+			for {
+				tok, err := l.next()
+				if err != nil {
+					return d, err
+				}
+				if tok == keyword(">>") {
+					return d, nil
+				}
+				key, ok := tok.(name)
+				if !ok {
+					// Malformed key; skip it.
+					continue
+				}
+				val, err := l.object()
+				if err != nil {
+					return d, err
+				}
+				if val == keyword(">>") {
+					return d, nil
+				}
+				d[key] = val
+			}
+		}
+	case int:
+		// "n g R" is an indirect reference.
+		save := l.pos
+		if g, err := l.next(); err == nil {
+			if gen, ok := g.(int); ok {
+				if r, err := l.next(); err == nil && r == keyword("R") {
+					return ref{t, gen}, nil
+				}
+			}
+		}
+		l.pos = save
+	}
+	return tok, nil
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+// Package pdftext extracts running text from PDF files without external
+// tools.
+//
+// It understands what typeset papers need: Flate-compressed content and
+// object streams, simple and composite fonts with ToUnicode maps or glyph
+// name encodings, and form XObjects. Text is read in content-stream order,
+// which for LaTeX output is reading order, and grouped into lines and
+// paragraphs by position. Clean then removes running headers and footers and
+// rejoins hyphenated words; it also accepts the output of pdftotext so both
+// backends produce the same shape of text.
+package pdftext
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Extract returns the raw text of each page of a PDF.
+func Extract(data []byte) ([]string, error) {
+	doc, err := parseDocument(data)
+	if err != nil {
+		return nil, err
+	}
+	pages := doc.pages()
+	if len(pages) == 0 {
+		return nil, fmt.Errorf("no pages found")
+	}
+
+	texts := make([]string, len(pages))
+	for i, page := range pages {
+		in := &interpreter{doc: doc}
+		in.run(doc.contents(page), doc.dict(page["Resources"]), gstate{ctm: identity, hscale: 1}, 0)
+		texts[i] = pageText(buildLines(in.glyphs))
+	}
+	return texts, nil
+}
+
+// ExtractFile reads a PDF file and returns the raw text of each page.
+func ExtractFile(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Extract(data)
+}
+
+// SplitPages splits pdftotext output, which separates pages with form
+// feeds, into the text of each page.
+func SplitPages(text string) []string {
+	pages := strings.Split(text, "\f")
+	if n := len(pages); n > 1 && strings.TrimSpace(pages[n-1]) == "" {
+		pages = pages[:n-1]
+	}
+	return pages
+}
+
+// pageMarker precedes the text of each page in Markdown output. It is an
+// HTML comment, so it does not show when the Markdown is rendered.
+const pageMarker = "<!-- page %d -->"
+
+var pageMarkerPattern = regexp.MustCompile(`(?m)^<!-- page \d+ -->\n?`)
+
+// Markdown renders pages as one document with a marker before each page.
+func Markdown(pages []Page) string {
+	var b strings.Builder
+	for i, p := range pages {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, pageMarker+"\n\n", p.Number)
+		if p.Text != "" {
+			b.WriteString(p.Text)
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// StripMarkers removes the page markers written by Markdown.
+func StripMarkers(text string) string {
+	return pageMarkerPattern.ReplaceAllString(text, "")
+}
@@ -0,0 +1,171 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package pdftext
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// buildPDF assembles a PDF from object bodies (object n is objs[n-1]).
+// A body may contain "%STREAM%" followed by data, which is Flate-encoded
+// into a stream. Empty bodies are left out, for objects stored in object
+// streams.
+func buildPDF(t *testing.T, objs []string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.5\n%\xe2\xe3\xcf\xd3\n")
+	offsets := make([]int, len(objs))
+	for i, body := range objs {
+		if body == "" {
+			continue
+		}
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n", i+1)
+		if hdr, data, ok := strings.Cut(body, "%STREAM%"); ok {
+			var z bytes.Buffer
+			w := zlib.NewWriter(&z)
+			w.Write([]byte(data))
+			w.Close()
+			fmt.Fprintf(&buf, "<< %s /Filter /FlateDecode /Length %d >>\nstream\n", hdr, z.Len())
+			buf.Write(z.Bytes())
+			buf.WriteString("\nendstream")
+		} else {
+			buf.WriteString(body)
+		}
+		buf.WriteString("\nendobj\n")
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objs)+1)
+	for _, off := range offsets {
+		if off == 0 {
+			buf.WriteString("0000000000 00001 f \n")
+			continue
+		}
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objs)+1, xref)
+	return buf.Bytes()
+}
+
+// testFonts returns object bodies for a simple WinAnsi font (object 3),
+// an object stream holding a Type0 font (object 4 holds object 5), and the
+// Type0 font's ToUnicode map (object 6).
+func testFonts() []string {
+	widths := strings.TrimSpace(strings.Repeat("500 ", 95))
+	simple := fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding /FirstChar 32 /LastChar 126 /Widths [%s] >>", widths)
+	type0 := "<< /Type /Font /Subtype /Type0 /BaseFont /Test /Encoding /Identity-H /ToUnicode 6 0 R /DescendantFonts [<< /Type /Font /Subtype /CIDFontType2 /W [1 [500 500 500 500]] >>] >>"
+	objStm := fmt.Sprintf("/Type /ObjStm /N 1 /First 4 %%STREAM%%5 0 %s", type0)
+	cmap := `/CIDInit /ProcSet findresource begin
+12 dict begin
+begincmap
+1 begincodespacerange
+<0000> <FFFF>
+endcodespacerange
+1 beginbfrange
+<0001> <0003> <0061>
+endbfrange
+1 beginbfchar
+<0004> <FB01>
+endbfchar
+endcmap
+CMapName currentdict /CMap defineresource pop
+end
+end`
+	return []string{simple, objStm, "", "/Type /CMap %STREAM%" + cmap}
+}
+
+func TestExtract(t *testing.T) {
+	page1 := `BT /F1 10 Tf 72 750 Td (Journal of Tests) Tj ET
+BT /F1 10 Tf 72 700 Td [(Hello)-500(World)] TJ
+0 -12 Td (An exam-) Tj
+0 -12 Td (ple of text.) Tj
+/F2 10 Tf 0 -12 Td <0001000200030004> Tj ET
+BT /F1 10 Tf 300 50 Td (1) Tj ET`
+	page2 := `q 1 0 0 1 0 700 cm BT /F1 10 Tf 72 0 Td (Second page) Tj ET Q`
+
+	objs := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [7 0 R 8 0 R] /Count 2 /Resources << /Font << /F1 3 0 R /F2 5 0 R >> >> >>",
+	}
+	objs = append(objs, testFonts()...)
+	objs = append(objs,
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 9 0 R >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents [10 0 R] >>",
+		"%STREAM%"+page1,
+		"%STREAM%"+page2,
+	)
+
+	pages, err := Extract(buildPDF(t, objs))
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	if len(pages) != 2 {
+		t.Fatalf("got %d pages, want 2", len(pages))
+	}
+
+	want := "Journal of Tests\n\nHello World\nAn exam-\nple of text.\nabcﬁ\n\n1"
+	if pages[0] != want {
+		t.Errorf("page 1 = %q\nwant     %q", pages[0], want)
+	}
+	if pages[1] != "Second page" {
+		t.Errorf("page 2 = %q", pages[1])
+	}
+}
+
+func TestExtractErrors(t *testing.T) {
+	if _, err := Extract([]byte("not a pdf")); err == nil {
+		t.Error("expected error for non-PDF data")
+	}
+
+	encrypted := buildPDF(t, []string{"<< /Type /Catalog /Pages 2 0 R >>", "<< /Type /Pages /Kids [] /Count 0 >>"})
+	encrypted = bytes.Replace(encrypted, []byte("/Root 1 0 R"), []byte("/Root 1 0 R /Encrypt 2 0 R"), 1)
+	if _, err := Extract(encrypted); err != ErrEncrypted {
+		t.Errorf("err = %v, want ErrEncrypted", err)
+	}
+}
+
+func TestClean(t *testing.T) {
+	raw := []string{
+		"Journal of Tests 12\nA Title\n\nThe first para-\ngraph goes on.\nA state-of-the-\nart result, un-\n\nRelated work.\n1",
+		"Journal of Tests 13\nwith a para-\n",
+		"Journal of Tests 14\ngraph across pages. Figure ﬁve.\n- 3 -",
+	}
+	pages := Clean(raw)
+
+	want := []string{
+		"A Title\n\nThe first paragraph\ngoes on.\nA state-of-the-art\nresult, un-\n\nRelated work.",
+		"with a paragraph",
+		"across pages. Figure five.",
+	}
+	for i, p := range pages {
+		if p.Number != i+1 {
+			t.Errorf("page %d numbered %d", i+1, p.Number)
+		}
+		if p.Text != want[i] {
+			t.Errorf("page %d = %q\nwant     %q", i+1, p.Text, want[i])
+		}
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	md := Markdown([]Page{{Number: 1, Text: "One."}, {Number: 2}, {Number: 3, Text: "Three."}})
+	want := "<!-- page 1 -->\n\nOne.\n\n<!-- page 2 -->\n\n\n<!-- page 3 -->\n\nThree.\n"
+	if md != want {
+		t.Errorf("Markdown = %q\nwant %q", md, want)
+	}
+	if got := StripMarkers(md); strings.Contains(got, "page") || !strings.Contains(got, "Three.") {
+		t.Errorf("StripMarkers = %q", got)
+	}
+}
+
+func TestSplitPages(t *testing.T) {
+	got := SplitPages("one\n\ftwo\n\f")
+	if len(got) != 2 || got[0] != "one\n" || got[1] != "two\n" {
+		t.Errorf("SplitPages = %q", got)
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package refs
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	bibitemPattern = regexp.MustCompile(`\\bibitem\s*`)
+	bibinfoPattern = regexp.MustCompile(`\\bibinfo\s*\{([a-z]+)\}\s*`)
+	newblockSplit  = regexp.MustCompile(`\\newblock\b`)
+	endBibPattern  = regexp.MustCompile(`\\end\s*\{thebibliography\}`)
+)
+
+// ParseBBL reads the entries of a .bbl file. Both the thebibliography lists
+// BibTeX writes (\bibitem entries, with \newblock or \bibinfo markup) and
+// biber's \entry records for biblatex are understood.
+func ParseBBL(text string) []Reference {
+	if strings.Contains(text, `\entry{`) || strings.Contains(text, `\entry {`) {
+		return parseBiblatexBBL(text)
+	}
+	if m := endBibPattern.FindStringIndex(text); m != nil {
+		text = text[:m[0]]
+	}
+
+	var refs []Reference
+	locs := bibitemPattern.FindAllStringIndex(text, -1)
+	for i, loc := range locs {
+		end := len(text)
+		if i+1 < len(locs) {
+			end = locs[i+1][0]
+		}
+		item := text[loc[1]:end]
+
+		// \bibitem[label]{key}
+		if _, rest, ok := balanced(item, '[', ']'); ok {
+			item = strings.TrimLeft(rest, " \t\n")
+		}
+		key, rest, ok := balanced(item, '{', '}')
+		if !ok {
+			continue
+		}
+		ref := parseBibitem(stripTeXComments(rest))
+		ref.Key = strings.TrimSpace(key)
+		refs = append(refs, ref)
+	}
+	return refs
+}
+
+// parseBibitem reads the body of one \bibitem.
+func parseBibitem(body string) Reference {
+	ref := Reference{Raw: detex(body)}
+
+	// Styles such as revtex's tag every field with \bibinfo.
+	if fields := bibinfoFields(body); len(fields) > 0 {
+		ref.Title = cleanField(detex(fields["title"]))
+		if ref.Title == "" {
+			ref.Title = cleanField(detex(fields["booktitle"]))
+		}
+		for _, a := range bibinfoAuthors(body) {
+			ref.Authors = append(ref.Authors, cleanField(detex(a)))
+		}
+		ref.Year = detex(fields["year"])
+		ref.DOI = cleanDOI(detex(fields["doi"]))
+		if id := fields["eprint"]; id != "" {
+			ref.ArxivID = normalizeID(detex(id))
+		}
+		fillIDs(&ref, ref.Raw)
+		return ref
+	}
+
+	// Otherwise blocks separated by \newblock hold the authors, the title
+	// and the venue, in that order.
+	blocks := newblockSplit.Split(body, -1)
+	if len(blocks) >= 2 {
+		ref.Authors = splitAuthors(detex(blocks[0]))
+		ref.Title = cleanField(detex(blocks[1]))
+	} else {
+		ref.Authors, ref.Title = splitCitation(ref.Raw)
+	}
+	fillIDs(&ref, ref.Raw)
+	return ref
+}
+
+// bibinfoFields collects \bibinfo{field}{value} pairs. The first value of a
+// field wins.
+func bibinfoFields(body string) map[string]string {
+	fields := make(map[string]string)
+	for _, m := range bibinfoPattern.FindAllStringSubmatchIndex(body, -1) {
+		name := body[m[2]:m[3]]
+		value, _, ok := balanced(body[m[1]:], '{', '}')
+		if !ok {
+			continue
+		}
+		if _, seen := fields[name]; !seen {
+			fields[name] = value
+		}
+	}
+	return fields
+}
+
+// bibinfoAuthors returns the \bibinfo{author}{...} values in order.
+func bibinfoAuthors(body string) []string {
+	var authors []string
+	for _, m := range bibinfoPattern.FindAllStringSubmatchIndex(body, -1) {
+		if body[m[2]:m[3]] != "author" {
+			continue
+		}
+		if value, _, ok := balanced(body[m[1]:], '{', '}'); ok {
+			authors = append(authors, value)
+		}
+	}
+	return authors
+}
+
+var (
+	entryPattern   = regexp.MustCompile(`\\entry\s*\{([^}]*)\}\s*\{([^}]*)\}`)
+	fieldPattern   = regexp.MustCompile(`\\(?:field|strng)\s*\{([a-z]+)\}\s*`)
+	verbPattern    = regexp.MustCompile(`(?s)\\verb\s*\{([a-z]+)\}\s*\\verb\s+(.*?)\s*\\endverb`)
+	namePattern    = regexp.MustCompile(`\\name\s*\{author\}\s*\{\d+\}\s*\{[^}]*\}\s*`)
+	familyPattern  = regexp.MustCompile(`\bfamily=\{((?:[^{}]|\{[^{}]*\})*)\}`)
+	givenPattern   = regexp.MustCompile(`\bgiven=\{((?:[^{}]|\{[^{}]*\})*)\}`)
+	endEntrySplit  = regexp.MustCompile(`\\endentry\b`)
+	namePartSplits = regexp.MustCompile(`\{\{(?:hash|un|uniquename)=`)
+)
+
+// parseBiblatexBBL reads the \entry records biber writes for biblatex.
+func parseBiblatexBBL(text string) []Reference {
+	var refs []Reference
+	for _, chunk := range endEntrySplit.Split(stripTeXComments(text), -1) {
+		m := entryPattern.FindStringSubmatchIndex(chunk)
+		if m == nil {
+			continue
+		}
+		entry := chunk[m[1]:]
+		ref := Reference{Key: chunk[m[2]:m[3]]}
+
+		fields := make(map[string]string)
+		for _, f := range fieldPattern.FindAllStringSubmatchIndex(entry, -1) {
+			if value, _, ok := balanced(entry[f[1]:], '{', '}'); ok {
+				fields[entry[f[2]:f[3]]] = value
+			}
+		}
+		for _, v := range verbPattern.FindAllStringSubmatch(entry, -1) {
+			fields[v[1]] = strings.Join(strings.Fields(strings.ReplaceAll(v[2], `\verb`, "")), "")
+		}
+
+		if loc := namePattern.FindStringIndex(entry); loc != nil {
+			if names, _, ok := balanced(entry[loc[1]:], '{', '}'); ok {
+				for _, person := range namePartSplits.Split(names, -1)[1:] {
+					var family, given string
+					if f := familyPattern.FindStringSubmatch(person); f != nil {
+						family = detex(f[1])
+					}
+					if g := givenPattern.FindStringSubmatch(person); g != nil {
+						given = detex(g[1])
+					}
+					if name := strings.TrimSpace(given + " " + family); name != "" {
+						ref.Authors = append(ref.Authors, name)
+					}
+				}
+			}
+		}
+
+		ref.Title = cleanField(detex(fields["title"]))
+		ref.Year = fields["year"]
+		if ref.Year == "" && len(fields["date"]) >= 4 {
+			ref.Year = fields["date"][:4]
+		}
+		ref.DOI = cleanDOI(fields["doi"])
+		if strings.EqualFold(fields["eprinttype"], "arxiv") || strings.EqualFold(fields["archiveprefix"], "arxiv") {
+			ref.ArxivID = normalizeID(fields["eprint"])
+		}
+
+		var raw []string
+		if len(ref.Authors) > 0 {
+			raw = append(raw, strings.Join(ref.Authors, ", "))
+		}
+		for _, name := range []string{"title", "journaltitle", "booktitle", "year", "url"} {
+			if v := detex(fields[name]); v != "" {
+				raw = append(raw, v)
+			}
+		}
+		ref.Raw = strings.Join(raw, ". ")
+		fillIDs(&ref, ref.Raw)
+		refs = append(refs, ref)
+	}
+	return refs
+}
+
+// stripTeXComments removes % comments, which .bbl files use to break lines.
+func stripTeXComments(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		for j := 0; j < len(line); j++ {
+			if line[j] == '\\' {
+				j++
+				continue
+			}
+			if line[j] == '%' {
+				lines[i] = line[:j]
+				break
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,284 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package refs
+
+import (
+	"regexp"
+	"strings"
+)
+
+// BibEntry is one record of a BibTeX database, with lower-case field names.
+type BibEntry struct {
+	Type   string
+	Key    string
+	Fields map[string]string
+}
+
+// months are the macros BibTeX predefines.
+var months = map[string]string{
+	"jan": "January", "feb": "February", "mar": "March", "apr": "April",
+	"may": "May", "jun": "June", "jul": "July", "aug": "August",
+	"sep": "September", "oct": "October", "nov": "November", "dec": "December",
+}
+
+// ParseBibFile reads the records of a BibTeX database. @string macros are
+// expanded; @comment and @preamble are skipped, as is anything malformed.
+func ParseBibFile(text string) []BibEntry {
+	macros := make(map[string]string, len(months))
+	for k, v := range months {
+		macros[k] = v
+	}
+
+	var entries []BibEntry
+	for {
+		at := strings.IndexByte(text, '@')
+		if at < 0 {
+			return entries
+		}
+		text = text[at+1:]
+
+		typ := identifier(text)
+		text = strings.TrimLeft(text[len(typ):], " \t\r\n")
+		if text == "" || (text[0] != '{' && text[0] != '(') {
+			continue
+		}
+		closeCh := byte('}')
+		if text[0] == '(' {
+			closeCh = ')'
+		}
+		body, rest, ok := balanced(text, text[0], closeCh)
+		if !ok {
+			return entries
+		}
+		text = rest
+
+		switch typ = strings.ToLower(typ); typ {
+		case "comment", "preamble":
+			continue
+		case "string":
+			for name, value := range parseBibFields(body, macros) {
+				macros[name] = value
+			}
+			continue
+		}
+
+		comma := strings.IndexByte(body, ',')
+		if comma < 0 {
+			continue
+		}
+		entries = append(entries, BibEntry{
+			Type:   typ,
+			Key:    strings.TrimSpace(body[:comma]),
+			Fields: parseBibFields(body[comma+1:], macros),
+		})
+	}
+}
+
+// identifier returns the leading run of name characters in s.
+func identifier(s string) string {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || strings.IndexByte("_-:.+/'", c) >= 0) {
+			return s[:i]
+		}
+	}
+	return s
+}
+
+// parseBibFields reads "name = value, ..." where each value is a {group},
+// a "string", a number or a macro, possibly joined with #.
+func parseBibFields(s string, macros map[string]string) map[string]string {
+	fields := make(map[string]string)
+	for {
+		s = strings.TrimLeft(s, " \t\r\n,")
+		name := identifier(s)
+		if name == "" {
+			return fields
+		}
+		s = strings.TrimLeft(s[len(name):], " \t\r\n")
+		if s == "" || s[0] != '=' {
+			return fields
+		}
+		s = s[1:]
+
+		var value strings.Builder
+		for {
+			s = strings.TrimLeft(s, " \t\r\n")
+			if s == "" {
+				break
+			}
+			switch s[0] {
+			case '{':
+				part, rest, ok := balanced(s, '{', '}')
+				if !ok {
+					return fields
+				}
+				value.WriteString(part)
+				s = rest
+			case '"':
+				end := closingQuote(s)
+				if end < 0 {
+					return fields
+				}
+				value.WriteString(s[1:end])
+				s = s[end+1:]
+			default:
+				word := identifier(s)
+				if word == "" {
+					return fields
+				}
+				s = s[len(word):]
+				if v, ok := macros[strings.ToLower(word)]; ok {
+					word = v
+				}
+				value.WriteString(word)
+			}
+			s = strings.TrimLeft(s, " \t\r\n")
+			if s == "" || s[0] != '#' {
+				break
+			}
+			s = s[1:]
+		}
+		fields[strings.ToLower(name)] = strings.Join(strings.Fields(value.String()), " ")
+	}
+}
+
+// closingQuote returns the index of the quote ending the string at s[0],
+// skipping quotes inside braces.
+func closingQuote(s string) int {
+	depth := 0
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case '"':
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// ParseBib reads the references in a BibTeX database. If cited is not nil,
+// only the entries whose keys it contains are returned, in database order.
+func ParseBib(text string, cited map[string]bool) []Reference {
+	var refs []Reference
+	for _, e := range ParseBibFile(text) {
+		if cited != nil && !cited[e.Key] {
+			continue
+		}
+		refs = append(refs, e.Reference())
+	}
+	return refs
+}
+
+// Reference converts a BibTeX record into a Reference.
+func (e BibEntry) Reference() Reference {
+	f := e.Fields
+	ref := Reference{Key: e.Key, Title: cleanField(detex(f["title"]))}
+	if ref.Title == "" {
+		ref.Title = cleanField(detex(f["booktitle"]))
+	}
+	ref.Authors = splitBibNames(f["author"])
+	ref.Year = f["year"]
+	if ref.Year == "" && len(f["date"]) >= 4 {
+		ref.Year = f["date"][:4]
+	}
+	ref.DOI = cleanDOI(detex(f["doi"]))
+
+	prefix := strings.ToLower(f["archiveprefix"] + f["eprinttype"])
+	if f["eprint"] != "" && (prefix == "arxiv" || prefix == "") {
+		ref.ArxivID = normalizeID(f["eprint"])
+	}
+
+	var raw []string
+	if len(ref.Authors) > 0 {
+		raw = append(raw, strings.Join(ref.Authors, ", "))
+	}
+	for _, name := range []string{"title", "journal", "booktitle", "publisher", "year", "note", "url", "howpublished"} {
+		if v := detex(f[name]); v != "" {
+			raw = append(raw, v)
+		}
+	}
+	ref.Raw = strings.Join(raw, ". ")
+	fillIDs(&ref, ref.Raw)
+	return ref
+}
+
+var bibAndSplit = regexp.MustCompile(`\s+and\s+`)
+
+// splitBibNames splits a BibTeX author field and writes each name as
+// "First Last".
+func splitBibNames(field string) []string {
+	if strings.TrimSpace(field) == "" {
+		return nil
+	}
+	var names []string
+	for _, name := range splitTopLevel(field) {
+		name = detex(name)
+		if strings.EqualFold(name, "others") {
+			continue
+		}
+		// "Last, Jr, First" and "Last, First" put the surname first.
+		if parts := strings.Split(name, ","); len(parts) > 1 {
+			first := strings.TrimSpace(parts[len(parts)-1])
+			last := strings.TrimSpace(strings.Join(parts[:len(parts)-1], ","))
+			name = strings.TrimSpace(first + " " + last)
+		}
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// splitTopLevel splits on "and" outside braces, so that {Barnes and Noble}
+// stays one name.
+func splitTopLevel(field string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i := 0; i < len(field); i++ {
+		switch field[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case ' ', '\t', '\n':
+			if depth != 0 {
+				continue
+			}
+			if loc := bibAndSplit.FindStringIndex(field[i:]); loc != nil && loc[0] == 0 {
+				parts = append(parts, field[start:i])
+				start = i + loc[1]
+				i = start - 1
+			}
+		}
+	}
+	return append(parts, field[start:])
+}
+
+var citePattern = regexp.MustCompile(`\\[A-Za-z]*cite[A-Za-z]*\*?\s*(?:\[[^\]]*\]\s*){0,2}\{([^}]*)\}`)
+
+// CitedKeys returns the citation keys used in LaTeX text, and whether the
+// text cites the whole database with \nocite{*}. Commented-out citations do
+// not count.
+func CitedKeys(tex string) (keys map[string]bool, all bool) {
+	keys = make(map[string]bool)
+	for _, m := range citePattern.FindAllStringSubmatch(stripTeXComments(tex), -1) {
+		for _, k := range strings.Split(m[1], ",") {
+			k = strings.TrimSpace(k)
+			if k == "*" {
+				all = true
+			} else if k != "" {
+				keys[k] = true
+			}
+		}
+	}
+	return keys, all
+}
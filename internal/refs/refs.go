@@ -0,0 +1,213 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+// Package refs extracts the reference list of a paper into structured
+// entries.
+//
+// References are read from the sources a paper can come with, best first:
+// the .bbl file BibTeX or biber produced for the LaTeX source (ParseBBL), the
+// .bib database itself (ParseBib), or the plain text of the PDF (ParseText).
+// Every entry records whatever could be recognized of its authors, title,
+// year, arXiv ID and DOI, along with its raw text.
+package refs
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/mtreilly/arc-arxiv/internal/arxiv"
+)
+
+// Where a reference list was read from.
+const (
+	SourceBBL  = "bbl"
+	SourceBib  = "bib"
+	SourceText = "text"
+)
+
+// Reference is one entry of a paper's reference list. Library is left for
+// the caller to fill in with the arXiv ID of the matching library paper.
+type Reference struct {
+	Key     string   `yaml:"key,omitempty" json:"key,omitempty"`
+	Title   string   `yaml:"title,omitempty" json:"title,omitempty"`
+	Authors []string `yaml:"authors,omitempty" json:"authors,omitempty"`
+	Year    string   `yaml:"year,omitempty" json:"year,omitempty"`
+	ArxivID string   `yaml:"arxiv_id,omitempty" json:"arxiv_id,omitempty"`
+	DOI     string   `yaml:"doi,omitempty" json:"doi,omitempty"`
+	Library string   `yaml:"library,omitempty" json:"library,omitempty"`
+	Raw     string   `yaml:"raw,omitempty" json:"raw,omitempty"`
+}
+
+var (
+	// arxivRefPattern finds arXiv identifiers written with an arXiv prefix or
+	// as an arxiv.org link: "arXiv:1706.03762v5", "arXiv preprint
+	// arXiv:1706.03762", "arxiv.org/abs/hep-th/9711200".
+	arxivRefPattern = regexp.MustCompile(`(?i)(?:arxiv(?:\s+preprint)?\s*:?\s*(?:arxiv\s*:\s*)?|arxiv\.org/(?:abs|pdf)/)((?:\d{4}\.\d{4,5}|[a-z]+(?:-[a-z]+)?(?:\.[a-z]{2})?/\d{7})(?:v\d+)?)`)
+
+	// oldArxivPattern finds old-style identifiers without a prefix, which
+	// bibliographies in physics and mathematics often cite bare.
+	oldArxivPattern = regexp.MustCompile(`\b((?:hep-th|hep-ph|hep-lat|hep-ex|gr-qc|astro-ph|cond-mat|quant-ph|math-ph|nucl-th|nucl-ex|physics|math|nlin|cs|q-bio|q-alg|alg-geom|dg-ga|funct-an|chao-dyn|solv-int|patt-sol|adap-org|comp-gas|chem-ph|atom-ph|supr-con|mtrl-th|cmp-lg)(?:\.[A-Z]{2})?/\d{7})(?:v\d+)?\b`)
+
+	// doiPattern finds a DOI, bare or inside a doi.org link.
+	doiPattern = regexp.MustCompile(`\b(10\.\d{4,9}/[^\s"<>{}]+)`)
+
+	yearPattern = regexp.MustCompile(`\b(1[89]\d{2}|20\d{2})[a-z]?\b`)
+)
+
+// FindArxivID returns the first arXiv identifier in s, without a version
+// suffix, or "" if there is none.
+func FindArxivID(s string) string {
+	if m := arxivRefPattern.FindStringSubmatch(s); m != nil {
+		if id := normalizeID(m[1]); id != "" {
+			return id
+		}
+	}
+	if m := oldArxivPattern.FindStringSubmatch(s); m != nil {
+		return normalizeID(m[1])
+	}
+	return ""
+}
+
+// normalizeID turns a matched identifier into the library's form: no
+// version, and no subject class in old-style IDs ("math.AG/0101001" is
+// "math/0101001").
+func normalizeID(s string) string {
+	s = strings.ToLower(s)
+	if slash := strings.Index(s, "/"); slash > 0 {
+		if dot := strings.Index(s[:slash], "."); dot > 0 {
+			s = s[:dot] + s[slash:]
+		}
+	}
+	id, err := arxiv.NormalizeArxivID(s)
+	if err != nil {
+		return ""
+	}
+	base, _ := arxiv.SplitVersion(id)
+	return base
+}
+
+// FindDOI returns the first DOI in s, or "" if there is none.
+func FindDOI(s string) string {
+	m := doiPattern.FindStringSubmatch(s)
+	if m == nil {
+		return ""
+	}
+	return cleanDOI(m[1])
+}
+
+func cleanDOI(doi string) string {
+	doi = strings.TrimSpace(doi)
+	doi = strings.TrimPrefix(doi, "https://doi.org/")
+	doi = strings.TrimPrefix(doi, "http://dx.doi.org/")
+	doi = strings.TrimPrefix(doi, "doi:")
+	return strings.TrimRight(doi, ".,;:)]")
+}
+
+// findYear returns the first plausible publication year in s, ignoring the
+// digits of arXiv IDs and DOIs.
+func findYear(s string) string {
+	s = arxivRefPattern.ReplaceAllString(s, "")
+	s = doiPattern.ReplaceAllString(s, "")
+	if m := yearPattern.FindStringSubmatch(s); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// fillIDs sets the arXiv ID, DOI and year of ref from text when they are not
+// known yet.
+func fillIDs(ref *Reference, text string) {
+	if ref.ArxivID == "" {
+		ref.ArxivID = FindArxivID(text)
+	}
+	if ref.DOI == "" {
+		ref.DOI = FindDOI(text)
+	}
+	if ref.Year == "" {
+		ref.Year = findYear(text)
+	}
+}
+
+// NormalizeTitle reduces a title to lower-case words so that titles can be
+// compared across sources.
+func NormalizeTitle(title string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range strings.ToLower(title) {
+		switch {
+		case unicode.IsLetter(r), unicode.IsDigit(r):
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			b.WriteRune(r)
+			space = false
+		default:
+			space = true
+		}
+	}
+	return b.String()
+}
+
+var etAlPattern = regexp.MustCompile(`(?i),?\s*\bet\s+al\b\.?`)
+
+// splitAuthors splits an author list written out in prose: "A. Vaswani,
+// N. Shazeer, and I. Polosukhin", "Vaswani, A., Shazeer, N. & Polosukhin,
+// I." or "Vaswani A, Shazeer N". "et al." and "others" are dropped.
+func splitAuthors(s string) []string {
+	s = strings.TrimSpace(etAlPattern.ReplaceAllString(s, ""))
+	if fields := strings.Fields(s); len(fields) > 0 && !isInitials(fields[len(fields)-1]) {
+		s = strings.TrimSuffix(s, ".")
+	}
+	s = strings.NewReplacer(" & ", " and ", ", and ", " and ", ",and ", " and ", "; ", ", ").Replace(s)
+	var pieces []string
+	for _, part := range strings.Split(s, " and ") {
+		for _, p := range strings.Split(part, ",") {
+			if p = strings.TrimSpace(p); p != "" {
+				pieces = append(pieces, p)
+			}
+		}
+	}
+
+	var authors []string
+	for i := 0; i < len(pieces); i++ {
+		p := pieces[i]
+		// "Vaswani, A." is one author written surname first.
+		if i+1 < len(pieces) && !isInitials(p) && isInitials(pieces[i+1]) {
+			p = pieces[i+1] + " " + p
+			i++
+		}
+		if strings.EqualFold(p, "others") {
+			continue
+		}
+		authors = append(authors, p)
+	}
+	return authors
+}
+
+// isInitials reports whether s consists only of initials such as "A.",
+// "A. B." or "J.-P.".
+func isInitials(s string) bool {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return false
+	}
+	for _, f := range fields {
+		for _, part := range strings.Split(f, "-") {
+			part = strings.TrimSuffix(part, ".")
+			if len([]rune(part)) > 2 || part == "" || strings.ToUpper(part) != part {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// cleanField tidies a title or author text: surrounding punctuation and
+// repeated whitespace go.
+func cleanField(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	s = strings.Trim(s, " ,;:")
+	s = strings.TrimSuffix(s, ".")
+	return strings.TrimSpace(strings.Trim(s, `"“”`))
+}
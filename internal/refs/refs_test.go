@@ -0,0 +1,250 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package refs
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFindIDs(t *testing.T) {
+	tests := []struct {
+		text, arxiv, doi string
+	}{
+		{"arXiv preprint arXiv:1706.03762, 2017.", "1706.03762", ""},
+		{"CoRR, abs/1810.04805v2 https://arxiv.org/abs/1810.04805v2", "1810.04805", ""},
+		{"Nucl. Phys. B 500 (1997), hep-th/9711200.", "hep-th/9711200", ""},
+		{"arXiv:math.AG/0101001", "math/0101001", ""},
+		{"Nature 521, 436 (2015). doi:10.1038/nature14539.", "", "10.1038/nature14539"},
+		{"https://doi.org/10.1103/PhysRevLett.116.061102)", "", "10.1103/PhysRevLett.116.061102"},
+		{"Proceedings 2019, pages 1234.5678", "", ""},
+	}
+	for _, tt := range tests {
+		if got := FindArxivID(tt.text); got != tt.arxiv {
+			t.Errorf("FindArxivID(%q) = %q, want %q", tt.text, got, tt.arxiv)
+		}
+		if got := FindDOI(tt.text); got != tt.doi {
+			t.Errorf("FindDOI(%q) = %q, want %q", tt.text, got, tt.doi)
+		}
+	}
+}
+
+func TestSplitAuthors(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"A. Vaswani, N. Shazeer, and I. Polosukhin", []string{"A. Vaswani", "N. Shazeer", "I. Polosukhin"}},
+		{"Vaswani, A., Shazeer, N. & Polosukhin, I.", []string{"A. Vaswani", "N. Shazeer", "I. Polosukhin"}},
+		{"Ashish Vaswani and Noam Shazeer", []string{"Ashish Vaswani", "Noam Shazeer"}},
+		{"J.-P. Serre et al.", []string{"J.-P. Serre"}},
+	}
+	for _, tt := range tests {
+		if got := splitAuthors(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitAuthors(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseBBL(t *testing.T) {
+	t.Run("newblock", func(t *testing.T) {
+		bbl := `\begin{thebibliography}{10}
+\providecommand{\natexlab}[1]{#1}
+
+\bibitem[{Vaswani et~al.(2017)}]{vaswani2017}
+Ashish Vaswani, Noam Shazeer, and Illia Polosukhin.
+\newblock Attention is all you need.
+\newblock In \emph{Advances in Neural Information Processing Systems}, 2017.
+\newblock URL \url{http://arxiv.org/abs/1706.03762}.
+
+\bibitem{goedel}
+Kurt G{\"o}del.
+\newblock {\"U}ber formal unentscheidbare {S}{\"a}tze.
+\newblock \emph{Monatshefte f\"ur Mathematik}, 38:\penalty0 173--198, 1931.
+\newblock \doi{10.1007/BF01700692}.
+
+\end{thebibliography}`
+		refs := ParseBBL(bbl)
+		if len(refs) != 2 {
+			t.Fatalf("got %d refs, want 2", len(refs))
+		}
+		want := Reference{
+			Key:     "vaswani2017",
+			Title:   "Attention is all you need",
+			Authors: []string{"Ashish Vaswani", "Noam Shazeer", "Illia Polosukhin"},
+			Year:    "2017",
+			ArxivID: "1706.03762",
+		}
+		got := refs[0]
+		got.Raw = ""
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("ref 0 = %+v\nwant %+v", got, want)
+		}
+		if refs[1].Title != "Über formal unentscheidbare Sätze" || refs[1].DOI != "10.1007/BF01700692" || refs[1].Year != "1931" {
+			t.Errorf("ref 1 = %+v", refs[1])
+		}
+		if !strings.Contains(refs[1].Raw, "Monatshefte für Mathematik") {
+			t.Errorf("raw = %q", refs[1].Raw)
+		}
+	})
+
+	t.Run("bibinfo", func(t *testing.T) {
+		bbl := `\bibitem [{\citenamefont {Abbott}\ \emph {et~al.}(2016)}]{ligo}%
+  \BibitemOpen
+  \bibfield  {author} {\bibinfo {author} {\bibfnamefont {B.~P.}\ \bibnamefont {Abbott}}, \bibinfo {author} {\bibfnamefont {R.}~\bibnamefont {Abbott}}, \emph {et~al.}},\ }\href {\doibase 10.1103/PhysRevLett.116.061102} {\bibfield  {journal} {\bibinfo  {journal} {Phys. Rev. Lett.}\ }\textbf {\bibinfo {volume} {116}},\ \bibinfo {pages} {061102} (\bibinfo {year} {2016})},\ \Eprint {http://arxiv.org/abs/1602.03837} {arXiv:1602.03837} \BibitemShut {NoStop}%
+`
+		refs := ParseBBL(bbl)
+		if len(refs) != 1 {
+			t.Fatalf("got %d refs, want 1", len(refs))
+		}
+		r := refs[0]
+		if r.Key != "ligo" || r.Year != "2016" || r.ArxivID != "1602.03837" || r.DOI != "10.1103/PhysRevLett.116.061102" {
+			t.Errorf("ref = %+v", r)
+		}
+		if !reflect.DeepEqual(r.Authors, []string{"B. P. Abbott", "R. Abbott"}) {
+			t.Errorf("authors = %q", r.Authors)
+		}
+	})
+
+	t.Run("biblatex", func(t *testing.T) {
+		bbl := `\refsection{0}
+  \datalist[entry]{nty/global//global/global}
+    \entry{devlin2019}{inproceedings}{}
+      \name{author}{2}{}{%
+        {{hash=a1}{%
+           family={Devlin},
+           familyi={D\bibinitperiod},
+           given={Jacob},
+           giveni={J\bibinitperiod}}}%
+        {{un=0,uniquepart=base,hash=b2}{%
+           family={Chang},
+           familyi={C\bibinitperiod},
+           given={Ming-Wei},
+           giveni={M\bibinithyphendelim W\bibinitperiod}}}%
+      }
+      \field{title}{{BERT}: Pre-training of Deep Bidirectional Transformers}
+      \field{year}{2019}
+      \field{eprinttype}{arXiv}
+      \field{eprint}{1810.04805}
+      \verb{doi}
+      \verb 10.18653/v1/N19-1423
+      \endverb
+    \endentry
+  \enddatalist
+\endrefsection`
+		refs := ParseBBL(bbl)
+		if len(refs) != 1 {
+			t.Fatalf("got %d refs, want 1", len(refs))
+		}
+		want := Reference{
+			Key:     "devlin2019",
+			Title:   "BERT: Pre-training of Deep Bidirectional Transformers",
+			Authors: []string{"Jacob Devlin", "Ming-Wei Chang"},
+			Year:    "2019",
+			ArxivID: "1810.04805",
+			DOI:     "10.18653/v1/N19-1423",
+		}
+		got := refs[0]
+		got.Raw = ""
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("ref = %+v\nwant %+v", got, want)
+		}
+	})
+}
+
+func TestParseBib(t *testing.T) {
+	bib := `@string{nips = "Advances in Neural Information Processing Systems"}
+% A comment with an @ sign
+@inproceedings{vaswani2017,
+  title     = {Attention is All you {Need}},
+  author    = {Vaswani, Ashish and Shazeer, Noam and others},
+  booktitle = nips # " 30",
+  year      = 2017,
+  eprint    = {1706.03762},
+  archivePrefix = {arXiv},
+}
+@Article(uncited,
+  title = "Not cited",
+  author = "Nobody",
+  doi = {10.1000/xyz}
+)`
+	refs := ParseBib(bib, map[string]bool{"vaswani2017": true})
+	if len(refs) != 1 {
+		t.Fatalf("got %d refs, want 1", len(refs))
+	}
+	r := refs[0]
+	if r.Title != "Attention is All you Need" || r.Year != "2017" || r.ArxivID != "1706.03762" {
+		t.Errorf("ref = %+v", r)
+	}
+	if !reflect.DeepEqual(r.Authors, []string{"Ashish Vaswani", "Noam Shazeer"}) {
+		t.Errorf("authors = %q", r.Authors)
+	}
+	if !strings.Contains(r.Raw, "Advances in Neural Information Processing Systems 30") {
+		t.Errorf("raw = %q", r.Raw)
+	}
+
+	all := ParseBib(bib, nil)
+	if len(all) != 2 || all[1].Key != "uncited" || all[1].DOI != "10.1000/xyz" {
+		t.Errorf("all = %+v", all)
+	}
+}
+
+func TestCitedKeys(t *testing.T) {
+	keys, all := CitedKeys(`As shown \citep[see][p.~3]{a, b} and \citet{c}. % \cite{d}
+\nocite{*}`)
+	for _, k := range []string{"a", "b", "c"} {
+		if !keys[k] {
+			t.Errorf("missing key %q", k)
+		}
+	}
+	if keys["d"] {
+		t.Error("commented-out citation counted")
+	}
+	if !all {
+		t.Error("expected \\nocite{*} to be recognized")
+	}
+}
+
+func TestParseText(t *testing.T) {
+	text := `Conclusion
+
+We conclude.
+
+References
+
+[1] A. Vaswani, N. Shazeer, and I. Polosukhin. Attention is all you
+need. In NeurIPS, 2017. arXiv:1706.03762.
+[2] LeCun, Y., Bengio, Y. & Hinton, G. (2015). Deep learning. Nature 521,
+436–444. doi:10.1038/nature14539
+[3] K. He, X. Zhang, S. Ren, and J. Sun. “Deep residual learning for image
+recognition,” in CVPR, 2016.
+
+A Appendix
+
+[4] Not a reference.`
+
+	refs := ParseText(text)
+	if len(refs) != 3 {
+		t.Fatalf("got %d refs, want 3: %+v", len(refs), refs)
+	}
+	tests := []struct {
+		title, year, arxiv, doi string
+		authors                 int
+	}{
+		{"Attention is all you need", "2017", "1706.03762", "", 3},
+		{"Deep learning", "2015", "", "10.1038/nature14539", 3},
+		{"Deep residual learning for image recognition", "2016", "", "", 4},
+	}
+	for i, tt := range tests {
+		r := refs[i]
+		if r.Title != tt.title || r.Year != tt.year || r.ArxivID != tt.arxiv || r.DOI != tt.doi || len(r.Authors) != tt.authors {
+			t.Errorf("ref %d = %+v", i, r)
+		}
+	}
+
+	if refs := ParseText("No reference section here."); refs != nil {
+		t.Errorf("expected no refs, got %+v", refs)
+	}
+}
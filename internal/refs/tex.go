@@ -0,0 +1,97 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package refs
+
+import (
+	"regexp"
+	"strings"
+)
+
+// accents maps TeX accent commands and the letters they commonly apply to
+// onto precomposed characters.
+var accents = map[byte]map[byte]string{
+	'"':  {'a': "ä", 'e': "ë", 'i': "ï", 'o': "ö", 'u': "ü", 'y': "ÿ", 'A': "Ä", 'E': "Ë", 'I': "Ï", 'O': "Ö", 'U': "Ü"},
+	'\'': {'a': "á", 'c': "ć", 'e': "é", 'i': "í", 'n': "ń", 'o': "ó", 's': "ś", 'u': "ú", 'y': "ý", 'z': "ź", 'A': "Á", 'C': "Ć", 'E': "É", 'I': "Í", 'O': "Ó", 'S': "Ś", 'U': "Ú", 'Z': "Ź"},
+	'`':  {'a': "à", 'e': "è", 'i': "ì", 'o': "ò", 'u': "ù", 'A': "À", 'E': "È", 'O': "Ò", 'U': "Ù"},
+	'^':  {'a': "â", 'e': "ê", 'i': "î", 'o': "ô", 'u': "û", 'A': "Â", 'E': "Ê", 'I': "Î", 'O': "Ô", 'U': "Û"},
+	'~':  {'a': "ã", 'n': "ñ", 'o': "õ", 'A': "Ã", 'N': "Ñ", 'O': "Õ"},
+	'c':  {'c': "ç", 's': "ş", 'C': "Ç", 'S': "Ş"},
+	'v':  {'c': "č", 'e': "ě", 'n': "ň", 'r': "ř", 's': "š", 'z': "ž", 'C': "Č", 'R': "Ř", 'S': "Š", 'Z': "Ž"},
+	'H':  {'o': "ő", 'u': "ű", 'O': "Ő", 'U': "Ű"},
+	'u':  {'a': "ă", 'g': "ğ", 'A': "Ă"},
+	'.':  {'z': "ż", 'Z': "Ż"},
+	'=':  {'a': "ā", 'e': "ē", 'o': "ō"},
+}
+
+// letterMacros are TeX commands that stand for a single character.
+var letterMacros = map[string]string{
+	"ss": "ß", "o": "ø", "O": "Ø", "aa": "å", "AA": "Å", "ae": "æ", "AE": "Æ",
+	"oe": "œ", "OE": "Œ", "l": "ł", "L": "Ł", "i": "ı", "j": "ȷ",
+	"&": "&", "%": "%", "_": "_", "$": "$", "#": "#", "{": "", "}": "",
+	"textendash": "–", "textemdash": "—", "ldots": "…", "dots": "…",
+}
+
+var (
+	accentPattern  = regexp.MustCompile(`\\(["'` + "`" + `^~=.]|[cvHu](?:\s+|\s*\{))\s*\{?\\?([A-Za-z])\}?`)
+	macroPattern   = regexp.MustCompile(`\\([A-Za-z]+|[&%_$#{}])\s*`)
+	hrefPattern    = regexp.MustCompile(`\\href\s*\{([^{}]*)\}\s*\{([^{}]*)\}`)
+	urlPattern     = regexp.MustCompile(`\\(?:url|path|doi|eprint)\s*\{([^{}]*)\}`)
+	dropArgPattern = regexp.MustCompile(`\\(?:natexlab|bibfnamefont|bibnamefont|bibinfo\s*\{[a-z]+\})\s*`)
+	dropPattern    = regexp.MustCompile(`\\(?:newblock|penalty-?\d*|BibitemOpen|BibitemShut\s*\{[A-Za-z]*\}|EOS|showISSN\s*\{[^{}]*\}|showISBNx\s*\{[^{}]*\}|showURL|showDOI|showeprint(?:\s*\[[^\]]*\])?|selectlanguage\s*\{[a-z]*\}|bibAnnoteFile\s*\{[^{}]*\}|bibAnnote\s*\{[^{}]*\}\s*\{[^{}]*\}|bibfield\s*\{[a-z]+\}|Eprint|enquote|protect|relax|em|it|bf|sc|tt|rm|sl|sf|upshape|itshape|bfseries|scshape|normalfont)\b\s*`)
+)
+
+// detex turns a fragment of LaTeX into plain text: accents become
+// characters, links their text, formatting commands disappear and the
+// arguments of other commands are kept.
+func detex(s string) string {
+	s = strings.NewReplacer("\\\\", " ", "\\ ", " ", "\\,", " ").Replace(s)
+	s = accentPattern.ReplaceAllStringFunc(s, func(m string) string {
+		sub := accentPattern.FindStringSubmatch(m)
+		accent := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(sub[1]), "{"))
+		if letters, ok := accents[accent[0]]; ok {
+			if c, ok := letters[sub[2][0]]; ok {
+				return c
+			}
+		}
+		return sub[2]
+	})
+	s = hrefPattern.ReplaceAllString(s, "$2 $1")
+	s = urlPattern.ReplaceAllString(s, "$1")
+	s = dropPattern.ReplaceAllString(s, "")
+	s = dropArgPattern.ReplaceAllString(s, "")
+	s = macroPattern.ReplaceAllStringFunc(s, func(m string) string {
+		name := strings.TrimSpace(m)[1:]
+		if c, ok := letterMacros[name]; ok {
+			return c
+		}
+		// Unknown command: drop it and keep its argument, if any.
+		return ""
+	})
+	s = strings.NewReplacer("~", " ", "{", "", "}", "", "$", "", "---", "—", "--", "–", "``", "“", "''", "”").Replace(s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// balanced returns the contents of the group starting at s[0], which must be
+// open ("{" or "["), and the rest of s after the matching close. ok is false
+// if the group is not closed.
+func balanced(s string, open, close byte) (inner, rest string, ok bool) {
+	if s == "" || s[0] != open {
+		return "", s, false
+	}
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case open:
+			depth++
+		case close:
+			depth--
+			if depth == 0 {
+				return s[1:i], s[i+1:], true
+			}
+		}
+	}
+	return "", s, false
+}
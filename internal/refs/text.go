@@ -0,0 +1,140 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package refs
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// headingPattern matches the heading of a reference section on a line
+	// of its own, optionally numbered.
+	headingPattern = regexp.MustCompile(`(?im)^[ \t]*(?:\d+\.?|[IVX]+\.)?[ \t]*(references|bibliography|references and notes|literature cited|works cited)[ \t]*$`)
+
+	// sectionEndPattern matches headings that follow the references.
+	sectionEndPattern = regexp.MustCompile(`(?m)^[ \t]*(?:[A-Z](?:\.\d+)?[ \t]+)?(?:Appendix|APPENDIX|Appendices|APPENDICES|Supplementary [Mm]aterial|SUPPLEMENTARY MATERIAL)\b`)
+
+	// Entry labels: "[12]" or "[Vas+17]", and "12." at the start of a line.
+	bracketLabel = regexp.MustCompile(`(?m)^[ \t]*\[[^\]\n]{1,20}\][ \t]*`)
+	numberLabel  = regexp.MustCompile(`(?m)^[ \t]*\d{1,3}\.[ \t]+`)
+
+	// yearInParens ends the author list of author-year styles.
+	yearInParens = regexp.MustCompile(`\((?:1[89]|20)\d{2}[a-z]?\)\.?`)
+
+	quotedTitle = regexp.MustCompile(`[“"]([^”"]{8,})[”"]`)
+)
+
+// ParseText reads the reference list from the plain text of a paper, such
+// as the text extracted from its PDF. It looks for the last "References" or
+// "Bibliography" heading and splits what follows into entries by their
+// labels ("[12]", "12.") or, failing that, by paragraphs.
+func ParseText(text string) []Reference {
+	locs := headingPattern.FindAllStringIndex(text, -1)
+	if locs == nil {
+		return nil
+	}
+	section := text[locs[len(locs)-1][1]:]
+	if m := sectionEndPattern.FindStringIndex(section); m != nil {
+		section = section[:m[0]]
+	}
+
+	var refs []Reference
+	for _, entry := range splitEntries(section) {
+		entry = strings.Join(strings.Fields(entry), " ")
+		if len(entry) < 15 {
+			continue
+		}
+		ref := Reference{Raw: entry}
+		ref.Authors, ref.Title = splitCitation(entry)
+		fillIDs(&ref, entry)
+		refs = append(refs, ref)
+	}
+	return refs
+}
+
+// splitEntries divides a reference section into entries.
+func splitEntries(section string) []string {
+	for _, label := range []*regexp.Regexp{bracketLabel, numberLabel} {
+		locs := label.FindAllStringIndex(section, -1)
+		if len(locs) < 2 {
+			continue
+		}
+		entries := make([]string, 0, len(locs))
+		for i, loc := range locs {
+			end := len(section)
+			if i+1 < len(locs) {
+				end = locs[i+1][0]
+			}
+			entries = append(entries, section[loc[1]:end])
+		}
+		return entries
+	}
+	return strings.Split(section, "\n\n")
+}
+
+// splitCitation separates the authors and title of a formatted citation.
+//
+// Author-year styles close the authors with "(2017)"; numbered styles end
+// them with the first full stop that does not follow an initial, and the
+// title runs to the next one. A quoted title is taken as is.
+func splitCitation(s string) (authors []string, title string) {
+	if m := quotedTitle.FindStringSubmatchIndex(s); m != nil {
+		return splitAuthors(s[:m[0]]), cleanField(s[m[2]:m[3]])
+	}
+	if m := yearInParens.FindStringIndex(s); m != nil && m[0] > 0 {
+		sentences := splitSentences(s[m[1]:])
+		if len(sentences) > 0 {
+			title = cleanField(sentences[0])
+		}
+		return splitAuthors(s[:m[0]]), title
+	}
+
+	sentences := splitSentences(s)
+	if len(sentences) < 2 {
+		return nil, ""
+	}
+	return splitAuthors(sentences[0]), cleanField(sentences[1])
+}
+
+// abbreviations end in a full stop without ending a sentence.
+var abbreviations = map[string]bool{
+	"al": true, "et": true, "eds": true, "ed": true, "proc": true, "vol": true,
+	"no": true, "pp": true, "jr": true, "sr": true, "dr": true, "st": true,
+	"conf": true, "int": true, "j": true, "phys": true, "rev": true, "lett": true,
+}
+
+// splitSentences splits s after ".", "?" or "!" followed by a space, except
+// after initials and common abbreviations.
+func splitSentences(s string) []string {
+	var sentences []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != '.' && c != '?' && c != '!' {
+			continue
+		}
+		if i+1 < len(s) && s[i+1] != ' ' {
+			continue
+		}
+		if c == '.' {
+			word := s[strings.LastIndexAny(s[:i], " .-")+1 : i]
+			if len([]rune(word)) == 1 || abbreviations[strings.ToLower(word)] {
+				continue
+			}
+		}
+		end := i + 1
+		if c == '.' {
+			end = i
+		}
+		if sentence := strings.TrimSpace(s[start:end]); sentence != "" {
+			sentences = append(sentences, sentence)
+		}
+		start = i + 1
+	}
+	if rest := strings.TrimSpace(s[start:]); rest != "" {
+		sentences = append(sentences, rest)
+	}
+	return sentences
+}
@@ -0,0 +1,155 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+// Package settings loads arc-arxiv's own configuration file. The shared arc
+// configuration only knows about the research root, so options specific to
+// arc-arxiv live in <research_root>/arc-arxiv.yaml. A missing file is not an
+// error; every option has a default.
+package settings
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the name of the settings file inside the research root.
+const FileName = "arc-arxiv.yaml"
+
+// EnvPath names an environment variable that overrides the settings file
+// location.
+const EnvPath = "ARC_ARXIV_CONFIG"
+
+// Settings holds arc-arxiv's options.
+type Settings struct {
+	Notes     Notes     `yaml:"notes"`
+	Extract   Extract   `yaml:"extract"`
+	OAI       OAI       `yaml:"oai"`
+	RateLimit RateLimit `yaml:"rate_limit"`
+	Cache     Cache     `yaml:"cache"`
+	Client    Client    `yaml:"client"`
+	Fetch     Fetch     `yaml:"fetch"`
+
+	// dir is the directory relative paths in the file are resolved against.
+	dir string
+}
+
+// Notes configures the notes.md template written for each paper.
+type Notes struct {
+	// Template is a text/template file used for every paper, replacing the
+	// built-in template.
+	Template string `yaml:"template,omitempty"`
+	// Categories maps an arXiv category ("hep-th", "cs.LG") or archive
+	// ("cs", "math") to a template file used for papers in it.
+	Categories map[string]string `yaml:"categories,omitempty"`
+}
+
+// Extract configures PDF text extraction.
+type Extract struct {
+	// Backend is "builtin" (the default) or "pdftotext".
+	Backend string `yaml:"backend,omitempty"`
+}
+
+// OAI configures metadata harvesting through OAI-PMH.
+type OAI struct {
+	// BaseURL replaces arXiv's OAI-PMH endpoint, for example with a mirror.
+	BaseURL string `yaml:"base_url,omitempty"`
+}
+
+// RateLimit paces and retries requests to arXiv. Zero values keep the
+// defaults: three seconds between requests and five retries, backing off
+// from two seconds up to five minutes.
+type RateLimit struct {
+	// Interval is the least time between the starts of two requests.
+	Interval time.Duration `yaml:"interval,omitempty"`
+	// MaxRetries is how often a failed request is retried; -1 disables
+	// retries.
+	MaxRetries int `yaml:"max_retries,omitempty"`
+	// Backoff is the pause before the first retry, doubled for each further
+	// one.
+	Backoff time.Duration `yaml:"backoff,omitempty"`
+	// MaxWait caps a single pause, including one the server asks for.
+	MaxWait time.Duration `yaml:"max_wait,omitempty"`
+}
+
+// Cache configures the on-disk cache of arXiv API responses.
+type Cache struct {
+	// TTL maps a request kind ("search", "article", "listing") to how long
+	// its responses are used without asking arXiv again.
+	TTL map[string]time.Duration `yaml:"ttl,omitempty"`
+}
+
+// Client configures how arc-arxiv reaches arXiv: other endpoints, mirrors,
+// a proxy and timeouts. Empty values keep the defaults.
+type Client struct {
+	// APIBaseURL replaces the query API, https://export.arxiv.org/api/query.
+	APIBaseURL string `yaml:"api_base_url,omitempty"`
+	// DownloadBaseURL replaces https://arxiv.org for PDFs and sources.
+	DownloadBaseURL string `yaml:"download_base_url,omitempty"`
+	// Mirrors are download sites tried in order when a download fails.
+	Mirrors []string `yaml:"mirrors,omitempty"`
+	// ListingBaseURL replaces https://rss.arxiv.org/rss for daily listings.
+	ListingBaseURL string `yaml:"listing_base_url,omitempty"`
+	// Proxy is an HTTP(S) proxy URL; by default the environment's
+	// HTTPS_PROXY and friends are used.
+	Proxy string `yaml:"proxy,omitempty"`
+	// CAFile is a PEM file of certificates trusted in addition to the
+	// system's, for example a corporate proxy's.
+	CAFile string `yaml:"ca_file,omitempty"`
+	// ConnectTimeout and ResponseTimeout bound each attempt of a request.
+	ConnectTimeout  time.Duration `yaml:"connect_timeout,omitempty"`
+	ResponseTimeout time.Duration `yaml:"response_timeout,omitempty"`
+}
+
+// Fetch configures fetching papers.
+type Fetch struct {
+	// Workers is how many PDFs are downloaded at once; 0 keeps the default
+	// of 4. Requests still start no faster than rate_limit allows.
+	Workers int `yaml:"workers,omitempty"`
+}
+
+// Path returns the settings file location for a research root.
+func Path(researchRoot string) string {
+	if p := os.Getenv(EnvPath); p != "" {
+		return p
+	}
+	return filepath.Join(researchRoot, FileName)
+}
+
+// Load reads the settings file for a research root. A missing file yields
+// the defaults.
+func Load(researchRoot string) (*Settings, error) {
+	path := Path(researchRoot)
+	s := &Settings{dir: filepath.Dir(path)}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read settings: %w", err)
+	}
+	if err := yaml.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// Resolve turns a path from the settings file into an absolute path. "~/" is
+// expanded to the home directory and relative paths are taken relative to
+// the directory holding the settings file.
+func (s *Settings) Resolve(path string) string {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	if filepath.IsAbs(path) || s.dir == "" {
+		return path
+	}
+	return filepath.Join(s.dir, path)
+}
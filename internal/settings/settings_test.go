@@ -0,0 +1,103 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package settings
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadMissingFile(t *testing.T) {
+	t.Setenv(EnvPath, "")
+	s, err := Load(t.TempDir())
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if s.Notes.Template != "" || len(s.Notes.Categories) != 0 {
+		t.Errorf("expected defaults, got %+v", s.Notes)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	t.Setenv(EnvPath, "")
+	root := t.TempDir()
+	content := `notes:
+  template: templates/notes.md
+  categories:
+    hep-th: templates/theory.md
+    cs.LG: /abs/ml.md
+`
+	if err := os.WriteFile(filepath.Join(root, FileName), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := Load(root)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := s.Resolve(s.Notes.Template); got != filepath.Join(root, "templates", "notes.md") {
+		t.Errorf("Resolve(template) = %q", got)
+	}
+	if got := s.Resolve(s.Notes.Categories["cs.LG"]); got != "/abs/ml.md" {
+		t.Errorf("Resolve(cs.LG) = %q", got)
+	}
+	if s.Notes.Categories["hep-th"] != "templates/theory.md" {
+		t.Errorf("categories = %v", s.Notes.Categories)
+	}
+}
+
+func TestLoadNetwork(t *testing.T) {
+	t.Setenv(EnvPath, "")
+	root := t.TempDir()
+	content := "rate_limit:\n  interval: 5s\n  max_retries: -1\n  max_wait: 1m30s\ncache:\n  ttl:\n    search: 10m\n" +
+		"client:\n  mirrors: [https://a.example, https://b.example]\n  response_timeout: 2m\n"
+	if err := os.WriteFile(filepath.Join(root, FileName), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := Load(root)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	want := RateLimit{Interval: 5 * time.Second, MaxRetries: -1, MaxWait: 90 * time.Second}
+	if s.RateLimit != want {
+		t.Errorf("rate limit = %+v, want %+v", s.RateLimit, want)
+	}
+	if s.Cache.TTL["search"] != 10*time.Minute {
+		t.Errorf("cache TTL = %v", s.Cache.TTL)
+	}
+	if len(s.Client.Mirrors) != 2 || s.Client.ResponseTimeout != 2*time.Minute {
+		t.Errorf("client = %+v", s.Client)
+	}
+}
+
+func TestLoadEnvOverride(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "custom.yaml")
+	if err := os.WriteFile(path, []byte("notes:\n  template: n.md\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(EnvPath, path)
+
+	s, err := Load(t.TempDir())
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := s.Resolve(s.Notes.Template); got != filepath.Join(dir, "n.md") {
+		t.Errorf("Resolve = %q", got)
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	t.Setenv(EnvPath, "")
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, FileName), []byte("notes: [\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(root); err == nil {
+		t.Error("expected parse error")
+	}
+}
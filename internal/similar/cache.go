@@ -0,0 +1,78 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package similar
+
+import (
+	"bytes"
+	"encoding/gob"
+	"os"
+	"path/filepath"
+
+	"github.com/mtreilly/arc-arxiv/internal/fsutil"
+)
+
+// cacheVersion changes whenever tokenization, weighting or the cache layout
+// changes, invalidating every cached vector.
+const cacheVersion = 1
+
+// Stamp identifies the version of a file by size and modification time in
+// nanoseconds. The zero Stamp stands for a missing file.
+type Stamp struct {
+	Size    int64
+	ModTime int64
+}
+
+// StampFile returns the Stamp of path, or the zero Stamp if it does not exist.
+func StampFile(path string) Stamp {
+	info, err := os.Stat(path)
+	if err != nil {
+		return Stamp{}
+	}
+	return Stamp{Size: info.Size(), ModTime: info.ModTime().UnixNano()}
+}
+
+// Entry holds the vectors of one paper, valid while its meta.yaml and
+// body.md have the recorded stamps. Meta covers the title and abstract.
+type Entry struct {
+	ID        string
+	Title     string
+	MetaStamp Stamp
+	BodyStamp Stamp
+	Meta      Vector
+	Body      Vector
+}
+
+// Cache holds the vectors of every paper in the library, keyed by paper
+// directory name.
+type Cache struct {
+	Version int
+	Entries map[string]*Entry
+}
+
+// LoadCache reads the cache at path. A missing, unreadable or outdated cache
+// yields an empty one, since it can always be rebuilt.
+func LoadCache(path string) *Cache {
+	empty := &Cache{Version: cacheVersion, Entries: make(map[string]*Entry)}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return empty
+	}
+	var c Cache
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&c); err != nil || c.Version != cacheVersion || c.Entries == nil {
+		return empty
+	}
+	return &c
+}
+
+// Save writes the cache to path, creating its directory.
+func (c *Cache) Save(path string) error {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(c); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return fsutil.WriteFile(path, buf.Bytes(), 0o644)
+}
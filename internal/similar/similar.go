@@ -0,0 +1,250 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+// Package similar ranks library papers by how much their text resembles a
+// paper or a piece of free text.
+//
+// Each paper is a bag of words weighted by sublinear term frequency per
+// field (title words count for more than abstract words, and those for more
+// than words of the extracted body). Weights are scaled by inverse document
+// frequency over the library at query time and papers are ranked by cosine
+// similarity. Term frequencies do not depend on the rest of the library, so
+// they can be cached per paper; see Cache.
+package similar
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/mtreilly/arc-arxiv/internal/fulltext"
+)
+
+// Field weights applied to the term frequencies of each part of a paper.
+const (
+	TitleWeight    = 2.0
+	AbstractWeight = 1.0
+	BodyWeight     = 0.5
+)
+
+// Vector maps terms to weights.
+type Vector map[string]float64
+
+// Add adds weight times the sublinear frequency (1 + ln tf) of every term
+// of text to v.
+func (v Vector) Add(text string, weight float64) {
+	for term, tf := range termCounts(text) {
+		v[term] += weight * (1 + math.Log(float64(tf)))
+	}
+}
+
+// Merge returns a new vector holding the sum of vs.
+func Merge(vs ...Vector) Vector {
+	out := make(Vector)
+	for _, v := range vs {
+		for term, w := range v {
+			out[term] += w
+		}
+	}
+	return out
+}
+
+// MetaVector returns the vector of a paper's title and abstract.
+func MetaVector(title, abstract string) Vector {
+	v := make(Vector)
+	v.Add(title, TitleWeight)
+	v.Add(abstract, AbstractWeight)
+	return v
+}
+
+// BodyVector returns the vector of a paper's extracted text.
+func BodyVector(body string) Vector {
+	v := make(Vector)
+	v.Add(body, BodyWeight)
+	return v
+}
+
+// TextVector returns the vector of a free-text query.
+func TextVector(text string) Vector {
+	v := make(Vector)
+	v.Add(text, AbstractWeight)
+	return v
+}
+
+// termCounts counts the terms of text: lowercase words without stop words,
+// numbers and single letters, with plural endings removed.
+func termCounts(text string) map[string]int {
+	counts := make(map[string]int)
+	for _, w := range fulltext.Words(text) {
+		if len(w) < 2 || stopWords[w] || isNumber(w) {
+			continue
+		}
+		counts[stem(w)]++
+	}
+	return counts
+}
+
+// stem strips a plural "s" so that "network" and "networks" are one term.
+// It is deliberately crude; anything more aggressive merges unrelated words.
+func stem(w string) string {
+	if len(w) <= 3 || !strings.HasSuffix(w, "s") {
+		return w
+	}
+	for _, keep := range []string{"ss", "us", "is", "as"} {
+		if strings.HasSuffix(w, keep) {
+			return w
+		}
+	}
+	if strings.HasSuffix(w, "ies") && len(w) > 4 {
+		return w[:len(w)-3] + "y"
+	}
+	return w[:len(w)-1]
+}
+
+func isNumber(w string) bool {
+	for _, r := range w {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+var stopWords = func() map[string]bool {
+	m := make(map[string]bool)
+	for _, w := range strings.Fields(`
+		a about above after again against all also among an and any are as at
+		be because been before being below between both but by can could did do
+		does doing down during each few for from further had has have having he
+		her here hers him his how however i if in into is it its itself just
+		more most much must my no nor not now of off on once one only or other
+		our ours out over own paper same she should show shows shown so some
+		such than that the their theirs them then there these they this those
+		through thus to too two under until up upon use used uses using very
+		via was we were what when where which while who whom why will with
+		within without would you your propose proposed present results
+		approach method methods based new et al`) {
+		m[w] = true
+	}
+	return m
+}()
+
+// Doc is a library paper and its term vector.
+type Doc struct {
+	ID     string
+	Vector Vector
+}
+
+// Result is a ranked paper. Terms are the shared terms that contribute most
+// to the score.
+type Result struct {
+	ID    string   `json:"id"`
+	Score float64  `json:"score"`
+	Terms []string `json:"terms"`
+}
+
+// Corpus is a set of documents with their document frequencies.
+type Corpus struct {
+	docs  []Doc
+	idf   map[string]float64
+	norms []float64
+}
+
+// NewCorpus computes the inverse document frequencies of docs and the norm
+// of each weighted document vector.
+func NewCorpus(docs []Doc) *Corpus {
+	df := make(map[string]int)
+	for _, d := range docs {
+		for term := range d.Vector {
+			df[term]++
+		}
+	}
+	c := &Corpus{docs: docs, idf: make(map[string]float64, len(df)), norms: make([]float64, len(docs))}
+	n := float64(len(docs))
+	for term, f := range df {
+		c.idf[term] = math.Log(1 + n/float64(f))
+	}
+	for i, d := range docs {
+		c.norms[i] = c.norm(d.Vector)
+	}
+	return c
+}
+
+// Len returns the number of documents.
+func (c *Corpus) Len() int {
+	return len(c.docs)
+}
+
+func (c *Corpus) norm(v Vector) float64 {
+	var sum float64
+	for term, w := range v {
+		x := w * c.idf[term]
+		sum += x * x
+	}
+	return math.Sqrt(sum)
+}
+
+// maxTerms is the number of shared terms reported per result.
+const maxTerms = 5
+
+// Rank returns up to limit documents most similar to query, best first.
+// Documents with nothing in common with the query and the document with ID
+// exclude are left out. A limit of zero returns every match. Terms unknown
+// to the corpus carry no weight.
+func (c *Corpus) Rank(query Vector, exclude string, limit int) []Result {
+	qnorm := c.norm(query)
+	if qnorm == 0 {
+		return nil
+	}
+
+	type contribution struct {
+		term  string
+		value float64
+	}
+	var results []Result
+	for i, d := range c.docs {
+		if d.ID == exclude || c.norms[i] == 0 {
+			continue
+		}
+		var dot float64
+		var shared []contribution
+		for term, qw := range query {
+			dw, ok := d.Vector[term]
+			if !ok {
+				continue
+			}
+			idf := c.idf[term]
+			x := qw * idf * dw * idf
+			dot += x
+			shared = append(shared, contribution{term, x})
+		}
+		if dot == 0 {
+			continue
+		}
+		sort.Slice(shared, func(a, b int) bool {
+			if shared[a].value != shared[b].value {
+				return shared[a].value > shared[b].value
+			}
+			return shared[a].term < shared[b].term
+		})
+		var terms []string
+		for _, s := range shared {
+			if len(terms) == maxTerms {
+				break
+			}
+			terms = append(terms, s.term)
+		}
+		results = append(results, Result{ID: d.ID, Score: dot / (qnorm * c.norms[i]), Terms: terms})
+	}
+
+	sort.Slice(results, func(a, b int) bool {
+		if results[a].Score != results[b].Score {
+			return results[a].Score > results[b].Score
+		}
+		return results[a].ID < results[b].ID
+	})
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package similar
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestTermCounts(t *testing.T) {
+	got := termCounts("The Networks of networks: 2017 studies in attention, a bias.")
+	want := map[string]int{"network": 2, "study": 1, "attention": 1, "bias": 1}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("termCounts = %v, want %v", got, want)
+	}
+}
+
+func testCorpus() *Corpus {
+	return NewCorpus([]Doc{
+		{ID: "attention", Vector: MetaVector("Attention Is All You Need", "The Transformer relies entirely on attention mechanisms for sequence transduction.")},
+		{ID: "bert", Vector: MetaVector("BERT: Pre-training of Deep Bidirectional Transformers", "Language representation with transformer encoders and masked language modeling.")},
+		{ID: "resnet", Vector: MetaVector("Deep Residual Learning for Image Recognition", "Residual networks ease the training of very deep convolutional networks.")},
+		{ID: "gravity", Vector: MetaVector("Loop Quantum Gravity", "Spin networks and quantized geometry.")},
+	})
+}
+
+func TestRank(t *testing.T) {
+	c := testCorpus()
+
+	results := c.Rank(TextVector("transformer attention for sequence modeling"), "", 0)
+	if len(results) < 2 || results[0].ID != "attention" || results[1].ID != "bert" {
+		t.Fatalf("results = %+v", results)
+	}
+	for _, r := range results {
+		if r.ID == "gravity" {
+			t.Errorf("unrelated paper ranked: %+v", r)
+		}
+		if r.Score <= 0 || r.Score > 1.0000001 {
+			t.Errorf("score out of range: %+v", r)
+		}
+	}
+	if results[0].Terms[0] != "attention" {
+		t.Errorf("top shared terms = %v", results[0].Terms)
+	}
+
+	// Querying by a paper leaves the paper itself out.
+	results = c.Rank(MetaVector("Deep Residual Learning for Image Recognition", "Residual networks ease the training of very deep convolutional networks."), "resnet", 1)
+	if len(results) != 1 || results[0].ID == "resnet" {
+		t.Errorf("results = %+v", results)
+	}
+
+	if got := c.Rank(TextVector("the of and"), "", 0); got != nil {
+		t.Errorf("stop-word query returned %+v", got)
+	}
+	if got := c.Rank(TextVector("zeolite"), "", 0); len(got) != 0 {
+		t.Errorf("unknown term returned %+v", got)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	got := Merge(Vector{"a": 1, "b": 2}, Vector{"b": 0.5})
+	if !reflect.DeepEqual(got, Vector{"a": 1, "b": 2.5}) {
+		t.Errorf("Merge = %v", got)
+	}
+}
+
+func TestCacheRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cache", "similar.gob")
+
+	c := LoadCache(path)
+	if len(c.Entries) != 0 {
+		t.Fatalf("missing cache has entries: %+v", c.Entries)
+	}
+	meta := filepath.Join(dir, "meta.yaml")
+	if err := os.WriteFile(meta, []byte("title: x\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	c.Entries["1706.03762"] = &Entry{ID: "1706.03762", Title: "Attention", MetaStamp: StampFile(meta), Meta: Vector{"attention": 2}}
+	if err := c.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	back := LoadCache(path)
+	e := back.Entries["1706.03762"]
+	if e == nil || e.MetaStamp != StampFile(meta) || e.BodyStamp != (Stamp{}) || e.Meta["attention"] != 2 {
+		t.Errorf("entry = %+v", e)
+	}
+
+	if err := os.WriteFile(path, []byte("garbage"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if len(LoadCache(path).Entries) != 0 {
+		t.Error("corrupt cache not discarded")
+	}
+}
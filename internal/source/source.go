@@ -0,0 +1,149 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+// Package source unpacks arXiv e-prints and finds their way around the
+// LaTeX inside.
+//
+// arXiv serves a paper's source as a gzipped tar archive, a single gzipped
+// file (one .tex file), or occasionally a plain tar or a PDF for papers
+// submitted without source. Unpack handles all of these; MainFile picks the
+// top-level .tex file and Flatten inlines its \input and \include files.
+package source
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ErrPDFOnly is returned by Unpack when the e-print is a PDF, meaning the
+// paper was submitted without source.
+var ErrPDFOnly = errors.New("paper was submitted as PDF only; no source available")
+
+// singleFileName is used for an e-print that is a single gzipped file.
+const singleFileName = "main.tex"
+
+// maxFileSize guards against decompression bombs.
+const maxFileSize = 512 << 20
+
+// Unpack extracts the e-print at archivePath into destDir, which is created
+// if needed, and returns the relative paths of the files written.
+func Unpack(archivePath, destDir string) ([]string, error) {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	br := bufio.NewReader(f)
+	var r io.Reader = br
+	if magic, _ := br.Peek(2); bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("read gzip: %w", err)
+		}
+		defer func() { _ = gz.Close() }()
+		r = gz
+	}
+
+	content := bufio.NewReaderSize(r, 1024)
+	head, _ := content.Peek(512)
+	if err := os.MkdirAll(destDir, 0o755); err != nil {
+		return nil, err
+	}
+	switch {
+	case isTar(head):
+		return untar(content, destDir)
+	case bytes.HasPrefix(head, []byte("%PDF-")):
+		return nil, ErrPDFOnly
+	case len(head) == 0:
+		return nil, fmt.Errorf("e-print is empty")
+	}
+	if err := writeFile(filepath.Join(destDir, singleFileName), content, 0o644); err != nil {
+		return nil, err
+	}
+	return []string{singleFileName}, nil
+}
+
+// isTar reports whether head starts a tar archive: "ustar" at offset 257,
+// or a valid header checksum for old-style archives.
+func isTar(head []byte) bool {
+	if len(head) < 512 {
+		return false
+	}
+	if bytes.HasPrefix(head[257:], []byte("ustar")) {
+		return true
+	}
+	_, err := tar.NewReader(bytes.NewReader(head)).Next()
+	return err == nil || err == io.ErrUnexpectedEOF
+}
+
+func untar(r io.Reader, destDir string) ([]string, error) {
+	var files []string
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return files, nil
+		}
+		if err != nil {
+			return files, fmt.Errorf("read tar: %w", err)
+		}
+
+		rel, ok := safePath(hdr.Name)
+		if !ok {
+			continue
+		}
+		target := filepath.Join(destDir, filepath.FromSlash(rel))
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return files, err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return files, err
+			}
+			if err := writeFile(target, tr, 0o644); err != nil {
+				return files, err
+			}
+			files = append(files, rel)
+		}
+		// Links and special files are skipped: a link could point outside
+		// the source directory.
+	}
+}
+
+// safePath cleans an archive member name and rejects names that would land
+// outside the destination directory.
+func safePath(name string) (string, bool) {
+	clean := path.Clean("/" + strings.ReplaceAll(name, "\\", "/"))
+	rel := strings.TrimPrefix(clean, "/")
+	if rel == "" || rel == "." {
+		return "", false
+	}
+	return rel, true
+}
+
+func writeFile(path string, r io.Reader, perm os.FileMode) error {
+	out, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	n, err := io.Copy(out, io.LimitReader(r, maxFileSize+1))
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil && n > maxFileSize {
+		err = fmt.Errorf("%s exceeds %d MB", filepath.Base(path), maxFileSize>>20)
+	}
+	return err
+}
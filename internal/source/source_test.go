@@ -0,0 +1,239 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package source
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func tarball(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(files[name])), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		tw.Write([]byte(files[name]))
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func gzipped(data []byte) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	gz.Write(data)
+	gz.Close()
+	return buf.Bytes()
+}
+
+func writeArchive(t *testing.T, data []byte) string {
+	t.Helper()
+	p := filepath.Join(t.TempDir(), "eprint")
+	if err := os.WriteFile(p, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func readFile(t *testing.T, p string) string {
+	t.Helper()
+	data, err := os.ReadFile(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestUnpack(t *testing.T) {
+	files := map[string]string{
+		"main.tex":         `\documentclass{article}`,
+		"figs/plot.pdf":    "%PDF-1.4",
+		"../../escape.tex": "nope",
+	}
+
+	t.Run("gzipped tar", func(t *testing.T) {
+		dest := t.TempDir()
+		got, err := Unpack(writeArchive(t, gzipped(tarball(t, files))), dest)
+		if err != nil {
+			t.Fatalf("Unpack: %v", err)
+		}
+		sort.Strings(got)
+		if strings.Join(got, ",") != "escape.tex,figs/plot.pdf,main.tex" {
+			t.Errorf("files = %v", got)
+		}
+		if readFile(t, filepath.Join(dest, "figs", "plot.pdf")) != "%PDF-1.4" {
+			t.Error("figs/plot.pdf not extracted")
+		}
+		if _, err := os.Stat(filepath.Join(filepath.Dir(dest), "escape.tex")); !os.IsNotExist(err) {
+			t.Error("archive member escaped the destination directory")
+		}
+	})
+
+	t.Run("plain tar", func(t *testing.T) {
+		dest := t.TempDir()
+		if _, err := Unpack(writeArchive(t, tarball(t, files)), dest); err != nil {
+			t.Fatalf("Unpack: %v", err)
+		}
+		if readFile(t, filepath.Join(dest, "main.tex")) != `\documentclass{article}` {
+			t.Error("main.tex not extracted")
+		}
+	})
+
+	t.Run("single gzipped file", func(t *testing.T) {
+		dest := t.TempDir()
+		got, err := Unpack(writeArchive(t, gzipped([]byte(`\documentclass{revtex4}`))), dest)
+		if err != nil {
+			t.Fatalf("Unpack: %v", err)
+		}
+		if len(got) != 1 || got[0] != "main.tex" {
+			t.Errorf("files = %v", got)
+		}
+		if readFile(t, filepath.Join(dest, "main.tex")) != `\documentclass{revtex4}` {
+			t.Error("single file not written as main.tex")
+		}
+	})
+
+	t.Run("PDF only", func(t *testing.T) {
+		_, err := Unpack(writeArchive(t, []byte("%PDF-1.5\n...")), t.TempDir())
+		if !errors.Is(err, ErrPDFOnly) {
+			t.Errorf("err = %v, want ErrPDFOnly", err)
+		}
+	})
+}
+
+func writeTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestMainFile(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{"single candidate", map[string]string{
+			"paper.tex": "\\documentclass{article}\n\\begin{document}\\end{document}",
+			"intro.tex": "\\section{Intro}",
+		}, "paper.tex"},
+		{"commented documentclass ignored", map[string]string{
+			"old.tex":  "% \\documentclass{article}\n\\begin{document}",
+			"real.tex": "\\documentclass{article}\n\\begin{document}",
+		}, "real.tex"},
+		{"document body beats preamble-only", map[string]string{
+			"cover.tex": "\\documentclass{article}",
+			"z.tex":     "\\documentclass{article}\n\\begin{document}",
+		}, "z.tex"},
+		{"conventional name breaks ties", map[string]string{
+			"supplement.tex": "\\documentclass{article}\n\\begin{document} long long long",
+			"main.tex":       "\\documentclass{article}\n\\begin{document}",
+		}, "main.tex"},
+		{"flattened copy ignored", map[string]string{
+			"main.tex":      "\\documentclass{article}\n\\begin{document}",
+			"main.flat.tex": "\\documentclass{article}\n\\begin{document} and much more",
+		}, "main.tex"},
+		{"00README.json wins", map[string]string{
+			"main.tex":      "\\documentclass{article}\n\\begin{document}",
+			"src/ms.tex":    "\\documentclass{article}",
+			"00README.json": `{"sources":[{"filename":"src/ms.tex","usage":"toplevel"}]}`,
+		}, "src/ms.tex"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MainFile(writeTree(t, tt.files))
+			if err != nil {
+				t.Fatalf("MainFile: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("MainFile = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := MainFile(writeTree(t, map[string]string{"notes.txt": "hi"})); err == nil {
+		t.Error("expected error without .tex files")
+	}
+}
+
+func TestFlatten(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"main.tex": strings.Join([]string{
+			`\documentclass{article}`,
+			`\begin{document}`,
+			`\input{sections/intro}`,
+			`% \input{sections/draft}`,
+			`\include{appendix}`,
+			`\input missing`,
+			`\input{loop} 50\% done`,
+			`\end{document}`,
+			``,
+		}, "\n"),
+		"sections/intro.tex":  "Intro text.\n\\input{sections/detail.tex}\n",
+		"sections/detail.tex": "Detail.",
+		"appendix.tex":        "Appendix.\n",
+		"loop.tex":            "Loop \\input{loop}\n",
+	})
+
+	got, err := Flatten(dir, "main.tex")
+	if err != nil {
+		t.Fatalf("Flatten: %v", err)
+	}
+	want := strings.Join([]string{
+		`\documentclass{article}`,
+		`\begin{document}`,
+		`% arc-arxiv: begin sections/intro.tex`,
+		`Intro text.`,
+		`% arc-arxiv: begin sections/detail.tex`,
+		`Detail.`,
+		`% arc-arxiv: end sections/detail.tex`,
+		``,
+		`% arc-arxiv: end sections/intro.tex`,
+		``,
+		`% \input{sections/draft}`,
+		`% arc-arxiv: begin appendix.tex`,
+		`\clearpage`,
+		`Appendix.`,
+		`% arc-arxiv: end appendix.tex`,
+		``,
+		`\input missing% arc-arxiv: missing not found`,
+		``,
+		`% arc-arxiv: begin loop.tex`,
+		`Loop \input{loop}% arc-arxiv: not expanded (recursive input)`,
+		``,
+		`% arc-arxiv: end loop.tex`,
+		` 50\% done`,
+		`\end{document}`,
+		``,
+	}, "\n")
+	if got != want {
+		t.Errorf("Flatten =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,228 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package source
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// preferredMainNames break ties between several candidate main files.
+var preferredMainNames = []string{"main.tex", "ms.tex", "paper.tex", "article.tex"}
+
+// FlatName returns the name Flatten's output is saved under: "main.tex"
+// becomes "main.flat.tex", in the same directory.
+func FlatName(main string) string {
+	ext := path.Ext(main)
+	return strings.TrimSuffix(main, ext) + ".flat" + ext
+}
+
+// MainFile returns the path, relative to dir, of the top-level .tex file.
+//
+// arXiv's 00README.json names it explicitly when present. Otherwise the
+// candidates are .tex files with an uncommented \documentclass; one that
+// also has \begin{document} wins, then a conventional name, then the
+// largest file. Flattened copies written by FlatName are never chosen.
+func MainFile(dir string) (string, error) {
+	if main := readmeTopLevel(dir); main != "" {
+		return main, nil
+	}
+
+	type candidate struct {
+		rel      string
+		document bool
+		size     int
+	}
+	var candidates []candidate
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.EqualFold(filepath.Ext(p), ".tex") || strings.HasSuffix(p, ".flat.tex") {
+			return err
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return nil
+		}
+		text := stripComments(string(data))
+		if !strings.Contains(text, `\documentclass`) && !strings.Contains(text, `\documentstyle`) {
+			return nil
+		}
+		rel, _ := filepath.Rel(dir, p)
+		candidates = append(candidates, candidate{
+			rel:      filepath.ToSlash(rel),
+			document: strings.Contains(text, `\begin{document}`),
+			size:     len(data),
+		})
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("no main .tex file found")
+	}
+
+	rank := func(c candidate) int {
+		for i, n := range preferredMainNames {
+			if strings.EqualFold(c.rel, n) {
+				return i
+			}
+		}
+		return len(preferredMainNames)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.document != b.document {
+			return a.document
+		}
+		if ra, rb := rank(a), rank(b); ra != rb {
+			return ra < rb
+		}
+		if a.size != b.size {
+			return a.size > b.size
+		}
+		return a.rel < b.rel
+	})
+	return candidates[0].rel, nil
+}
+
+// readmeTopLevel reads the top-level file from 00README.json, if any.
+func readmeTopLevel(dir string) string {
+	data, err := os.ReadFile(filepath.Join(dir, "00README.json"))
+	if err != nil {
+		return ""
+	}
+	var readme struct {
+		Sources []struct {
+			Filename string `json:"filename"`
+			Usage    string `json:"usage"`
+		} `json:"sources"`
+	}
+	if json.Unmarshal(data, &readme) != nil {
+		return ""
+	}
+	for _, s := range readme.Sources {
+		if s.Usage != "toplevel" {
+			continue
+		}
+		if rel, ok := safePath(s.Filename); ok {
+			if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(rel))); err == nil {
+				return rel
+			}
+		}
+	}
+	return ""
+}
+
+// commentPattern matches a TeX comment: a % not escaped by a backslash,
+// through the end of the line.
+var commentPattern = regexp.MustCompile(`(?m)(^|[^\\])%.*$`)
+
+func stripComments(text string) string {
+	return commentPattern.ReplaceAllString(text, "$1")
+}
+
+// inputPattern matches \input{file}, \include{file}, \subfile{file} and the
+// brace-less \input file.
+var inputPattern = regexp.MustCompile(`\\(input|include|subfile)\s*(?:\{([^}]+)\}|\s([^\s{}\\%]+))`)
+
+// maxInputDepth bounds nested \input files.
+const maxInputDepth = 16
+
+// Flatten returns the main file at dir/main with every \input, \include and
+// \subfile replaced by the contents of the file it names, recursively.
+// Paths are resolved against dir, as TeX does when run there. Commented-out
+// commands are left alone, and files that cannot be found are kept as a
+// command with a comment noting the problem.
+func Flatten(dir, main string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(main)))
+	if err != nil {
+		return "", err
+	}
+	return flatten(dir, string(data), map[string]bool{main: true}, 0), nil
+}
+
+func flatten(dir, text string, active map[string]bool, depth int) string {
+	var b strings.Builder
+	for _, line := range strings.SplitAfter(text, "\n") {
+		code, comment := splitComment(line)
+		last := 0
+		for _, m := range inputPattern.FindAllStringSubmatchIndex(code, -1) {
+			b.WriteString(code[last:m[0]])
+			last = m[1]
+
+			command := code[m[2]:m[3]]
+			var arg string
+			if m[4] >= 0 {
+				arg = code[m[4]:m[5]]
+			} else {
+				arg = code[m[6]:m[7]]
+			}
+			rel, ok := resolveInput(dir, strings.TrimSpace(arg))
+			switch {
+			case !ok:
+				fmt.Fprintf(&b, "%s%% arc-arxiv: %s not found\n", code[m[0]:m[1]], arg)
+			case active[rel] || depth >= maxInputDepth:
+				fmt.Fprintf(&b, "%s%% arc-arxiv: not expanded (recursive input)\n", code[m[0]:m[1]])
+			default:
+				data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(rel)))
+				if err != nil {
+					fmt.Fprintf(&b, "%s%% arc-arxiv: %v\n", code[m[0]:m[1]], err)
+					continue
+				}
+				active[rel] = true
+				inner := flatten(dir, string(data), active, depth+1)
+				delete(active, rel)
+
+				fmt.Fprintf(&b, "%% arc-arxiv: begin %s\n", rel)
+				if command == "include" {
+					b.WriteString("\\clearpage\n")
+				}
+				b.WriteString(inner)
+				if !strings.HasSuffix(inner, "\n") {
+					b.WriteString("\n")
+				}
+				fmt.Fprintf(&b, "%% arc-arxiv: end %s\n", rel)
+			}
+		}
+		b.WriteString(code[last:])
+		b.WriteString(comment)
+	}
+	return b.String()
+}
+
+// splitComment splits a line into code and its trailing comment, if any.
+func splitComment(line string) (string, string) {
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '%':
+			return line[:i], line[i:]
+		}
+	}
+	return line, ""
+}
+
+// resolveInput finds the file an \input argument names, trying a .tex
+// extension as TeX does.
+func resolveInput(dir, arg string) (string, bool) {
+	rel, ok := safePath(arg)
+	if !ok {
+		return "", false
+	}
+	for _, candidate := range []string{rel + ".tex", rel} {
+		info, err := os.Stat(filepath.Join(dir, filepath.FromSlash(candidate)))
+		if err == nil && info.Mode().IsRegular() {
+			return candidate, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,192 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+// Package watch keeps named arXiv searches and remembers which results of
+// each have already been reported.
+//
+// Saved searches and their state live together in
+// <research_root>/watches.yaml. A search remembers the IDs of every paper it
+// has reported, so re-running it only surfaces papers that are new since the
+// previous run, and a paper is never reported twice by the same search. The
+// metadata of the most recently reported papers is kept as well, so that
+// they can be listed (for example as a feed) without searching again.
+package watch
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/mtreilly/arc-arxiv/internal/arxiv"
+	"github.com/mtreilly/arc-arxiv/internal/fsutil"
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the name of the store inside the research root.
+const FileName = "watches.yaml"
+
+// DefaultMaxResults is the number of newest results a search asks for when
+// none is configured.
+const DefaultMaxResults = 50
+
+// MaxRecent is the number of reported papers whose metadata a search keeps.
+const MaxRecent = 100
+
+// Search is a saved search and its state. The query fields mirror
+// arxiv.SearchOptions; results are always requested newest first.
+type Search struct {
+	Name       string `yaml:"name" json:"name"`
+	Query      string `yaml:"query,omitempty" json:"query,omitempty"`
+	Author     string `yaml:"author,omitempty" json:"author,omitempty"`
+	Title      string `yaml:"title,omitempty" json:"title,omitempty"`
+	Abstract   string `yaml:"abstract,omitempty" json:"abstract,omitempty"`
+	Category   string `yaml:"category,omitempty" json:"category,omitempty"`
+	MaxResults int    `yaml:"max_results,omitempty" json:"max_results,omitempty"`
+	// Fetch downloads new papers whenever the search runs.
+	Fetch bool `yaml:"fetch,omitempty" json:"fetch,omitempty"`
+
+	CreatedAt string `yaml:"created_at" json:"created_at"`
+	LastRun   string `yaml:"last_run,omitempty" json:"last_run,omitempty"`
+	// Seen holds the base IDs of every paper the search has reported.
+	Seen []string `yaml:"seen,omitempty" json:"-"`
+	// Recent holds the papers reported most recently, newest report first.
+	// Their FetchedAt is the time they were reported.
+	Recent []*arxiv.ArxivMeta `yaml:"recent,omitempty" json:"-"`
+}
+
+// Options returns the arXiv search options of s.
+func (s *Search) Options() *arxiv.SearchOptions {
+	max := s.MaxResults
+	if max <= 0 {
+		max = DefaultMaxResults
+	}
+	return &arxiv.SearchOptions{
+		Author:     s.Author,
+		Title:      s.Title,
+		Abstract:   s.Abstract,
+		Category:   s.Category,
+		MaxResults: max,
+		SortBy:     "submitted",
+	}
+}
+
+// Empty reports whether s has no search criteria.
+func (s *Search) Empty() bool {
+	return s.Query == "" && s.Author == "" && s.Title == "" && s.Abstract == "" && s.Category == ""
+}
+
+// Record marks results as seen and returns those not seen before, in the
+// order given, adding them to Recent. Versions are ignored: a revised paper
+// is not new.
+func (s *Search) Record(results []*arxiv.ArxivMeta, now time.Time) []*arxiv.ArxivMeta {
+	seen := make(map[string]bool, len(s.Seen))
+	for _, id := range s.Seen {
+		seen[id] = true
+	}
+	var fresh []*arxiv.ArxivMeta
+	for _, m := range results {
+		id, _ := arxiv.SplitVersion(m.ArxivID)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		s.Seen = append(s.Seen, id)
+		fresh = append(fresh, m)
+	}
+	s.LastRun = now.UTC().Format(time.RFC3339)
+
+	recent := make([]*arxiv.ArxivMeta, 0, len(fresh)+len(s.Recent))
+	for _, m := range fresh {
+		r := *m
+		r.FetchedAt = s.LastRun
+		recent = append(recent, &r)
+	}
+	recent = append(recent, s.Recent...)
+	if len(recent) > MaxRecent {
+		recent = recent[:MaxRecent]
+	}
+	s.Recent = recent
+	return fresh
+}
+
+// Store is the set of saved searches, sorted by name.
+type Store struct {
+	Searches []*Search `yaml:"searches"`
+
+	path string
+}
+
+// Path returns the store location for a research root.
+func Path(researchRoot string) string {
+	return filepath.Join(researchRoot, FileName)
+}
+
+// Load reads the store at path. A missing file yields an empty store.
+func Load(path string) (*Store, error) {
+	st := &Store{path: path}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return st, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read saved searches: %w", err)
+	}
+	if err := yaml.Unmarshal(data, st); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return st, nil
+}
+
+// Save writes the store back to the file it was loaded from.
+func (st *Store) Save() error {
+	sort.Slice(st.Searches, func(i, j int) bool { return st.Searches[i].Name < st.Searches[j].Name })
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(st); err != nil {
+		return err
+	}
+	return fsutil.WriteFile(st.path, buf.Bytes(), 0o644)
+}
+
+// Get returns the search called name, or nil.
+func (st *Store) Get(name string) *Search {
+	for _, s := range st.Searches {
+		if s.Name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Add saves a new search. Names are letters, digits, '.', '_' and '-'.
+func (st *Store) Add(s *Search) error {
+	if !namePattern.MatchString(s.Name) {
+		return fmt.Errorf("invalid search name %q (use letters, digits, '.', '_' and '-')", s.Name)
+	}
+	if s.Empty() {
+		return fmt.Errorf("search %q has no query, author, title, abstract or category", s.Name)
+	}
+	if st.Get(s.Name) != nil {
+		return fmt.Errorf("saved search %q already exists", s.Name)
+	}
+	st.Searches = append(st.Searches, s)
+	return nil
+}
+
+// Remove deletes the search called name.
+func (st *Store) Remove(name string) error {
+	for i, s := range st.Searches {
+		if s.Name == name {
+			st.Searches = append(st.Searches[:i], st.Searches[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("no saved search named %q", name)
+}
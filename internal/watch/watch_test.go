@@ -0,0 +1,106 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package watch
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mtreilly/arc-arxiv/internal/arxiv"
+)
+
+func metas(ids ...string) []*arxiv.ArxivMeta {
+	out := make([]*arxiv.ArxivMeta, len(ids))
+	for i, id := range ids {
+		out[i] = &arxiv.ArxivMeta{ArxivID: id}
+	}
+	return out
+}
+
+func ids(ms []*arxiv.ArxivMeta) []string {
+	var out []string
+	for _, m := range ms {
+		out = append(out, m.ArxivID)
+	}
+	return out
+}
+
+func TestRecord(t *testing.T) {
+	s := &Search{Name: "x", Query: "attention"}
+	now := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	if got := ids(s.Record(metas("2503.00002", "2503.00001", "2503.00001"), now)); !reflect.DeepEqual(got, []string{"2503.00002", "2503.00001"}) {
+		t.Errorf("first run = %v", got)
+	}
+	if got := ids(s.Record(metas("2503.00003", "2503.00002v2", "2503.00001"), now)); !reflect.DeepEqual(got, []string{"2503.00003"}) {
+		t.Errorf("second run = %v", got)
+	}
+	if len(s.Seen) != 3 || s.LastRun != "2025-03-01T09:00:00Z" {
+		t.Errorf("state = %v, %s", s.Seen, s.LastRun)
+	}
+	if got := ids(s.Recent); !reflect.DeepEqual(got, []string{"2503.00003", "2503.00002", "2503.00001"}) || s.Recent[0].FetchedAt != s.LastRun {
+		t.Errorf("recent = %v", got)
+	}
+
+	for i := 0; i < MaxRecent; i++ {
+		s.Record(metas(fmt.Sprintf("2504.%05d", i)), now)
+	}
+	if len(s.Recent) != MaxRecent || s.Recent[0].ArxivID != fmt.Sprintf("2504.%05d", MaxRecent-1) {
+		t.Errorf("recent not capped: %d entries, first %s", len(s.Recent), s.Recent[0].ArxivID)
+	}
+}
+
+func TestOptions(t *testing.T) {
+	o := (&Search{Category: "cs.LG"}).Options()
+	if o.MaxResults != DefaultMaxResults || o.SortBy != "submitted" || o.Category != "cs.LG" {
+		t.Errorf("options = %+v", o)
+	}
+}
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	st, err := Load(path)
+	if err != nil || len(st.Searches) != 0 {
+		t.Fatalf("Load missing = %+v, %v", st, err)
+	}
+
+	for _, bad := range []*Search{
+		{Name: "has space", Query: "x"},
+		{Name: "", Query: "x"},
+		{Name: "empty"},
+	} {
+		if err := st.Add(bad); err == nil {
+			t.Errorf("Add(%+v) succeeded", bad)
+		}
+	}
+	if err := st.Add(&Search{Name: "zeta", Category: "hep-th"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.Add(&Search{Name: "alpha", Query: "attention", Fetch: true, Seen: []string{"2503.00001"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.Add(&Search{Name: "alpha", Query: "again"}); err == nil {
+		t.Error("duplicate name accepted")
+	}
+	if err := st.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	back, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(back.Searches) != 2 || back.Searches[0].Name != "alpha" || !back.Searches[0].Fetch || back.Get("alpha").Seen[0] != "2503.00001" {
+		t.Fatalf("reloaded = %+v", back.Searches)
+	}
+	if err := back.Remove("zeta"); err != nil || back.Get("zeta") != nil {
+		t.Errorf("Remove: %v", err)
+	}
+	if err := back.Remove("zeta"); err == nil {
+		t.Error("removing a missing search succeeded")
+	}
+}